@@ -25,7 +25,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Clients see only their addresses (active by default). Admins can pass ?all=1 to see all, and ?include_inactive=1 to include inactive.",
+                "description": "Clients see only their addresses (active by default). Staff with addresses.manage.all can pass ?all=1 to see all, ?customer_id to see one customer, and ?include_inactive=1 to include inactive.",
                 "produces": [
                     "application/json"
                 ],
@@ -36,13 +36,13 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Admin only: if set to 1, list all users' addresses",
+                        "description": "addresses.manage.all only: if set to 1, list all users' addresses",
                         "name": "all",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Admin only: if set to 1, include inactive addresses",
+                        "description": "addresses.manage.all only: if set to 1, include inactive addresses",
                         "name": "include_inactive",
                         "in": "query"
                     },
@@ -77,7 +77,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates coordinates first if provided, then address; CustomerID is set from JWT. Clients create their own; admins can also create for themselves only in this endpoint.",
+                "description": "Creates coordinates first if provided, then address; CustomerID is set from JWT. Clients create their own; staff can also create for themselves only in this endpoint.",
                 "consumes": [
                     "application/json"
                 ],
@@ -165,6 +165,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Returns the address with its version as ETag",
                 "produces": [
                     "application/json"
                 ],
@@ -208,6 +209,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Requires If-Match with the address ETag; the new ETag is returned.",
                 "consumes": [
                     "application/json"
                 ],
@@ -226,6 +228,13 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag of the address as read by the client",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Address update",
                         "name": "request",
@@ -300,6 +309,18 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "412": {
+                        "description": "Address changed since it was read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match required",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
@@ -309,7 +330,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes address only if it is not referenced by any order. Owner or admin only.",
+                "description": "Deletes address only if it is not referenced by any order. Owner or addresses.manage.all only.",
                 "tags": [
                     "addresses"
                 ],
@@ -355,6 +376,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Requires If-Match with the address ETag; the new ETag is returned.",
                 "consumes": [
                     "application/json"
                 ],
@@ -370,6 +392,13 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag of the address as read by the client",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Desired active state",
                         "name": "request",
@@ -406,13 +435,30 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "412": {
+                        "description": "Address changed since it was read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match required",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/login": {
+        "/admin/users": {
             "post": {
-                "description": "Authenticates user and returns JWT token",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an account with any role, e.g. operators or administrators (users.manage only), and emails a verification link. Roles other than client need a password of at least 12 characters. The creation is recorded in the audit log.",
                 "consumes": [
                     "application/json"
                 ],
@@ -420,38 +466,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "users"
                 ],
-                "summary": "Login endpoint",
+                "summary": "Create user with a role",
                 "parameters": [
                     {
-                        "description": "Login credentials",
+                        "description": "User details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "email": {
-                                    "type": "string"
-                                },
-                                "password": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.NewUserRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "JWT token",
+                    "201": {
+                        "description": "Created user",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "token": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
                         }
                     },
                     "400": {
@@ -461,13 +494,13 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Not authorized",
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -475,36 +508,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders": {
+        "/delivery-attempts/reasons": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Clients see only their orders. Admins can see all orders by passing ?all=1.",
+                "description": "Returns the catalog of reasons for a failed delivery attempt",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "delivery_attempts"
                 ],
-                "summary": "List orders",
-                "parameters": [
+                "summary": "Get failed delivery reasons",
+                "responses": {
+                    "200": {
+                        "description": "List of reasons",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "properties": {
+                                    "label": {
+                                        "type": "string"
+                                    },
+                                    "value": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery-stops": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "If set to 1 and requester is admin, returns all orders; otherwise returns only own orders",
-                        "name": "all",
-                        "in": "query"
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Requires deliveries.route. Orders in route with destination, recipient contact and delivery preferences, earliest preferred window first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "delivery_attempts"
+                ],
+                "summary": "Driver stop list",
                 "responses": {
                     "200": {
-                        "description": "List of orders with details",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderListItem"
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryStop"
                             }
                         }
                     },
@@ -527,41 +594,74 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/email/verification": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new order for authenticated user",
-                "consumes": [
-                    "application/json"
+                "description": "Sends a new verification link to the authenticated user; earlier links stop working",
+                "tags": [
+                    "auth"
                 ],
-                "produces": [
+                "summary": "Resend verification email",
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already verified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/verify": {
+            "post": {
+                "description": "Confirms the account email with the token from the verification email",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "auth"
                 ],
-                "summary": "Create new order",
+                "summary": "Verify email",
                 "parameters": [
                     {
-                        "description": "Order details",
-                        "name": "order",
+                        "description": "Verification token",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Order"
+                            "type": "object",
+                            "properties": {
+                                "token": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created order",
-                        "schema": {
-                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Order"
-                        }
+                    "204": {
+                        "description": "No content"
                     },
                     "400": {
                         "description": "Bad request",
@@ -569,14 +669,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                    "422": {
+                        "description": "Invalid, used or expired token",
                         "schema": {
                             "type": "string"
                         }
@@ -584,71 +678,106 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/status": {
+        "/erasure-requests": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns all available order status constants",
+                "description": "Returns the personal data erasure requests, oldest first (privacy.manage only)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "privacy"
+                ],
+                "summary": "List erasure requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, completed or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get available order statuses",
                 "responses": {
                     "200": {
-                        "description": "List of order status",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "object",
-                                "properties": {
-                                    "label": {
-                                        "type": "string"
-                                    },
-                                    "value": {
-                                        "type": "string"
-                                    }
-                                }
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.ErasureRequest"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/orders/{id}": {
-            "get": {
+        "/erasure-requests/{id}/approve": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns the detailed information of a specific order",
+                "description": "Erases the requester's personal data at once: the account is anonymized and deleted and their personal addresses lose street and number; orders and organization addresses are kept. Needs privacy.manage, and another administrator than the requester. Recorded in the audit log.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "privacy"
                 ],
-                "summary": "Get order detail by ID",
+                "summary": "Approve erasure request",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Erasure request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.reviewBody"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order detail",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderDetail"
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.ErasureRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "401": {
@@ -669,8 +798,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "409": {
+                        "description": "Already reviewed, last admin or last owner",
                         "schema": {
                             "type": "string"
                         }
@@ -678,47 +807,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}/status": {
-            "patch": {
+        "/erasure-requests/{id}/reject": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the status of an order (admin only)",
+                "description": "Rejects a pending request with a note explaining why (privacy.manage only). Recorded in the audit log.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "orders"
+                    "privacy"
                 ],
-                "summary": "Update order status",
+                "summary": "Reject erasure request",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Erasure request ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New status",
-                        "name": "status",
+                        "description": "Reason of the rejection",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "status": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/internal_delivery_http.reviewBody"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.ErasureRequest"
+                        }
                     },
                     "400": {
                         "description": "Bad request",
@@ -737,45 +867,93 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Already reviewed",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
-                }
-            }
-        },
-        "/package-types": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticates user and returns a short-lived JWT access token plus a refresh token. Accounts with MFA (mandatory for some roles) get an mfa_token instead, to complete at /login/mfa or, when enrollment_required, at /mfa/enroll and /mfa/confirm. Repeated failures lock the account or client IP for a growing period.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Returns package types. If ?all=1 and requester is admin, includes inactive; otherwise only active.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "package_types"
+                    "auth"
                 ],
-                "summary": "List package types",
+                "summary": "Login endpoint",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "If set to 1 and requester is admin, returns active and inactive",
-                        "name": "all",
-                        "in": "query"
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                },
+                                "password": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Access and refresh tokens",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageType"
-                            }
+                            "$ref": "#/definitions/internal_delivery_http.tokenResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Second factor required",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.mfaChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Not authorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Email not verified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see Retry-After",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "string"
                         }
@@ -783,48 +961,142 @@ const docTemplate = `{
                 }
             }
         },
-        "/package-types/{id}/active": {
-            "patch": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Admin only. Sets is_active true/false for a PackageType",
+        "/login/mfa": {
+            "post": {
+                "description": "Exchanges the mfa_token returned by /login and a TOTP or recovery code for access and refresh tokens",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "package_types"
+                    "auth"
                 ],
-                "summary": "Set PackageType active status",
+                "summary": "Complete login with second factor",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "PackageType ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Desired active state",
+                        "description": "Challenge token and TOTP or recovery code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "active": {
-                                    "type": "boolean"
+                                "code": {
+                                    "type": "string"
+                                },
+                                "mfa_token": {
+                                    "type": "string"
                                 }
                             }
                         }
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "Access and refresh tokens",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.tokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid challenge or code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "MFA enrollment required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, see Retry-After",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the current session: its refresh tokens stop working and the access token is rejected from now on",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout",
                 "responses": {
                     "204": {
                         "description": "No content"
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manifests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires manifests.manage. Filtered by station and day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "manifests"
+                ],
+                "summary": "List manifests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Station ID",
+                        "name": "station_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Manifest day (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.Manifest"
+                            }
+                        }
+                    },
                     "400": {
                         "description": "Bad request",
                         "schema": {
@@ -844,11 +1116,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users": {
+            },
             "post": {
-                "description": "Creates a new user account",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires manifests.manage. Builds a draft manifest with the orders that left the station on the date (outbound or load scans), optionally limited to the parcels loaded into a route.",
                 "consumes": [
                     "application/json"
                 ],
@@ -856,42 +1131,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "manifests"
                 ],
-                "summary": "Register new user",
+                "summary": "Create dispatch manifest",
                 "parameters": [
                     {
-                        "description": "User registration details",
+                        "description": "Manifest",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "email": {
-                                    "type": "string"
-                                },
-                                "full_name": {
-                                    "type": "string"
-                                },
-                                "password": {
-                                    "type": "string"
-                                },
-                                "phone": {
-                                    "type": "string"
-                                },
-                                "role": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.ManifestRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created user",
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Manifest"
                         }
                     },
                     "400": {
@@ -899,29 +1157,41 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/users/{id}": {
+        "/manifests/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns user details by ID. Only admin or the user themself can access.",
+                "description": "Requires manifests.manage. Manifest with its orders.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "manifests"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get manifest",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Manifest ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -929,9 +1199,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User details",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Manifest"
                         }
                     },
                     "401": {
@@ -953,30 +1223,45 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/manifests/{id}/document": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a user account (admin or own account only)",
+                "description": "Requires manifests.manage. Renders the manifest with pieces, weights, destinations and signature lines.",
+                "produces": [
+                    "application/pdf",
+                    "text/csv"
+                ],
                 "tags": [
-                    "users"
+                    "manifests"
                 ],
-                "summary": "Delete user",
+                "summary": "Get manifest document",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Manifest ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pdf (default) or csv",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content"
+                    "200": {
+                        "description": "Manifest document",
+                        "schema": {
+                            "type": "file"
+                        }
                     },
                     "400": {
                         "description": "Bad request",
@@ -995,100 +1280,4638 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manifests/{id}/issue": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires manifests.manage. Freezes the manifest and records its ID in the history of every listed order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "manifests"
+                ],
+                "summary": "Issue manifest",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Manifest ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Manifest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Manifest already issued",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/manifests/{id}/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires manifests.manage. Re-collects the orders of a draft manifest. Issued manifests are frozen (409).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "manifests"
+                ],
+                "summary": "Refresh draft manifest",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Manifest ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Manifest"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Manifest already issued",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/erasure-request": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancellation right (ARCO / GDPR): files a request, reviewed by an administrator, to anonymize the caller's account and personal addresses. Orders are kept for accounting. One request may be pending at a time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Request erasure of own personal data",
+                "parameters": [
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "reason": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.ErasureRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "A request is already pending",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Access right (ARCO / GDPR): returns a ZIP with the caller's profile, addresses, orders and their status history as JSON files. Each export is recorded in the audit log.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Export own personal data",
+                "responses": {
+                    "200": {
+                        "description": "ZIP archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enables MFA with a code from the authenticator app and returns 10 single-use recovery codes, shown only once. When called with an mfa_token the login is completed and tokens are included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm MFA enrollment",
+                "parameters": [
+                    {
+                        "description": "TOTP code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.mfaCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized or invalid code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "MFA already enabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turns MFA off after checking a current TOTP or recovery code. Not allowed for roles where MFA is mandatory.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Disable MFA",
+                "parameters": [
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized or invalid code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "MFA is mandatory for the role",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a TOTP secret and returns it with its otpauth URL and QR code. Accepts an access token or the mfa_token of a login that requires enrollment. MFA is active only after /mfa/confirm.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start MFA enrollment",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.mfaEnrollResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "MFA already enabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the recovery codes after checking a current TOTP or recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Regenerate MFA recovery codes",
+                "parameters": [
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.mfaCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized or invalid code",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clients see only their orders. Staff with orders.read.all can see all orders by passing ?all=1.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "If set to 1 and requester has orders.read.all, returns all orders; otherwise returns only own orders",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of orders with details",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderListItem"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new order for authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create new order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body replay the first response for 24h",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Order details; number, status, pickup and version are set by the server",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.OrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created order",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with the same Idempotency-Key is in progress or the order number is taken",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key reused with a different body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error, not cached for the Idempotency-Key",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Same scope as the order list: clients export their orders, orders.export.all can export all orders with ?all=1. CSV and XLSX are streamed row by row with the list columns, plus the detail columns with ?detail=1. PDF is a summary with totals by status and the first 500 orders.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Export orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default), xlsx or pdf",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "If set to 1 and requester has orders.export.all, exports all orders",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "If set to 1, adds the detail columns",
+                        "name": "detail",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates one order per row of a CSV or XLSX file (first sheet) for the requester, matching or creating the origin and destination addresses. Every row is validated with the order creation rules. With dry_run=1 nothing is stored. mode=atomic (default) stores all rows or none (422 if any fails); mode=row stores the valid rows and reports the failed ones.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Bulk import orders",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file with a header row",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "If set to 1, validates without storing",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "atomic (default) or row",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.OrderImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Atomic import rolled back",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.OrderImportReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns all available order status constants",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get available order statuses",
+                "responses": {
+                    "200": {
+                        "description": "List of order status",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "properties": {
+                                    "label": {
+                                        "type": "string"
+                                    },
+                                    "value": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the detailed information of a specific order, with its version as ETag",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order detail by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order detail",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderDetail"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner or orders.manage, only while the order is ` + "`" + `created` + "`" + `. Changes destination, package type, weight, quantity or observations; omitted fields are kept. Requires If-Match with the order ETag; the new ETag is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Edit order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order as read by the client",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.OrderEditRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Order is no longer editable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Order changed since it was read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/changes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner or orders.read.all. Field-level changes made by order edits, oldest first; rows with the same version belong to the same edit.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Order change log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderChange"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/delivery-attempts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "delivery_attempts"
+                ],
+                "summary": "List delivery attempts of an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryAttempt"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires deliveries.record. Registers a failed delivery for an order in route. After the configured maximum of attempts the order moves to return_to_sender.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "delivery_attempts"
+                ],
+                "summary": "Record failed delivery attempt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attempt details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "notes": {
+                                    "type": "string"
+                                },
+                                "reason": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Recorded attempt",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryAttempt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/delivery-date": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner or orders.manage. Picks a new delivery date (YYYY-MM-DD) for an order with a failed delivery attempt. Requires If-Match with the order ETag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "delivery_attempts"
+                ],
+                "summary": "Reschedule delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order as read by the client",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New delivery date (YYYY-MM-DD)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "date": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Order changed since it was read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the status history of an order, including failed delivery attempts and reschedules. Owner or orders.read.all.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order timeline",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order timeline",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatusHistory"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/label": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renders the 4x6 shipping label of an order with a Code128 barcode of the order number and a QR code linking to tracking. Owner or orders.read.all.",
+                "produces": [
+                    "application/pdf",
+                    "application/zpl",
+                    "image/png"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get shipping label",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pdf (default), zpl or png",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shipping label",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/return": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner or orders.manage. Creates a return linked to a delivered or return_to_sender order, swapping origin and destination.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create return order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Original order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Return observations",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "observations": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created return order",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the status of an order (orders.status.update). Requires If-Match with the order ETag; the new ETag is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Update order status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order as read by the client",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "status": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Order changed since it was read",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the caller's organization with its members",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Get my organization",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Organization"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not a member of an organization",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opens an organization with the caller as its owner. Only clients that don't belong to an organization yet. Addresses, orders and pickups created afterwards belong to the organization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create organization",
+                "parameters": [
+                    {
+                        "description": "Organization",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Already a member of an organization",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner only. Returns the organization's keys, including revoked and rotated ones, with their prefix and last use.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner only. Issues a key for server-to-server integrations of the organization, sent as X-API-Key or Authorization: Bearer. It can be granted orders.create and addresses.create; without permissions it only reads. rate_limit is in requests per minute (0 uses the default). The key is returned only once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "description": "API key",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.APIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.apiKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner only. The key stops working at once.",
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Revoke API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/api-keys/{id}/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner only. Issues a new key with the same name, permissions and limit. The old key is revoked at once or, with grace_period (e.g. \"24h\", max 168h), keeps working until then.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Rotate API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Grace period of the old key",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "grace_period": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.apiKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Key already revoked or rotated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/invitations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner only. Emails a single-use link to join the organization with the given role (owner, shipper or viewer). Inviting the same email again revokes the earlier link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Invite member",
+                "parameters": [
+                    {
+                        "description": "Invitation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                },
+                                "role": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.OrganizationInvitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not a member of an organization",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Already a member",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/invitations/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Joins the organization of an invitation addressed to the caller's email. Users already in an organization must leave it first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Accept invitation",
+                "parameters": [
+                    {
+                        "description": "Invitation token from the email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "token": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Invitation for another email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Already a member of an organization",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid, used or expired invitation",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/members/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owners remove any member and members can remove themselves to leave. The organization keeps the rows they created and always keeps at least one owner.",
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID of the member",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Last owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner only. The organization always keeps at least one owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Change member role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID of the member",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role: owner, shipper or viewer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "role": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Last owner",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/package-types": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns package types. If ?all=1 and requester has package_types.manage, includes inactive; otherwise only active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "package_types"
+                ],
+                "summary": "List package types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "If set to 1 and requester has package_types.manage, returns active and inactive",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageType"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/package-types/{id}/active": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires package_types.manage. Sets is_active true/false for a PackageType",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "package_types"
+                ],
+                "summary": "Set PackageType active status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "PackageType ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Desired active state",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "active": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/forgot": {
+            "post": {
+                "description": "Emails a single-use reset link. Always answers 202 so it doesn't reveal whether the email is registered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request password reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Sets a new password with the token from the reset email. The token works once and every session of the user is revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "password": {
+                                    "type": "string"
+                                },
+                                "token": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid, used or expired token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pickup-slots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the pickup time windows and their capacity per zone. An empty zone is the default for every zone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pickups"
+                ],
+                "summary": "List pickup slots",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.PickupSlot"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires pickup_slots.manage. Sets the capacity of a zone and time window (HH:MM).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pickups"
+                ],
+                "summary": "Create or update pickup slot",
+                "parameters": [
+                    {
+                        "description": "Pickup slot",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "capacity": {
+                                    "type": "integer"
+                                },
+                                "is_active": {
+                                    "type": "boolean"
+                                },
+                                "window_end": {
+                                    "type": "string"
+                                },
+                                "window_start": {
+                                    "type": "string"
+                                },
+                                "zone": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.PickupSlot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pickups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clients see their pickups. Staff with pickups.manage.all can pass ?all=1 to see every customer; ?date=YYYY-MM-DD returns the scheduled pickups due that day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pickups"
+                ],
+                "summary": "List pickups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pickups.manage.all only: if set to 1, list pickups of all customers",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only scheduled pickups due on this date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.Pickup"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires orders.create or pickups.manage.all. Schedules a courier pickup at an origin address within a time window, optionally grouping created orders from that address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pickups"
+                ],
+                "summary": "Book pickup",
+                "parameters": [
+                    {
+                        "description": "Pickup request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.PickupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Pickup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pickups/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pickups"
+                ],
+                "summary": "Get pickup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pickup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Pickup"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner or pickups.manage.all. Moves a scheduled pickup to another date and time window.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pickups"
+                ],
+                "summary": "Reschedule pickup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pickup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New date and window",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "date": {
+                                    "type": "string"
+                                },
+                                "window_end": {
+                                    "type": "string"
+                                },
+                                "window_start": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Pickup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/pickups/{id}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Owner or pickups.manage.all. Cancels a scheduled pickup and releases its grouped orders.",
+                "tags": [
+                    "pickups"
+                ],
+                "summary": "Cancel pickup",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Pickup ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/scans": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires scans.read. Full scan log, including rejected scans, filtered by order, station and day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stations"
+                ],
+                "summary": "List scans",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Station ID",
+                        "name": "station_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day of the scans (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.Scan"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires scans.record. Resolves the order by order_number and applies the status implied by the scan type (inbound, outbound, load, unload). Repeating the last scan returns it with 200 and changes nothing; scans that don't fit the order status are rejected with 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stations"
+                ],
+                "summary": "Record barcode scan",
+                "parameters": [
+                    {
+                        "description": "Scan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.ScanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Duplicate scan, already applied",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Scan"
+                        }
+                    },
+                    "201": {
+                        "description": "Applied scan",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Scan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Scan does not fit the order status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns active stations. If ?all=1 and requester has stations.manage, includes inactive.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stations"
+                ],
+                "summary": "List stations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "If set to 1 and requester has stations.manage, returns active and inactive",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/logistics-app_backend_internal_domain.Station"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires stations.manage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stations"
+                ],
+                "summary": "Create station",
+                "parameters": [
+                    {
+                        "description": "Station",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "city": {
+                                    "type": "string"
+                                },
+                                "code": {
+                                    "type": "string"
+                                },
+                                "name": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.Station"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stations/{id}/active": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires stations.manage. Sets is_active true/false for a Station",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stations"
+                ],
+                "summary": "Set Station active status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Station ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Desired active state",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "active": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and a new refresh token. Each refresh token works once; presenting a used one revokes the whole session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "refresh_token": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Access and refresh tokens",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http.tokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, expired or reused refresh token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a page of users ordered by id (users.read.all only). q searches email and full name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search in email and full name",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Active flag",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default and 100 at most",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.UserPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a client account and emails a verification link. Public sign-up only creates clients; staff accounts are created by an administrator through /admin/users.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "User registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                },
+                                "full_name": {
+                                    "type": "string"
+                                },
+                                "password": {
+                                    "type": "string"
+                                },
+                                "phone": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created user",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Role other than client",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets a new password for the caller, who must confirm the current one. The caller's other sessions are ended; the current one stays signed in.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change own password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "current_password": {
+                                    "type": "string"
+                                },
+                                "new_password": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Wrong current password",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns user details by ID. Only users.read.all or the user themself can access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User details",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a user account (users.delete): its personal data and personal addresses are anonymized, its sessions, pending tokens and API keys are revoked and the row is soft deleted, so its orders and history stay intact; the deletion is audited. On their own account, users without users.delete get an erasure request for an administrator to approve (202). To suspend an account instead, PATCH is_active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Erasure request filed for the caller's own account",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.ErasureRequest"
+                        }
+                    },
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Last active admin or last owner of an organization",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the fields sent. Users may change their own full_name and phone; role and is_active need users.manage and can't be changed on the own account. Deactivating a user ends its sessions, and the last active admin can't be demoted or deactivated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_usecase.UserUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Last active admin",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends every session of the user: refresh tokens stop working and access tokens already issued are rejected (users.manage or own account only).",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Force logout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "internal_delivery_http.apiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.APIKey"
+                },
+                "key": {
+                    "description": "The key itself, shown only in this response",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http.mfaChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "enrollment_required": {
+                    "description": "The account must enroll first, using the mfa_token at /mfa/enroll and /mfa/confirm",
+                    "type": "boolean"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http.mfaCodesResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http.mfaEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_url": {
+                    "type": "string"
+                },
+                "qr_code": {
+                    "description": "PNG QR code of otpauth_url as a data URL",
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http.reviewBody": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http.tokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "description": "Owner who created or last rotated the key; requests act on their behalf",
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "description": "Set on rotation with a grace period: the old key keeps working until then",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "permissions": {
+                    "description": "Subset of the client permissions the key grants",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logistics-app_backend_internal_domain.Permission"
+                    }
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "Requests per minute, 0 uses the default limit",
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "rotated_from_id": {
+                    "description": "Key this one replaced on rotation",
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "coordinate_id": {
+                    "type": "integer"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "exterior_number": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interior_number": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "neighborhood": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Set when the address belongs to an organization; then every member shares it",
+                    "type": "integer"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Optimistic lock, bumped on every update and exposed as ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.DeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "attempt_number": {
+                    "type": "integer"
+                },
+                "attempted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryFailureReason"
+                },
+                "recorded_by": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.DeliveryFailureReason": {
+            "type": "string",
+            "enum": [
+                "absent",
+                "wrong_address",
+                "refused"
+            ],
+            "x-enum-varnames": [
+                "FailureRecipientAbsent",
+                "FailureWrongAddress",
+                "FailureRefused"
+            ]
+        },
+        "logistics-app_backend_internal_domain.DeliveryPreferences": {
+            "type": "object",
+            "properties": {
+                "access_instructions": {
+                    "type": "string"
+                },
+                "leave_with": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.LeaveWith"
+                },
+                "recipient_name": {
+                    "type": "string"
+                },
+                "recipient_phone": {
+                    "type": "string"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.DeliveryStop": {
+            "type": "object",
+            "properties": {
+                "destination_full_address": {
+                    "type": "string"
+                },
+                "observations": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "preferences": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryPreferences"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "recipient_name": {
+                    "type": "string"
+                },
+                "recipient_phone": {
+                    "type": "string"
+                },
+                "scheduled_delivery_date": {
+                    "type": "string"
+                },
+                "size_code": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.ErasureRequest": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.ErasureStatus"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.ErasureStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "completed",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "ErasurePending",
+                "ErasureCompleted",
+                "ErasureRejected"
+            ]
+        },
+        "logistics-app_backend_internal_domain.LeaveWith": {
+            "type": "string",
+            "enum": [
+                "",
+                "neighbor",
+                "concierge"
+            ],
+            "x-enum-varnames": [
+                "LeaveWithNobody",
+                "LeaveWithNeighbor",
+                "LeaveWithConcierge"
+            ]
+        },
+        "logistics-app_backend_internal_domain.Manifest": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "driver_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "issued_by": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logistics-app_backend_internal_domain.ManifestItem"
+                    }
+                },
+                "manifest_date": {
+                    "type": "string"
+                },
+                "manifest_number": {
+                    "type": "string"
+                },
+                "route_code": {
+                    "type": "string"
+                },
+                "station_code": {
+                    "type": "string"
+                },
+                "station_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.ManifestStatus"
+                },
+                "total_pieces": {
+                    "type": "integer"
+                },
+                "total_weight_kg": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vehicle_plate": {
+                    "type": "string"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.ManifestItem": {
+            "type": "object",
+            "properties": {
+                "destination_full_address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manifest_id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "pieces": {
+                    "type": "integer"
+                },
+                "recipient_name": {
+                    "type": "string"
+                },
+                "size_code": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                },
+                "weight_kg": {
+                    "type": "number"
+                },
+                "zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.ManifestStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "issued"
+            ],
+            "x-enum-varnames": [
+                "ManifestDraft",
+                "ManifestIssued"
+            ]
+        },
+        "logistics-app_backend_internal_domain.Order": {
+            "type": "object",
+            "properties": {
+                "actual_weight_kg": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "delivery_attempts": {
+                    "description": "Delivery attempts",
+                    "type": "integer"
+                },
+                "delivery_preferences": {
+                    "description": "Recipient delivery preferences",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryPreferences"
+                        }
+                    ]
+                },
+                "destination_address_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internal_notes": {
+                    "type": "string"
+                },
+                "observations": {
+                    "type": "string"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "description": "Organization that owns the order, shared by its members",
+                    "type": "integer"
+                },
+                "origin_address_id": {
+                    "type": "integer"
+                },
+                "package_type_id": {
+                    "type": "integer"
+                },
+                "pickup_id": {
+                    "description": "Pickup the order is grouped in, if any",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "return_of_order_id": {
+                    "description": "Reverse logistics: set on return orders, points to the original order",
+                    "type": "integer"
+                },
+                "scheduled_delivery_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Optimistic lock, bumped on every update and exposed as ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.OrderChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "integer"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Order version produced by the edit; groups the fields changed together",
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.OrderDetail": {
+            "type": "object",
+            "properties": {
+                "actual_weight_kg": {
+                    "type": "number"
+                },
+                "ad_city": {
+                    "type": "string"
+                },
+                "ad_exterior": {
+                    "type": "string"
+                },
+                "ad_neighborhood": {
+                    "type": "string"
+                },
+                "ad_postal": {
+                    "type": "string"
+                },
+                "ad_street": {
+                    "type": "string"
+                },
+                "ao_city": {
+                    "type": "string"
+                },
+                "ao_exterior": {
+                    "type": "string"
+                },
+                "ao_neighborhood": {
+                    "type": "string"
+                },
+                "ao_postal": {
+                    "type": "string"
+                },
+                "ao_street": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_attempts": {
+                    "type": "integer"
+                },
+                "delivery_preferences": {
+                    "description": "Recipient delivery preferences",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryPreferences"
+                        }
+                    ]
+                },
+                "destination_address_id": {
+                    "type": "integer"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internal_notes": {
+                    "type": "string"
+                },
+                "observations": {
+                    "type": "string"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "origin_address_id": {
+                    "type": "integer"
+                },
+                "package_type_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "return_of_order_id": {
+                    "description": "Link between an order and its return, in both directions",
+                    "type": "integer"
+                },
+                "return_of_order_number": {
+                    "type": "string"
+                },
+                "return_order_id": {
+                    "type": "integer"
+                },
+                "return_order_number": {
+                    "type": "string"
+                },
+                "scheduled_delivery_date": {
+                    "type": "string"
+                },
+                "size_code": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                },
+                "status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.OrderListItem": {
+            "type": "object",
+            "properties": {
+                "actual_weight_kg": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "destination_full_address": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "origin_full_address": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "size_code": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                },
+                "status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "collected",
+                "in_station",
+                "in_route",
+                "delivered",
+                "cancelled",
+                "delivery_failed",
+                "return_to_sender",
+                "returned"
+            ],
+            "x-enum-varnames": [
+                "OrderCreated",
+                "OrderCollected",
+                "OrderInStation",
+                "OrderInRoute",
+                "OrderDelivered",
+                "OrderCancelled",
+                "OrderDeliveryFailed",
+                "OrderReturnToSender",
+                "OrderReturned"
+            ]
+        },
+        "logistics-app_backend_internal_domain.OrderStatusHistory": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manifest_id": {
+                    "type": "integer"
+                },
+                "new_status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "previous_status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.OrgRole": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "shipper",
+                "viewer"
+            ],
+            "x-enum-varnames": [
+                "OrgOwner",
+                "OrgShipper",
+                "OrgViewer"
+            ]
+        },
+        "logistics-app_backend_internal_domain.Organization": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
                     }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "logistics-app_backend_internal_domain.Address": {
+        },
+        "logistics-app_backend_internal_domain.OrganizationInvitation": {
             "type": "object",
             "properties": {
-                "city": {
+                "accepted_at": {
                     "type": "string"
                 },
-                "coordinate_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "country": {
+                "invited_by": {
+                    "type": "integer"
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "revoked_at": {
                     "type": "string"
                 },
+                "role": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrgRole"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.PackageSize": {
+            "type": "string",
+            "enum": [
+                "S",
+                "M",
+                "L",
+                "XL"
+            ],
+            "x-enum-varnames": [
+                "PackageS",
+                "PackageM",
+                "PackageL",
+                "PackageXL"
+            ]
+        },
+        "logistics-app_backend_internal_domain.PackageType": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "customer_id": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "exterior_number": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_weight_kg": {
+                    "type": "number"
+                },
+                "size_code": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.Permission": {
+            "type": "string",
+            "enum": [
+                "orders.create",
+                "orders.read.all",
+                "orders.manage",
+                "orders.status.update",
+                "orders.export.all",
+                "deliveries.record",
+                "deliveries.route",
+                "addresses.create",
+                "addresses.manage.all",
+                "pickups.manage.all",
+                "pickup_slots.manage",
+                "package_types.manage",
+                "stations.manage",
+                "scans.record",
+                "scans.read",
+                "manifests.manage",
+                "users.read.all",
+                "users.delete",
+                "users.manage",
+                "privacy.manage"
+            ],
+            "x-enum-varnames": [
+                "PermOrdersCreate",
+                "PermOrdersReadAll",
+                "PermOrdersManage",
+                "PermOrdersStatusUpdate",
+                "PermOrdersExportAll",
+                "PermDeliveriesRecord",
+                "PermDeliveriesRoute",
+                "PermAddressesCreate",
+                "PermAddressesManageAll",
+                "PermPickupsManageAll",
+                "PermPickupSlotsManage",
+                "PermPackageTypesManage",
+                "PermStationsManage",
+                "PermScansRecord",
+                "PermScansRead",
+                "PermManifestsManage",
+                "PermUsersReadAll",
+                "PermUsersDelete",
+                "PermUsersManage",
+                "PermPrivacyManage"
+            ]
+        },
+        "logistics-app_backend_internal_domain.Pickup": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
+                "created_by": {
+                    "type": "integer"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "interior_number": {
+                "notes": {
+                    "type": "string"
+                },
+                "order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "organization_id": {
+                    "type": "integer"
+                },
+                "origin_address_id": {
+                    "type": "integer"
+                },
+                "pickup_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PickupStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "integer"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                },
+                "zone": {
                     "type": "string"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.PickupSlot": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
                 },
                 "is_active": {
                     "type": "boolean"
                 },
-                "neighborhood": {
+                "window_end": {
                     "type": "string"
                 },
-                "postal_code": {
+                "window_start": {
+                    "type": "string"
+                },
+                "zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.PickupStatus": {
+            "type": "string",
+            "enum": [
+                "scheduled",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "PickupScheduled",
+                "PickupCancelled"
+            ]
+        },
+        "logistics-app_backend_internal_domain.Role": {
+            "type": "string",
+            "enum": [
+                "client",
+                "admin",
+                "dispatcher",
+                "station_clerk",
+                "support",
+                "finance"
+            ],
+            "x-enum-varnames": [
+                "RoleClient",
+                "RoleAdmin",
+                "RoleDispatcher",
+                "RoleStationClerk",
+                "RoleSupport",
+                "RoleFinance"
+            ]
+        },
+        "logistics-app_backend_internal_domain.Scan": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "previous_status": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.ScanResult"
+                },
+                "route_code": {
+                    "type": "string"
+                },
+                "scan_type": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.ScanType"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "scanned_by": {
+                    "type": "integer"
+                },
+                "station_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_domain.ScanResult": {
+            "type": "string",
+            "enum": [
+                "applied",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "ScanApplied",
+                "ScanRejected"
+            ]
+        },
+        "logistics-app_backend_internal_domain.ScanType": {
+            "type": "string",
+            "enum": [
+                "inbound",
+                "outbound",
+                "load",
+                "unload"
+            ],
+            "x-enum-varnames": [
+                "ScanInbound",
+                "ScanOutbound",
+                "ScanLoad",
+                "ScanUnload"
+            ]
+        },
+        "logistics-app_backend_internal_domain.Station": {
+            "type": "object",
+            "properties": {
+                "city": {
                     "type": "string"
                 },
-                "state": {
+                "code": {
                     "type": "string"
                 },
-                "street": {
+                "created_at": {
                     "type": "string"
                 },
-                "updated_at": {
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "logistics-app_backend_internal_domain.Order": {
+        "logistics-app_backend_internal_domain.User": {
             "type": "object",
             "properties": {
-                "actual_weight_kg": {
-                    "type": "number"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "created_by": {
-                    "type": "integer"
-                },
-                "customer_id": {
-                    "type": "integer"
-                },
-                "destination_address_id": {
-                    "type": "integer"
-                },
-                "id": {
+                "default_address_id": {
                     "type": "integer"
                 },
-                "internal_notes": {
+                "email": {
                     "type": "string"
                 },
-                "observations": {
+                "email_verified_at": {
+                    "description": "Set once the user confirms the email through the verification link",
                     "type": "string"
                 },
-                "order_number": {
+                "full_name": {
                     "type": "string"
                 },
-                "origin_address_id": {
+                "id": {
                     "type": "integer"
                 },
-                "package_type_id": {
-                    "type": "integer"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "quantity": {
+                "mfa_enabled": {
+                    "description": "TOTP second factor; the secret is stored on enrollment and MFAEnabled set once a code confirms it",
+                    "type": "boolean"
+                },
+                "org_role": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrgRole"
+                },
+                "organization_id": {
+                    "description": "Organization the user belongs to, at most one, and the role inside it",
                     "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                "phone": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.Role"
                 },
                 "updated_at": {
                     "type": "string"
@@ -1098,207 +5921,248 @@ const docTemplate = `{
                 }
             }
         },
-        "logistics-app_backend_internal_domain.OrderDetail": {
+        "logistics-app_backend_internal_usecase.APIKeyRequest": {
             "type": "object",
             "properties": {
-                "actual_weight_kg": {
-                    "type": "number"
-                },
-                "ad_city": {
+                "name": {
                     "type": "string"
                 },
-                "ad_exterior": {
-                    "type": "string"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logistics-app_backend_internal_domain.Permission"
+                    }
                 },
-                "ad_neighborhood": {
+                "rate_limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_usecase.ImportMode": {
+            "type": "string",
+            "enum": [
+                "atomic",
+                "row"
+            ],
+            "x-enum-varnames": [
+                "ImportAtomic",
+                "ImportPerRow"
+            ]
+        },
+        "logistics-app_backend_internal_usecase.ManifestRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
                     "type": "string"
                 },
-                "ad_postal": {
+                "driver_name": {
                     "type": "string"
                 },
-                "ad_street": {
+                "route_code": {
+                    "description": "Optional: limits the manifest to the parcels loaded into this route",
                     "type": "string"
                 },
-                "ao_city": {
-                    "type": "string"
+                "station_id": {
+                    "type": "integer"
                 },
-                "ao_exterior": {
+                "vehicle_plate": {
                     "type": "string"
-                },
-                "ao_neighborhood": {
+                }
+            }
+        },
+        "logistics-app_backend_internal_usecase.NewUserRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "ao_postal": {
+                "full_name": {
                     "type": "string"
                 },
-                "ao_street": {
+                "password": {
                     "type": "string"
                 },
-                "created_at": {
+                "phone": {
                     "type": "string"
                 },
+                "role": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.Role"
+                }
+            }
+        },
+        "logistics-app_backend_internal_usecase.OrderEditRequest": {
+            "type": "object",
+            "properties": {
+                "actual_weight_kg": {
+                    "type": "number"
+                },
                 "destination_address_id": {
                     "type": "integer"
                 },
-                "full_name": {
+                "observations": {
                     "type": "string"
                 },
-                "id": {
+                "package_type_id": {
                     "type": "integer"
                 },
-                "internal_notes": {
-                    "type": "string"
-                },
-                "observations": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_usecase.OrderImportReport": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
                 },
-                "order_number": {
-                    "type": "string"
+                "dry_run": {
+                    "type": "boolean"
                 },
-                "origin_address_id": {
+                "failed": {
                     "type": "integer"
                 },
-                "package_type_id": {
-                    "type": "integer"
+                "mode": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_usecase.ImportMode"
                 },
-                "quantity": {
-                    "type": "integer"
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logistics-app_backend_internal_usecase.OrderImportResult"
+                    }
                 },
-                "size_code": {
-                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                "succeeded": {
+                    "type": "integer"
                 },
-                "status": {
-                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_usecase.OrderImportResult": {
+            "type": "object",
+            "properties": {
+                "created_addresses": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "error": {
                     "type": "string"
                 },
-                "user_id": {
+                "line": {
+                    "description": "Line in the file, the header being line 1",
+                    "type": "integer"
+                },
+                "order_id": {
                     "type": "integer"
+                },
+                "order_number": {
+                    "type": "string"
                 }
             }
         },
-        "logistics-app_backend_internal_domain.OrderListItem": {
+        "logistics-app_backend_internal_usecase.OrderRequest": {
             "type": "object",
             "properties": {
                 "actual_weight_kg": {
                     "type": "number"
                 },
-                "created_at": {
-                    "type": "string"
+                "delivery_preferences": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.DeliveryPreferences"
                 },
-                "destination_full_address": {
+                "destination_address_id": {
+                    "type": "integer"
+                },
+                "internal_notes": {
+                    "description": "Only kept for staff that updates order statuses",
                     "type": "string"
                 },
-                "full_name": {
+                "observations": {
                     "type": "string"
                 },
-                "id": {
+                "origin_address_id": {
                     "type": "integer"
                 },
-                "order_number": {
+                "package_type_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_usecase.PickupRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
                     "type": "string"
                 },
-                "origin_full_address": {
+                "notes": {
                     "type": "string"
                 },
-                "quantity": {
+                "order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "origin_address_id": {
                     "type": "integer"
                 },
-                "size_code": {
-                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                "window_end": {
+                    "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/logistics-app_backend_internal_domain.OrderStatus"
+                "window_start": {
+                    "type": "string"
                 }
             }
         },
-        "logistics-app_backend_internal_domain.OrderStatus": {
-            "type": "string",
-            "enum": [
-                "created",
-                "collected",
-                "in_station",
-                "in_route",
-                "delivered",
-                "cancelled"
-            ],
-            "x-enum-varnames": [
-                "OrderCreated",
-                "OrderCollected",
-                "OrderInStation",
-                "OrderInRoute",
-                "OrderDelivered",
-                "OrderCancelled"
-            ]
-        },
-        "logistics-app_backend_internal_domain.PackageSize": {
-            "type": "string",
-            "enum": [
-                "S",
-                "M",
-                "L",
-                "XL"
-            ],
-            "x-enum-varnames": [
-                "PackageS",
-                "PackageM",
-                "PackageL",
-                "PackageXL"
-            ]
-        },
-        "logistics-app_backend_internal_domain.PackageType": {
+        "logistics-app_backend_internal_usecase.ScanRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "barcode": {
                     "type": "string"
                 },
-                "description": {
+                "device_id": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "is_active": {
-                    "type": "boolean"
+                "route_code": {
+                    "description": "Vehicle route the parcel is loaded into, used by route manifests",
+                    "type": "string"
                 },
-                "max_weight_kg": {
-                    "type": "number"
+                "scan_type": {
+                    "$ref": "#/definitions/logistics-app_backend_internal_domain.ScanType"
                 },
-                "size_code": {
-                    "$ref": "#/definitions/logistics-app_backend_internal_domain.PackageSize"
+                "station_id": {
+                    "type": "integer"
                 }
             }
         },
-        "logistics-app_backend_internal_domain.Role": {
-            "type": "string",
-            "enum": [
-                "client",
-                "admin"
-            ],
-            "x-enum-varnames": [
-                "RoleClient",
-                "RoleAdmin"
-            ]
-        },
-        "logistics-app_backend_internal_domain.User": {
+        "logistics-app_backend_internal_usecase.UserPage": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/logistics-app_backend_internal_domain.User"
+                    }
                 },
-                "default_address_id": {
+                "page": {
                     "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "logistics-app_backend_internal_usecase.UserUpdate": {
+            "type": "object",
+            "properties": {
                 "full_name": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
                 "is_active": {
                     "type": "boolean"
                 },
@@ -1307,12 +6171,6 @@ const docTemplate = `{
                 },
                 "role": {
                     "$ref": "#/definitions/logistics-app_backend_internal_domain.Role"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "updated_by": {
-                    "type": "integer"
                 }
             }
         }
@@ -1,26 +1,74 @@
 package app
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	httpdelivery "logistics-app/backend/internal/delivery/http"
 	"logistics-app/backend/internal/domain"
 	"logistics-app/backend/internal/infra/db"
+	"logistics-app/backend/internal/infra/signing"
 	"logistics-app/backend/internal/repository"
 	"logistics-app/backend/internal/usecase"
 
 	"github.com/gorilla/mux"
-	"golang.org/x/crypto/bcrypt"
 )
 
-func Bootstrap(r *mux.Router) error {
-	database, err := db.Connect()
+// DevMode reports whether APP_ENV selects local development, where insecure defaults are tolerated
+func DevMode() bool {
+	switch strings.ToLower(os.Getenv("APP_ENV")) {
+	case "dev", "development", "local":
+		return true
+	}
+	return false
+}
 
-	if err != nil {
-		return err
+// foreignKey is a constraint AutoMigrate doesn't create, since the models carry only the id columns
+type foreignKey struct {
+	table, column, refTable, onDelete string
+}
+
+// Users are soft deleted, so nothing cascades from them; the order's own records go with the order
+var foreignKeys = []foreignKey{
+	{"orders", "customer_id", "users", "RESTRICT"},
+	{"orders", "created_by", "users", "RESTRICT"},
+	{"orders", "updated_by", "users", "SET NULL"},
+	{"orders", "origin_address_id", "addresses", "RESTRICT"},
+	{"orders", "destination_address_id", "addresses", "RESTRICT"},
+	{"orders", "package_type_id", "package_types", "RESTRICT"},
+	{"orders", "return_of_order_id", "orders", "RESTRICT"},
+	{"addresses", "customer_id", "users", "RESTRICT"},
+	{"users", "default_address_id", "addresses", "SET NULL"},
+	{"users", "updated_by", "users", "SET NULL"},
+	{"order_status_histories", "order_id", "orders", "CASCADE"},
+	{"order_changes", "order_id", "orders", "CASCADE"},
+	{"delivery_attempts", "order_id", "orders", "CASCADE"},
+	{"erasure_requests", "user_id", "users", "RESTRICT"},
+}
+
+// addForeignKeys creates the missing foreign keys. They are added NOT VALID, so rows orphaned before
+// the constraints existed don't stop the startup, and then validated; a failed validation is logged
+// and the constraint still applies to new rows.
+func addForeignKeys(database *db.Database) error {
+	for _, fk := range foreignKeys {
+		name := fmt.Sprintf("fk_%s_%s", fk.table, fk.column)
+		add := fmt.Sprintf("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = '%s') THEN ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s(id) ON DELETE %s NOT VALID; END IF; END $$;",
+			name, fk.table, name, fk.column, fk.refTable, fk.onDelete)
+		if err := database.Exec(add).Error; err != nil {
+			return err
+		}
+		if err := database.Exec(fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s", fk.table, name)).Error; err != nil {
+			log.Printf("foreign key %s: existing rows don't satisfy it: %v", name, err)
+		}
 	}
+	return nil
+}
 
-	// AutoMigrate
+func migrate(database *db.Database) error {
 	if err := database.AutoMigrate(
 		&domain.User{},
 		&domain.Coordinates{},
@@ -28,25 +76,46 @@ func Bootstrap(r *mux.Router) error {
 		&domain.PackageType{},
 		&domain.Order{},
 		&domain.OrderStatusHistory{},
+		&domain.OrderChange{},
+		&domain.DeliveryAttempt{},
+		&domain.PickupSlot{},
+		&domain.Pickup{},
+		&domain.Station{},
+		&domain.Scan{},
+		&domain.Manifest{},
+		&domain.ManifestItem{},
+		&domain.IdempotencyKey{},
+		&domain.RefreshToken{},
+		&domain.RevokedAccessToken{},
+		&domain.UserToken{},
+		&domain.LoginThrottle{},
+		&domain.AuditLog{},
+		&domain.MFARecoveryCode{},
+		&domain.Organization{},
+		&domain.OrganizationInvitation{},
+		&domain.APIKey{},
+		&domain.ErasureRequest{},
 	); err != nil {
 		return err
 	}
+	return addForeignKeys(database)
+}
 
-	// Seed simple admin if not exists (store hashed password)
-	admin := domain.User{Email: "admin@example.com", FullName: "System Administrator", IsActive: true}
-	var existing domain.User
-
-	if err := database.Where(&admin).First(&existing).Error; err != nil {
-		// Not found -> create with hashed password
-		hashed, _ := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.DefaultCost)
-		database.Create(&domain.User{Email: admin.Email, Password: string(hashed), Role: domain.RoleAdmin, FullName: "System Administrator", IsActive: true})
-	} else {
-		// If found and password seems not bcrypt (no prefix)
-		if len(existing.Password) > 0 && !(len(existing.Password) > 4 && existing.Password[0] == '$') {
-			hashed, _ := bcrypt.GenerateFromPassword([]byte(existing.Password), bcrypt.DefaultCost)
-			existing.Password = string(hashed)
-			database.Save(&existing)
-		}
+func Bootstrap(r *mux.Router) error {
+	// Fail before touching the database when the token keys are missing or insecure
+	keys, err := signing.FromEnv(DevMode())
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Connect()
+
+	if err != nil {
+		return err
+	}
+
+	if err := migrate(database); err != nil {
+		return err
 	}
 
 	// Seed default package types if not present
@@ -64,16 +133,110 @@ func Bootstrap(r *mux.Router) error {
 		}
 	}
 
+	// Seed default pickup windows (apply to every zone) if not present
+	database.Model(&domain.PickupSlot{}).Count(&count)
+	if count == 0 {
+		slots := []domain.PickupSlot{
+			{Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 20, IsActive: true},
+			{Zone: "", WindowStart: "13:00", WindowEnd: "18:00", Capacity: 20, IsActive: true},
+		}
+		for _, s := range slots {
+			_ = database.Create(&s).Error
+		}
+	}
+
 	orderRepo := repository.NewOrderGormRepo(database)
 	userRepo := repository.NewUserGormRepo(database)
-	userSvc := usecase.NewUserService(userRepo)
+	auditRepo := repository.NewAuditGormRepo(database)
+	userSvc := usecase.NewUserService(userRepo, auditRepo)
 	ptRepo := repository.NewPackageTypeGormRepo(database)
 	ptSvc := usecase.NewPackageTypeService(ptRepo)
-	orderSvc := usecase.NewOrderService(orderRepo, ptSvc)
 	addrRepo := repository.NewAddressGormRepo(database)
+	orderSvc := usecase.NewOrderService(orderRepo, addrRepo, ptSvc)
 	addrSvc := usecase.NewAddressService(addrRepo)
-	h := &httpdelivery.Handler{Orders: orderSvc, Users: userSvc, PackageTypes: ptSvc, Addresses: addrSvc}
+	orderEditSvc := usecase.NewOrderEditService(repository.NewOrderChangeGormRepo(database), orderRepo, addrRepo, ptSvc)
+	importSvc := usecase.NewOrderImportService(orderImportStore{db: database.DB}, ptSvc)
+	// Failed delivery attempts before the order is sent back to the sender
+	maxAttempts := usecase.DefaultMaxDeliveryAttempts
+	if v, err := strconv.ParseUint(os.Getenv("MAX_DELIVERY_ATTEMPTS"), 10, 32); err == nil && v > 0 {
+		maxAttempts = uint(v)
+	}
+	deliverySvc := usecase.NewDeliveryService(repository.NewDeliveryAttemptGormRepo(database), orderRepo, maxAttempts)
+	pickupSvc := usecase.NewPickupService(repository.NewPickupGormRepo(database), addrRepo)
+	stationRepo := repository.NewStationGormRepo(database)
+	stationSvc := usecase.NewStationService(stationRepo)
+	scanSvc := usecase.NewScanService(repository.NewScanGormRepo(database), stationRepo)
+	// Lifetime of refresh tokens, e.g. 720h
+	refreshTTL := usecase.DefaultRefreshTTL
+	if v, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL")); err == nil && v > 0 {
+		refreshTTL = v
+	}
+	sessionSvc := usecase.NewSessionService(repository.NewSessionGormRepo(database), refreshTTL)
+	requireVerified, _ := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION"))
+	mailer := newMailer()
+	baseURL := getenv("APP_BASE_URL", "http://localhost:3000")
+	accountSvc := usecase.NewAccountService(userRepo, repository.NewUserTokenGormRepo(database), mailer, usecase.AccountConfig{
+		BaseURL:              baseURL,
+		RequireVerifiedEmail: requireVerified,
+	})
+	// Lifetime of organization invitations, e.g. 72h
+	invitationTTL := usecase.DefaultInvitationTTL
+	if v, err := time.ParseDuration(os.Getenv("ORG_INVITATION_TTL")); err == nil && v > 0 {
+		invitationTTL = v
+	}
+	orgSvc := usecase.NewOrganizationService(repository.NewOrganizationGormRepo(database), userRepo, mailer, usecase.OrganizationConfig{
+		BaseURL:       baseURL,
+		InvitationTTL: invitationTTL,
+	})
+	loginGuard := usecase.NewLoginGuardService(repository.NewLoginThrottleGormRepo(database), auditRepo, usecase.DefaultLoginGuardConfig)
+	mfaSvc := usecase.NewMFAService(repository.NewMFAGormRepo(database), usecase.MFAConfig{
+		Issuer:        getenv("MFA_ISSUER", "Logistics App"),
+		RequiredRoles: mfaRequiredRoles(os.Getenv("MFA_REQUIRED_ROLES")),
+	})
+	// Requests per minute of an API key without its own limit
+	apiKeyRate := uint(usecase.DefaultAPIKeyRateLimit)
+	if v, err := strconv.ParseUint(os.Getenv("API_KEY_RATE_LIMIT"), 10, 32); err == nil && v > 0 {
+		apiKeyRate = uint(v)
+	}
+	apiKeySvc := usecase.NewAPIKeyService(repository.NewAPIKeyGormRepo(database), apiKeyRate)
+	manifestSvc := usecase.NewManifestService(repository.NewManifestGormRepo(database), stationRepo)
+	h := &httpdelivery.Handler{
+		Orders:        orderSvc,
+		Users:         userSvc,
+		PackageTypes:  ptSvc,
+		Addresses:     addrSvc,
+		OrderEdits:    orderEditSvc,
+		Imports:       importSvc,
+		Idempotency:   usecase.NewIdempotencyService(repository.NewIdempotencyGormRepo(database), usecase.DefaultIdempotencyTTL),
+		Deliveries:    deliverySvc,
+		Pickups:       pickupSvc,
+		Stations:      stationSvc,
+		Scans:         scanSvc,
+		Manifests:     manifestSvc,
+		Sessions:      sessionSvc,
+		Accounts:      accountSvc,
+		LoginGuard:    loginGuard,
+		MFA:           mfaSvc,
+		Organizations: orgSvc,
+		APIKeys:       apiKeySvc,
+		Privacy:       usecase.NewPrivacyService(repository.NewPrivacyGormRepo(database), userSvc, auditRepo),
+		Keys:          keys,
+	}
 	h.Register(r)
-	log.Println("Bootstrap completed")
+	log.Printf("Bootstrap completed, signing tokens with key %s", keys.ActiveID())
 	return nil
 }
+
+// mfaRequiredRoles parses MFA_REQUIRED_ROLES, a comma separated list of roles ("admin" by default, "none" for no role)
+func mfaRequiredRoles(v string) []domain.Role {
+	if strings.TrimSpace(v) == "" {
+		return []domain.Role{domain.RoleAdmin}
+	}
+	var roles []domain.Role
+	for _, r := range strings.Split(v, ",") {
+		if r = strings.TrimSpace(r); r != "" && r != "none" {
+			roles = append(roles, domain.Role(r))
+		}
+	}
+	return roles
+}
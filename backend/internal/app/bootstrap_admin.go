@@ -0,0 +1,110 @@
+package app
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"logistics-app/backend/internal/usecase"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BootstrapAdmin implements the `bootstrap-admin` subcommand: it creates the first administrator.
+// Credentials come from --email/--password, or from stdin one per line (email first) when omitted,
+// so the password doesn't have to end up in the shell history.
+func BootstrapAdmin(args []string, stdin io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("bootstrap-admin", flag.ContinueOnError)
+	fs.SetOutput(out)
+	email := fs.String("email", "", "admin email (read from stdin when empty)")
+	name := fs.String("name", "Administrator", "admin full name")
+	password := fs.String("password", "", "admin password (read from stdin when empty)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	in := bufio.NewScanner(stdin)
+	readLine := func(prompt string) (string, error) {
+		fmt.Fprint(out, prompt)
+		if !in.Scan() {
+			if err := in.Err(); err != nil {
+				return "", err
+			}
+			return "", errors.New("unexpected end of input")
+		}
+		return strings.TrimSpace(in.Text()), nil
+	}
+
+	var err error
+	if *email == "" {
+		if *email, err = readLine("Email: "); err != nil {
+			return err
+		}
+	}
+	if *password == "" {
+		if *password, err = readLine("Password: "); err != nil {
+			return err
+		}
+	}
+
+	*email = strings.ToLower(strings.TrimSpace(*email))
+	if !strings.Contains(*email, "@") {
+		return fmt.Errorf("invalid email %q", *email)
+	}
+	if len(*password) < usecase.MinPrivilegedPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", usecase.MinPrivilegedPasswordLength)
+	}
+	if strings.TrimSpace(*name) == "" {
+		return errors.New("name is required")
+	}
+
+	database, err := db.Connect()
+	if err != nil {
+		return err
+	}
+	if err := migrate(database); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := database.Model(&domain.User{}).Where("email = ?", *email).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("user %s already exists", *email)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	// The operator chose the address, so it counts as verified; MFA enrollment is still forced on first login
+	now := time.Now()
+	admin := domain.User{
+		Email:           *email,
+		Password:        string(hash),
+		FullName:        strings.TrimSpace(*name),
+		Role:            domain.RoleAdmin,
+		IsActive:        true,
+		EmailVerifiedAt: &now,
+	}
+	if err := database.Create(&admin).Error; err != nil {
+		return err
+	}
+
+	database.Create(&domain.AuditLog{
+		Action:  domain.AuditAdminBootstrap,
+		Subject: fmt.Sprintf("user:%d", admin.ID),
+		Details: "created with bootstrap-admin",
+	})
+
+	fmt.Fprintf(out, "\nadmin %s created (id %d)\n", admin.Email, admin.ID)
+	return nil
+}
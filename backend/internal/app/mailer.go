@@ -0,0 +1,32 @@
+package app
+
+import (
+	"os"
+
+	"logistics-app/backend/internal/infra/mail"
+	"logistics-app/backend/internal/usecase"
+)
+
+func getenv(k, def string) string {
+	if v := os.Getenv(k); v != "" {
+		return v
+	}
+	return def
+}
+
+// newMailer picks the mail transport from MAIL_DRIVER: smtp, or file (default) for development
+func newMailer() usecase.Mailer {
+	from := getenv("MAIL_FROM", "no-reply@logistics.local")
+
+	if os.Getenv("MAIL_DRIVER") == "smtp" {
+		return &mail.SMTPMailer{
+			Host:     getenv("SMTP_HOST", "localhost"),
+			Port:     getenv("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}
+	}
+
+	return &mail.FileMailer{Dir: os.Getenv("MAIL_DIR"), From: from}
+}
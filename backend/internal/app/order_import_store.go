@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+	"logistics-app/backend/internal/infra/db"
+	"logistics-app/backend/internal/repository"
+	"logistics-app/backend/internal/usecase"
+
+	"gorm.io/gorm"
+)
+
+// orderImportStore binds the order and address repositories to the import transaction.
+// It is wired here because the repository package cannot depend on usecase.
+type orderImportStore struct{ db *gorm.DB }
+
+func (s orderImportStore) Transaction(ctx context.Context, fn func(tx usecase.OrderImportStore) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(orderImportStore{db: tx})
+	})
+}
+
+func (s orderImportStore) Orders() usecase.OrderRepo {
+	return repository.NewOrderGormRepo(&db.Database{DB: s.db})
+}
+
+func (s orderImportStore) Addresses() usecase.AddressRepo {
+	return repository.NewAddressGormRepo(&db.Database{DB: s.db})
+}
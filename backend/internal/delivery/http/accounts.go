@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Emails a single-use reset link. Always answers 202 so it doesn't reveal whether the email is registered.
+// @Tags auth
+// @Accept json
+// @Param request body object{email=string} true "Account email"
+// @Success 202 "Accepted"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /password/forgot [post]
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.Accounts.RequestPasswordReset(r.Context(), body.Email); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	w.WriteHeader(202)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Sets a new password with the token from the reset email. The token works once and every session of the user is revoked.
+// @Tags auth
+// @Accept json
+// @Param request body object{token=string,password=string} true "Reset token and new password"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 422 {string} string "Invalid, used or expired token"
+// @Router /password/reset [post]
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.Accounts.ResetPassword(r.Context(), body.Token, body.Password); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Confirms the account email with the token from the verification email
+// @Tags auth
+// @Accept json
+// @Param request body object{token=string} true "Verification token"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 422 {string} string "Invalid, used or expired token"
+// @Router /email/verify [post]
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.Accounts.VerifyEmail(r.Context(), body.Token); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Sends a new verification link to the authenticated user; earlier links stop working
+// @Tags auth
+// @Success 202 "Accepted"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "Email already verified"
+// @Failure 500 {string} string "Internal server error"
+// @Security BearerAuth
+// @Router /email/verification [post]
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	uid, _ := caller(r)
+	if err := h.Accounts.ResendVerification(r.Context(), uid); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	w.WriteHeader(202)
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/gorilla/mux"
+)
+
+type apiKeyResponse struct {
+	// The key itself, shown only in this response
+	Key    string         `json:"key"`
+	APIKey *domain.APIKey `json:"api_key"`
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Owner only. Issues a key for server-to-server integrations of the organization, sent as X-API-Key or Authorization: Bearer. It can be granted orders.create and addresses.create; without permissions it only reads. rate_limit is in requests per minute (0 uses the default). The key is returned only once.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param request body usecase.APIKeyRequest true "API key"
+// @Success 201 {object} apiKeyResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /organization/api-keys [post]
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req usecase.APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	k, raw, err := h.APIKeys.Create(r.Context(), scope(r), req)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(apiKeyResponse{Key: raw, APIKey: k})
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Owner only. Returns the organization's keys, including revoked and rotated ones, with their prefix and last use.
+// @Tags organizations
+// @Produce json
+// @Success 200 {array} domain.APIKey
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /organization/api-keys [get]
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	list, err := h.APIKeys.List(r.Context(), scope(r))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate API key
+// @Description Owner only. Issues a new key with the same name, permissions and limit. The old key is revoked at once or, with grace_period (e.g. "24h", max 168h), keeps working until then.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param id path integer true "API key ID"
+// @Param request body object{grace_period=string} false "Grace period of the old key"
+// @Success 201 {object} apiKeyResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Key already revoked or rotated"
+// @Security BearerAuth
+// @Router /organization/api-keys/{id}/rotate [post]
+func (h *Handler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body struct {
+		GracePeriod string `json:"grace_period"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	var grace time.Duration
+	if body.GracePeriod != "" {
+		d, err := time.ParseDuration(body.GracePeriod)
+		if err != nil {
+			http.Error(w, "grace_period inválido, use p.ej. 24h", 400)
+			return
+		}
+		grace = d
+	}
+	k, raw, err := h.APIKeys.Rotate(r.Context(), scope(r), uint(id64), grace)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(apiKeyResponse{Key: raw, APIKey: k})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Owner only. The key stops working at once.
+// @Tags organizations
+// @Param id path integer true "API key ID"
+// @Success 204 "No content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /organization/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	if err := h.APIKeys.Revoke(r.Context(), scope(r), uint(id64)); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	w.WriteHeader(204)
+}
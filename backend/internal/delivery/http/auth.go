@@ -0,0 +1,218 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+)
+
+var errUnauthenticated = errors.New("unauthorized")
+
+// bearerClaims parses and verifies the bearer token of the request
+func (h *Handler) bearerClaims(r *http.Request) (*claims, bool) {
+	header := r.Header.Get("Authorization")
+	if header == "" || !strings.HasPrefix(header, "Bearer ") {
+		return nil, false
+	}
+	tokStr := strings.TrimPrefix(header, "Bearer ")
+	token, err := h.Keys.Parse(tokStr, &claims{})
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+	cl, ok := token.Claims.(*claims)
+	return cl, ok
+}
+
+// apiKey returns the API key of the request, sent as X-API-Key or as a bearer token
+func apiKey(r *http.Request) (string, bool) {
+	if k := r.Header.Get("X-API-Key"); k != "" {
+		return k, true
+	}
+	if k := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); strings.HasPrefix(k, domain.APIKeyPrefix) {
+		return k, true
+	}
+	return "", false
+}
+
+// authenticateKey resolves the caller of an API key. It acts for the owner who issued it, while they
+// are still active and in the organization, with the key's permissions and on the organization's
+// rows only; a key without permissions reads only.
+func (h *Handler) authenticateKey(r *http.Request, raw string) (*domain.Principal, error) {
+	if h.APIKeys == nil {
+		return nil, errUnauthenticated
+	}
+	k, err := h.APIKeys.Authenticate(r.Context(), raw)
+	if err != nil {
+		if errors.Is(err, usecase.ErrRateLimited) {
+			return nil, err
+		}
+		return nil, errUnauthenticated
+	}
+
+	u, err := h.Users.GetByID(r.Context(), k.CreatedBy)
+	if err != nil || u == nil || !u.IsActive || u.OrganizationID == nil || *u.OrganizationID != k.OrganizationID {
+		return nil, errUnauthenticated
+	}
+
+	var perms []domain.Permission
+	for _, p := range u.Permissions() {
+		for _, granted := range k.Permissions {
+			if p == granted {
+				perms = append(perms, p)
+			}
+		}
+	}
+	orgRole := domain.OrgViewer
+	if len(perms) > 0 && u.OrgRole.CanShip() {
+		orgRole = domain.OrgShipper
+	}
+
+	orgID := k.OrganizationID
+	return &domain.Principal{
+		UserID:         u.ID,
+		Role:           u.Role,
+		Permissions:    perms,
+		OrganizationID: &orgID,
+		OrgRole:        orgRole,
+		APIKeyID:       k.ID,
+	}, nil
+}
+
+// authenticate resolves the caller of an API key, or of a valid access token that was not revoked
+// on logout or by a force logout and whose user is still active. The role is read from the user, so a change applies at once.
+func (h *Handler) authenticate(r *http.Request) (*domain.Principal, error) {
+	if raw, ok := apiKey(r); ok {
+		return h.authenticateKey(r, raw)
+	}
+
+	cl, ok := h.bearerClaims(r)
+	if !ok || cl.Purpose != "" {
+		return nil, errUnauthenticated
+	}
+	if h.Sessions != nil && (h.Sessions.IsRevoked(r.Context(), cl.ID) || h.Sessions.IsSessionRevoked(r.Context(), cl.SessionID)) {
+		return nil, errUnauthenticated
+	}
+
+	u, err := h.Users.GetByID(r.Context(), cl.UserID)
+	if err != nil || u == nil || !u.IsActive {
+		return nil, errUnauthenticated
+	}
+	// iat has second precision, so a token from the second of a force logout is rejected too
+	if u.SessionsRevokedAt != nil && (cl.IssuedAt == nil || !cl.IssuedAt.Time.After(u.SessionsRevokedAt.Truncate(time.Second))) {
+		return nil, errUnauthenticated
+	}
+
+	p := &domain.Principal{
+		UserID:         u.ID,
+		Role:           u.Role,
+		Permissions:    u.Permissions(),
+		OrganizationID: u.OrganizationID,
+		OrgRole:        u.OrgRole,
+		TokenID:        cl.ID,
+		SessionID:      cl.SessionID,
+	}
+	if cl.ExpiresAt != nil {
+		p.ExpiresAt = cl.ExpiresAt.Time
+	}
+	return p, nil
+}
+
+// RequireAuth is the middleware of the protected routes: it authenticates the request once and
+// stores the principal in its context, answering 401 otherwise
+func (h *Handler) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := h.authenticate(r)
+		if err != nil {
+			if !writeRateLimited(w, err) {
+				http.Error(w, "unauthorized", 401)
+			}
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(domain.WithPrincipal(r.Context(), p)))
+	})
+}
+
+// RequireSession keeps API keys out of the routes that manage the account or the organization,
+// which need the user signed in as themselves
+func (h *Handler) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := domain.PrincipalFrom(r.Context()); ok && p.APIKeyID != 0 {
+			http.Error(w, "forbidden: not available to API keys", 403)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeRateLimited answers 429 with Retry-After when err is a rate limit, reporting whether it was
+func writeRateLimited(w http.ResponseWriter, err error) bool {
+	var limited *usecase.RateLimitedError
+	if !errors.As(err, &limited) {
+		return false
+	}
+	secs := int(limited.RetryAfter.Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+	http.Error(w, limited.Error(), http.StatusTooManyRequests)
+	return true
+}
+
+// caller returns the user and role of the principal set by RequireAuth
+func caller(r *http.Request) (uint, domain.Role) {
+	p, ok := domain.PrincipalFrom(r.Context())
+	if !ok {
+		return 0, ""
+	}
+	return p.UserID, p.Role
+}
+
+// scope returns the customer data the caller of r reaches: their own and their organization's
+func scope(r *http.Request) domain.Scope {
+	p, ok := domain.PrincipalFrom(r.Context())
+	if !ok {
+		return domain.Scope{}
+	}
+	return p.Scope()
+}
+
+// can reports whether the caller of r holds perm. API keys only hold the permissions they were
+// granted, so handlers ask the principal and never the role.
+func can(r *http.Request, perm domain.Permission) bool {
+	p, ok := domain.PrincipalFrom(r.Context())
+	return ok && p.Can(perm)
+}
+
+// authorizeAny is authorize for actions open to any of perms, e.g. the owner's permission or the
+// staff one that reaches every customer
+func (h *Handler) authorizeAny(w http.ResponseWriter, r *http.Request, perms ...domain.Permission) bool {
+	p, ok := domain.PrincipalFrom(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return false
+	}
+	for _, perm := range perms {
+		if p.Can(perm) {
+			return true
+		}
+	}
+	http.Error(w, "forbidden", 403)
+	return false
+}
+
+// authorize checks that the caller grants p, answering 403 itself when it doesn't
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, perm domain.Permission) (uint, domain.Role, bool) {
+	p, ok := domain.PrincipalFrom(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return 0, "", false
+	}
+	if !p.Can(perm) {
+		http.Error(w, "forbidden", 403)
+		return 0, "", false
+	}
+	return p.UserID, p.Role, true
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"logistics-app/backend/internal/usecase"
+)
+
+// clientIP is the peer address or, when TRUST_PROXY_HEADERS=true, the X-Forwarded-For hop added by
+// the outermost of the TRUSTED_PROXY_COUNT proxies (default 1). Each proxy appends the address it
+// received the request from, so hops to the left of that one come from the client and can be forged.
+func clientIP(r *http.Request) string {
+	if trust, _ := strconv.ParseBool(getenv("TRUST_PROXY_HEADERS", "false")); trust {
+		var hops []string
+		for _, v := range r.Header.Values("X-Forwarded-For") {
+			for _, hop := range strings.Split(v, ",") {
+				hops = append(hops, strings.TrimSpace(hop))
+			}
+		}
+		proxies, err := strconv.Atoi(getenv("TRUSTED_PROXY_COUNT", "1"))
+		if err != nil || proxies < 1 {
+			proxies = 1
+		}
+		if len(hops) >= proxies && hops[len(hops)-proxies] != "" {
+			return hops[len(hops)-proxies]
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeLocked answers 429 with Retry-After when err is a lockout, reporting whether it was
+func writeLocked(w http.ResponseWriter, err error) bool {
+	var locked *usecase.LockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+	secs := int(locked.RetryAfter.Seconds()) + 1
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+	http.Error(w, locked.Error(), http.StatusTooManyRequests)
+	return true
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"logistics-app/backend/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// GetDeliveryFailureReasons
+// @Summary Get failed delivery reasons
+// @Description Returns the catalog of reasons for a failed delivery attempt
+// @Tags delivery_attempts
+// @Produce json
+// @Success 200 {array} object{value=string,label=string} "List of reasons"
+// @Failure 401 {string} string "Unauthorized"
+// @Security BearerAuth
+// @Router /delivery-attempts/reasons [get]
+func (h *Handler) GetDeliveryFailureReasons(w http.ResponseWriter, r *http.Request) {
+
+	reasons := []map[string]string{
+		{"value": string(domain.FailureRecipientAbsent), "label": "Destinatario ausente"},
+		{"value": string(domain.FailureWrongAddress), "label": "Dirección incorrecta"},
+		{"value": string(domain.FailureRefused), "label": "Entrega rechazada"},
+	}
+	_ = json.NewEncoder(w).Encode(reasons)
+}
+
+// RecordDeliveryAttempt godoc
+// @Summary Record failed delivery attempt
+// @Description Requires deliveries.record. Registers a failed delivery for an order in route. After the configured maximum of attempts the order moves to return_to_sender.
+// @Tags delivery_attempts
+// @Accept json
+// @Produce json
+// @Param id path integer true "Order ID"
+// @Param request body object{reason=string,notes=string} true "Attempt details"
+// @Success 201 {object} domain.DeliveryAttempt "Recorded attempt"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /orders/{id}/delivery-attempts [post]
+func (h *Handler) RecordDeliveryAttempt(w http.ResponseWriter, r *http.Request) {
+	uid, _, ok := h.authorize(w, r, domain.PermDeliveriesRecord)
+	if !ok {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body struct {
+		Reason domain.DeliveryFailureReason `json:"reason"`
+		Notes  string                       `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	a, err := h.Deliveries.RecordFailedAttempt(r.Context(), uint(id64), body.Reason, body.Notes, uid)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(a)
+}
+
+// ListDeliveryAttempts godoc
+// @Summary List delivery attempts of an order
+// @Tags delivery_attempts
+// @Produce json
+// @Param id path integer true "Order ID"
+// @Success 200 {array} domain.DeliveryAttempt
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /orders/{id}/delivery-attempts [get]
+func (h *Handler) ListDeliveryAttempts(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	list, err := h.Deliveries.ListAttempts(r.Context(), scope(r), can(r, domain.PermOrdersReadAll), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// RescheduleDelivery godoc
+// @Summary Reschedule delivery
+// @Description Owner or orders.manage. Picks a new delivery date (YYYY-MM-DD) for an order with a failed delivery attempt. Requires If-Match with the order ETag.
+// @Tags delivery_attempts
+// @Accept json
+// @Param id path integer true "Order ID"
+// @Param If-Match header string true "ETag of the order as read by the client"
+// @Param request body object{date=string} true "New delivery date (YYYY-MM-DD)"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 412 {string} string "Order changed since it was read"
+// @Failure 428 {string} string "If-Match required"
+// @Security BearerAuth
+// @Router /orders/{id}/delivery-date [patch]
+func (h *Handler) RescheduleDelivery(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermOrdersManage) {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body struct {
+		Date string `json:"date"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	date, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		http.Error(w, "date debe tener formato YYYY-MM-DD", 400)
+		return
+	}
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+	newVersion, err := h.Deliveries.Reschedule(r.Context(), scope(r), can(r, domain.PermOrdersManage), uint(id64), version, date)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	setETag(w, newVersion)
+	w.WriteHeader(204)
+}
+
+// ListDeliveryStops godoc
+// @Summary Driver stop list
+// @Description Requires deliveries.route. Orders in route with destination, recipient contact and delivery preferences, earliest preferred window first.
+// @Tags delivery_attempts
+// @Produce json
+// @Success 200 {array} domain.DeliveryStop
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal server error"
+// @Security BearerAuth
+// @Router /delivery-stops [get]
+func (h *Handler) ListDeliveryStops(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermDeliveriesRoute)
+	if !ok {
+		return
+	}
+	stops, err := h.Orders.ListDeliveryStops(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(stops)
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func setETag(w http.ResponseWriter, version uint) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatUint(uint64(version), 10)))
+}
+
+// ifMatch reads the version the client expects from If-Match; "*" matches any version and yields 0.
+// A missing header is answered with 428 and an unparseable one with 412, returning ok=false.
+func ifMatch(w http.ResponseWriter, r *http.Request) (version uint, ok bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" {
+		http.Error(w, "If-Match requerido: envíe el ETag obtenido al consultar el recurso", http.StatusPreconditionRequired)
+		return 0, false
+	}
+	if v == "*" {
+		return 0, true
+	}
+
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil || n == 0 {
+		http.Error(w, "If-Match no corresponde a la versión actual", http.StatusPreconditionFailed)
+		return 0, false
+	}
+	return uint(n), true
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/export"
+)
+
+// downloadWriter sets the download headers on the first write, so a failure before
+// any output can still be answered with a plain error
+type downloadWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	wrote       bool
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.wrote {
+		d.wrote = true
+		d.w.Header().Set("Content-Type", d.contentType)
+		d.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", d.filename))
+	}
+	return d.w.Write(p)
+}
+
+// ExportOrders godoc
+// @Summary Export orders
+// @Description Same scope as the order list: clients export their orders, orders.export.all can export all orders with ?all=1. CSV and XLSX are streamed row by row with the list columns, plus the detail columns with ?detail=1. PDF is a summary with totals by status and the first 500 orders.
+// @Tags orders
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce application/pdf
+// @Param format query string false "csv (default), xlsx or pdf"
+// @Param all query string false "If set to 1 and requester has orders.export.all, exports all orders"
+// @Param detail query string false "If set to 1, adds the detail columns"
+// @Success 200 {file} file "Order export"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 500 {string} string "Internal server error"
+// @Security BearerAuth
+// @Router /orders/export [get]
+func (h *Handler) ExportOrders(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermOrdersExportAll) {
+		return
+	}
+	format := export.Format(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		format = export.FormatCSV
+	}
+	out := &downloadWriter{w: w, filename: fmt.Sprintf("orders-%s.%s", time.Now().Format("20060102"), format)}
+	ow, contentType, err := export.NewOrderWriter(format, out, r.URL.Query().Get("detail") == "1")
+	if err != nil {
+		if errors.Is(err, export.ErrUnsupportedFormat) {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	out.contentType = contentType
+	if can(r, domain.PermOrdersExportAll) && r.URL.Query().Get("all") == "1" {
+		err = h.Orders.ExportJoinedAll(r.Context(), ow.Write)
+	} else {
+		err = h.Orders.ExportJoinedByCustomer(r.Context(), scope(r), ow.Write)
+	}
+	if err == nil {
+		err = ow.Close()
+	}
+	if err != nil {
+		if !out.wrote {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		// the response is already streaming, the client gets a truncated file
+		log.Printf("order export aborted: %v", err)
+	}
+}
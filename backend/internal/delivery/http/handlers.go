@@ -2,13 +2,16 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
 	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/signing"
 	"logistics-app/backend/internal/usecase"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,15 +23,38 @@ type Handler struct {
 	Users        *usecase.UserService
 	PackageTypes *usecase.PackageTypeService
 	Addresses    *usecase.AddressService
+	OrderEdits   *usecase.OrderEditService
+	Imports      *usecase.OrderImportService
+	Idempotency  *usecase.IdempotencyService
+	Deliveries   *usecase.DeliveryService
+	Pickups      *usecase.PickupService
+	Stations     *usecase.StationService
+	Scans        *usecase.ScanService
+	Manifests    *usecase.ManifestService
+	Sessions     *usecase.SessionService
+	Accounts     *usecase.AccountService
+	LoginGuard   *usecase.LoginGuardService
+	MFA          *usecase.MFAService
+	// Organizations whose members share addresses, orders and pickups
+	Organizations *usecase.OrganizationService
+	// Organization API keys, accepted next to access tokens
+	APIKeys *usecase.APIKeyService
+	// Personal data export and erasure requests
+	Privacy *usecase.PrivacyService
+	// Keys that sign and verify the JWTs
+	Keys *signing.KeySet
 }
 
 type claims struct {
 	UserID uint        `json:"uid"`
 	Role   domain.Role `json:"role"`
+	// Login session the token belongs to, revoked as a whole on logout
+	SessionID string `json:"sid,omitempty"`
+	// Set on tokens that only serve one step of the login, e.g. "mfa"; they are not access tokens
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func jwtKey() []byte { return []byte(getenv("JWT_SECRET", "dev_secret")) }
 func getenv(k, d string) string {
 	if v := os.Getenv(k); v != "" {
 		return v
@@ -37,41 +63,121 @@ func getenv(k, d string) string {
 }
 
 func (h *Handler) Register(r *mux.Router) {
+	// Public routes; EnrollMFA and ConfirmMFA also take the MFA challenge token and check it themselves
 	r.HandleFunc("/api/login", h.Login).Methods(http.MethodPost)
-	// Users
+	r.HandleFunc("/api/login/mfa", h.LoginMFA).Methods(http.MethodPost)
+	r.HandleFunc("/api/mfa/enroll", h.EnrollMFA).Methods(http.MethodPost)
+	r.HandleFunc("/api/mfa/confirm", h.ConfirmMFA).Methods(http.MethodPost)
+	r.HandleFunc("/api/token/refresh", h.RefreshToken).Methods(http.MethodPost)
+	r.HandleFunc("/api/password/forgot", h.ForgotPassword).Methods(http.MethodPost)
+	r.HandleFunc("/api/password/reset", h.ResetPassword).Methods(http.MethodPost)
+	r.HandleFunc("/api/email/verify", h.VerifyEmail).Methods(http.MethodPost)
 	r.HandleFunc("/api/users", h.RegisterUser).Methods(http.MethodPost)
-	r.HandleFunc("/api/users/{id}", h.GetUserByID).Methods(http.MethodGet)
-	r.HandleFunc("/api/users/{id}", h.DeleteUser).Methods(http.MethodDelete)
+	r.HandleFunc("/.well-known/jwks.json", h.JWKS).Methods(http.MethodGet)
+	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(200); _, _ = w.Write([]byte("ok")) }).Methods(http.MethodGet)
+
+	// Everything else needs an access token or an API key, validated once by RequireAuth
+	api := r.NewRoute().Subrouter()
+	api.Use(h.RequireAuth)
+
+	// Account and organization management need the user's own session, API keys get 403
+	session := api.NewRoute().Subrouter()
+	session.Use(h.RequireSession)
+	session.HandleFunc("/api/mfa/recovery-codes", h.RegenerateRecoveryCodes).Methods(http.MethodPost)
+	session.HandleFunc("/api/mfa/disable", h.DisableMFA).Methods(http.MethodPost)
+	session.HandleFunc("/api/logout", h.Logout).Methods(http.MethodPost)
+	session.HandleFunc("/api/email/verification", h.ResendVerification).Methods(http.MethodPost)
+	// Users
+	session.HandleFunc("/api/users", h.ListUsers).Methods(http.MethodGet)
+	session.HandleFunc("/api/admin/users", h.CreateUser).Methods(http.MethodPost)
+	session.HandleFunc("/api/users/me/password", h.ChangePassword).Methods(http.MethodPost)
+	session.HandleFunc("/api/users/{id}", h.GetUserByID).Methods(http.MethodGet)
+	session.HandleFunc("/api/users/{id}", h.UpdateUser).Methods(http.MethodPatch)
+	session.HandleFunc("/api/users/{id}", h.DeleteUser).Methods(http.MethodDelete)
+	session.HandleFunc("/api/users/{id}/logout", h.ForceLogout).Methods(http.MethodPost)
+
+	session.HandleFunc("/api/me/export", h.ExportMyData).Methods(http.MethodGet)
+	session.HandleFunc("/api/me/erasure-request", h.RequestErasure).Methods(http.MethodPost)
+	session.HandleFunc("/api/erasure-requests", h.ListErasureRequests).Methods(http.MethodGet)
+	session.HandleFunc("/api/erasure-requests/{id}/approve", h.ApproveErasure).Methods(http.MethodPost)
+	session.HandleFunc("/api/erasure-requests/{id}/reject", h.RejectErasure).Methods(http.MethodPost)
+	// Organizations
+	session.HandleFunc("/api/organization", h.CreateOrganization).Methods(http.MethodPost)
+	session.HandleFunc("/api/organization", h.GetOrganization).Methods(http.MethodGet)
+	session.HandleFunc("/api/organization/invitations", h.InviteMember).Methods(http.MethodPost)
+	session.HandleFunc("/api/organization/invitations/accept", h.AcceptInvitation).Methods(http.MethodPost)
+	session.HandleFunc("/api/organization/members/{id}", h.UpdateMemberRole).Methods(http.MethodPatch)
+	session.HandleFunc("/api/organization/members/{id}", h.RemoveMember).Methods(http.MethodDelete)
+	session.HandleFunc("/api/organization/api-keys", h.CreateAPIKey).Methods(http.MethodPost)
+	session.HandleFunc("/api/organization/api-keys", h.ListAPIKeys).Methods(http.MethodGet)
+	session.HandleFunc("/api/organization/api-keys/{id}/rotate", h.RotateAPIKey).Methods(http.MethodPost)
+	session.HandleFunc("/api/organization/api-keys/{id}", h.RevokeAPIKey).Methods(http.MethodDelete)
+
 	// Package Types
-	r.HandleFunc("/api/package-types", h.ListPackageTypes).Methods(http.MethodGet)
-	r.HandleFunc("/api/package-types/{id}/active", h.SetPackageTypeActive).Methods(http.MethodPatch)
+	api.HandleFunc("/api/package-types", h.ListPackageTypes).Methods(http.MethodGet)
+	api.HandleFunc("/api/package-types/{id}/active", h.SetPackageTypeActive).Methods(http.MethodPatch)
 	// Addresses
-	r.HandleFunc("/api/addresses", h.CreateAddress).Methods(http.MethodPost)
-	r.HandleFunc("/api/addresses", h.ListAddresses).Methods(http.MethodGet)
-	r.HandleFunc("/api/addresses/{id}", h.GetAddress).Methods(http.MethodGet)
-	r.HandleFunc("/api/addresses/{id}", h.UpdateAddress).Methods(http.MethodPut)
-	r.HandleFunc("/api/addresses/{id}", h.DeleteAddress).Methods(http.MethodDelete)
-	r.HandleFunc("/api/addresses/{id}/active", h.SetAddressActive).Methods(http.MethodPatch)
+	api.HandleFunc("/api/addresses", h.CreateAddress).Methods(http.MethodPost)
+	api.HandleFunc("/api/addresses", h.ListAddresses).Methods(http.MethodGet)
+	api.HandleFunc("/api/addresses/{id}", h.GetAddress).Methods(http.MethodGet)
+	api.HandleFunc("/api/addresses/{id}", h.UpdateAddress).Methods(http.MethodPut)
+	api.HandleFunc("/api/addresses/{id}", h.DeleteAddress).Methods(http.MethodDelete)
+	api.HandleFunc("/api/addresses/{id}/active", h.SetAddressActive).Methods(http.MethodPatch)
 	// Orders
-	r.HandleFunc("/api/orders", h.CreateOrder).Methods(http.MethodPost)
-	r.HandleFunc("/api/orders", h.MyOrders).Methods(http.MethodGet)
-	r.HandleFunc("/api/orders/status", h.GetOrderStatus).Methods(http.MethodGet)
-	r.HandleFunc("/api/orders/{id}", h.GetOrderByID).Methods(http.MethodGet)
-	r.HandleFunc("/api/orders/{id}/status", h.UpdateStatus).Methods(http.MethodPatch)
-
-	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(200); _, _ = w.Write([]byte("ok")) }).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders", h.CreateOrder).Methods(http.MethodPost)
+	api.HandleFunc("/api/orders", h.MyOrders).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/status", h.GetOrderStatus).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/import", h.ImportOrders).Methods(http.MethodPost)
+	api.HandleFunc("/api/orders/export", h.ExportOrders).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/{id}", h.GetOrderByID).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/{id}", h.EditOrder).Methods(http.MethodPatch)
+	api.HandleFunc("/api/orders/{id}/changes", h.ListOrderChanges).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/{id}/status", h.UpdateStatus).Methods(http.MethodPatch)
+	api.HandleFunc("/api/orders/{id}/history", h.GetOrderHistory).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/{id}/return", h.CreateReturn).Methods(http.MethodPost)
+	api.HandleFunc("/api/orders/{id}/label", h.GetOrderLabel).Methods(http.MethodGet)
+	// Delivery attempts
+	api.HandleFunc("/api/delivery-attempts/reasons", h.GetDeliveryFailureReasons).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/{id}/delivery-attempts", h.RecordDeliveryAttempt).Methods(http.MethodPost)
+	api.HandleFunc("/api/orders/{id}/delivery-attempts", h.ListDeliveryAttempts).Methods(http.MethodGet)
+	api.HandleFunc("/api/orders/{id}/delivery-date", h.RescheduleDelivery).Methods(http.MethodPatch)
+	api.HandleFunc("/api/delivery-stops", h.ListDeliveryStops).Methods(http.MethodGet)
+	// Pickups
+	api.HandleFunc("/api/pickup-slots", h.ListPickupSlots).Methods(http.MethodGet)
+	api.HandleFunc("/api/pickup-slots", h.SavePickupSlot).Methods(http.MethodPut)
+	api.HandleFunc("/api/pickups", h.BookPickup).Methods(http.MethodPost)
+	api.HandleFunc("/api/pickups", h.ListPickups).Methods(http.MethodGet)
+	api.HandleFunc("/api/pickups/{id}", h.GetPickup).Methods(http.MethodGet)
+	api.HandleFunc("/api/pickups/{id}", h.ReschedulePickup).Methods(http.MethodPatch)
+	api.HandleFunc("/api/pickups/{id}/cancel", h.CancelPickup).Methods(http.MethodPatch)
+	// Stations and scans
+	api.HandleFunc("/api/stations", h.ListStations).Methods(http.MethodGet)
+	api.HandleFunc("/api/stations", h.CreateStation).Methods(http.MethodPost)
+	api.HandleFunc("/api/stations/{id}/active", h.SetStationActive).Methods(http.MethodPatch)
+	api.HandleFunc("/api/scans", h.RecordScan).Methods(http.MethodPost)
+	api.HandleFunc("/api/scans", h.ListScans).Methods(http.MethodGet)
+	// Manifests
+	api.HandleFunc("/api/manifests", h.CreateManifest).Methods(http.MethodPost)
+	api.HandleFunc("/api/manifests", h.ListManifests).Methods(http.MethodGet)
+	api.HandleFunc("/api/manifests/{id}", h.GetManifest).Methods(http.MethodGet)
+	api.HandleFunc("/api/manifests/{id}/refresh", h.RefreshManifest).Methods(http.MethodPost)
+	api.HandleFunc("/api/manifests/{id}/issue", h.IssueManifest).Methods(http.MethodPost)
+	api.HandleFunc("/api/manifests/{id}/document", h.GetManifestDocument).Methods(http.MethodGet)
 }
 
 // Login godoc
 // @Summary Login endpoint
-// @Description Authenticates user and returns JWT token
+// @Description Authenticates user and returns a short-lived JWT access token plus a refresh token. Accounts with MFA (mandatory for some roles) get an mfa_token instead, to complete at /login/mfa or, when enrollment_required, at /mfa/enroll and /mfa/confirm. Repeated failures lock the account or client IP for a growing period.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body object{email=string,password=string} true "Login credentials"
-// @Success 200 {object} object{token=string} "JWT token"
+// @Success 200 {object} tokenResponse "Access and refresh tokens"
+// @Success 202 {object} mfaChallengeResponse "Second factor required"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Not authorized"
+// @Failure 403 {string} string "Email not verified"
+// @Failure 429 {string} string "Too many failed attempts, see Retry-After"
 // @Failure 500 {string} string "Internal server error"
 // @Router /login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
@@ -85,40 +191,53 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	u, err := h.Users.Authenticate(body.Email, body.Password)
+	ip := clientIP(r)
+	if err := h.LoginGuard.Check(r.Context(), body.Email, ip); err != nil {
+		if !writeLocked(w, err) {
+			http.Error(w, err.Error(), 500)
+		}
+		return
+	}
+
+	u, err := h.Users.Authenticate(r.Context(), body.Email, body.Password)
 	if err != nil {
+		if err := h.LoginGuard.Failure(r.Context(), body.Email, ip); err != nil {
+			log.Printf("login throttle: %v", err)
+		}
 		http.Error(w, "Invalid credentials", 401)
 		return
 	}
-
-	now := time.Now()
-	cl := &claims{
-		UserID: u.ID,
-		Role:   u.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour * 2)),
-		},
+	if err := h.Accounts.CanLogin(u); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 403))
+		return
+	}
+	// The account counters are cleared only after the second factor
+	if u.MFAEnabled || h.MFA.Required(u.Role) {
+		h.writeMFAChallenge(w, u)
+		return
+	}
+	if err := h.LoginGuard.Success(r.Context(), body.Email); err != nil {
+		log.Printf("login throttle: %v", err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, cl)
-	s, err := token.SignedString(jwtKey())
+	sessionID, refresh, err := h.Sessions.Start(r.Context(), u.ID)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	_ = json.NewEncoder(w).Encode(map[string]string{"token": s})
+	h.writeTokens(w, u, sessionID, refresh)
 }
 
 // RegisterUser godoc
 // @Summary Register new user
-// @Description Creates a new user account
+// @Description Creates a client account and emails a verification link. Public sign-up only creates clients; staff accounts are created by an administrator through /admin/users.
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param request body object{email=string,password=string,full_name=string,phone=string,role=string} true "User registration details"
+// @Param request body object{email=string,password=string,full_name=string,phone=string} true "User registration details"
 // @Success 201 {object} domain.User "Created user"
 // @Failure 400 {string} string "Bad request"
+// @Failure 403 {string} string "Role other than client"
 // @Router /users [post]
 func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	var body struct {
@@ -132,18 +251,27 @@ func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	u, err := h.Users.Register(body.Email, body.Password, body.FullName, body.Phone, body.Role)
+	// Rejected rather than ignored, so a client asking for another role learns it wasn't granted
+	if body.Role != "" && body.Role != domain.RoleClient {
+		http.Error(w, "forbidden: el registro público solo crea clientes", 403)
+		return
+	}
+	u, err := h.Users.Register(r.Context(), body.Email, body.Password, body.FullName, body.Phone)
 	if err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
+	// The link can be requested again, so a mail failure doesn't fail the registration
+	if err := h.Accounts.SendVerification(r.Context(), u); err != nil {
+		log.Printf("verification email to user %d: %v", u.ID, err)
+	}
 	w.WriteHeader(201)
 	_ = json.NewEncoder(w).Encode(u)
 }
 
 // GetUserByID godoc
 // @Summary Get user by ID
-// @Description Returns user details by ID. Only admin or the user themself can access.
+// @Description Returns user details by ID. Only users.read.all or the user themself can access.
 // @Tags users
 // @Produce json
 // @Param id path integer true "User ID"
@@ -154,19 +282,15 @@ func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /users/{id} [get]
 func (h *Handler) GetUserByID(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
+	uid, _ := caller(r)
 	idStr := mux.Vars(r)["id"]
 	id64, _ := strconv.ParseUint(idStr, 10, 64)
 	id := uint(id64)
-	if role != domain.RoleAdmin && uid != id {
+	if !can(r, domain.PermUsersReadAll) && uid != id {
 		http.Error(w, "forbidden", 403)
 		return
 	}
-	u, err := h.Users.GetByID(id)
+	u, err := h.Users.GetByID(r.Context(), id)
 	if err != nil || u == nil {
 		http.Error(w, "not found", 404)
 		return
@@ -176,30 +300,36 @@ func (h *Handler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 
 // DeleteUser godoc
 // @Summary Delete user
-// @Description Deletes a user account (admin or own account only)
+// @Description Deletes a user account (users.delete): its personal data and personal addresses are anonymized, its sessions, pending tokens and API keys are revoked and the row is soft deleted, so its orders and history stay intact; the deletion is audited. On their own account, users without users.delete get an erasure request for an administrator to approve (202). To suspend an account instead, PATCH is_active.
 // @Tags users
+// @Produce json
 // @Param id path integer true "User ID"
+// @Success 202 {object} domain.ErasureRequest "Erasure request filed for the caller's own account"
 // @Success 204 "No content"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Last active admin or last owner of an organization"
 // @Security BearerAuth
 // @Router /users/{id} [delete]
 func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
+	p, _ := domain.PrincipalFrom(r.Context())
 	idStr := mux.Vars(r)["id"]
 	id64, _ := strconv.ParseUint(idStr, 10, 64)
-	id := uint(id64)
-	if role != domain.RoleAdmin && uid != id {
-		http.Error(w, "forbidden", 403)
+	// self-service deletion goes through the admin-approved erasure
+	if uint(id64) == p.UserID && !p.Can(domain.PermUsersDelete) {
+		e, err := h.Privacy.RequestErasure(r.Context(), p.UserID, clientIP(r), "")
+		if err != nil {
+			http.Error(w, err.Error(), errStatus(err, 500))
+			return
+		}
+		w.WriteHeader(202)
+		_ = json.NewEncoder(w).Encode(e)
 		return
 	}
-	if err := h.Users.Delete(id); err != nil {
-		http.Error(w, err.Error(), 400)
+	if err := h.Users.Delete(r.Context(), *p, clientIP(r), uint(id64)); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
 		return
 	}
 	w.WriteHeader(204)
@@ -211,45 +341,65 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 // @Tags orders
 // @Accept json
 // @Produce json
-// @Param order body domain.Order true "Order details"
+// @Param Idempotency-Key header string false "Retries with the same key and body replay the first response for 24h"
+// @Param order body usecase.OrderRequest true "Order details; number, status, pickup and version are set by the server"
 // @Success 201 {object} domain.Order "Created order"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 403 {string} string "Forbidden"
+// @Failure 409 {string} string "A request with the same Idempotency-Key is in progress or the order number is taken"
+// @Failure 422 {string} string "Idempotency-Key reused with a different body"
+// @Failure 500 {string} string "Internal error, not cached for the Idempotency-Key"
 // @Security BearerAuth
 // @Router /orders [post]
 func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
+	uid, _, ok := h.authorize(w, r, domain.PermOrdersCreate)
 	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
-	if role != domain.RoleClient && role != domain.RoleAdmin {
-		http.Error(w, "forbidden", 403)
-		return
-	}
-	var o domain.Order
-	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
-		http.Error(w, err.Error(), 400)
 		return
 	}
-	o.CustomerID = uid
-	o.CreatedBy = uid
-	o.UpdatedBy = &uid
-	if err := h.Orders.Create(&o); err != nil {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	w.WriteHeader(201)
-	_ = json.NewEncoder(w).Encode(o)
+	h.idempotent(w, r, uid, "POST /api/orders", body, func(w http.ResponseWriter, r *http.Request) {
+		var req usecase.OrderRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		o := domain.Order{
+			OriginAddressID:      req.OriginAddressID,
+			DestinationAddressID: req.DestinationAddressID,
+			PackageTypeID:        req.PackageTypeID,
+			Quantity:             req.Quantity,
+			ActualWeightKg:       req.ActualWeightKg,
+			Observations:         req.Observations,
+			DeliveryPreferences:  req.DeliveryPreferences,
+			CustomerID:           uid,
+			OrganizationID:       scope(r).Writable().OrganizationID,
+			CreatedBy:            uid,
+			UpdatedBy:            &uid,
+		}
+		if can(r, domain.PermOrdersStatusUpdate) {
+			o.InternalNotes = req.InternalNotes
+		}
+		if err := h.Orders.Create(r.Context(), &o); err != nil {
+			// anything but a validation error may succeed on retry, and 5xx is not cached
+			http.Error(w, err.Error(), errStatus(err, 500))
+			return
+		}
+		w.WriteHeader(201)
+		_ = json.NewEncoder(w).Encode(o)
+	})
 }
 
 // Orders list godoc
 // @Summary List orders
-// @Description Clients see only their orders. Admins can see all orders by passing ?all=1.
+// @Description Clients see only their orders. Staff with orders.read.all can see all orders by passing ?all=1.
 // @Tags orders
 // @Produce json
-// @Param all query string false "If set to 1 and requester is admin, returns all orders; otherwise returns only own orders"
+// @Param all query string false "If set to 1 and requester has orders.read.all, returns all orders; otherwise returns only own orders"
 // @Success 200 {array} domain.OrderListItem "List of orders with details"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 403 {string} string "Forbidden"
@@ -257,23 +407,17 @@ func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /orders [get]
 func (h *Handler) MyOrders(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
-	if role != domain.RoleClient && role != domain.RoleAdmin {
-		http.Error(w, "forbidden", 403)
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermOrdersReadAll) {
 		return
 	}
 	var (
 		items []domain.OrderListItem
 		err   error
 	)
-	if role == domain.RoleAdmin && r.URL.Query().Get("all") == "1" {
-		items, err = h.Orders.ListJoinedAll()
+	if can(r, domain.PermOrdersReadAll) && r.URL.Query().Get("all") == "1" {
+		items, err = h.Orders.ListJoinedAll(r.Context())
 	} else {
-		items, err = h.Orders.ListJoinedByCustomer(uid)
+		items, err = h.Orders.ListJoinedByCustomer(r.Context(), scope(r))
 	}
 	if err != nil {
 		http.Error(w, err.Error(), 500)
@@ -284,25 +428,23 @@ func (h *Handler) MyOrders(w http.ResponseWriter, r *http.Request) {
 
 // UpdateStatus godoc
 // @Summary Update order status
-// @Description Updates the status of an order (admin only)
+// @Description Updates the status of an order (orders.status.update). Requires If-Match with the order ETag; the new ETag is returned.
 // @Tags orders
 // @Accept json
 // @Param id path integer true "Order ID"
+// @Param If-Match header string true "ETag of the order as read by the client"
 // @Param status body object{status=string} true "New status"
 // @Success 204 "No content"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 403 {string} string "Forbidden"
+// @Failure 412 {string} string "Order changed since it was read"
+// @Failure 428 {string} string "If-Match required"
 // @Security BearerAuth
 // @Router /orders/{id}/status [patch]
 func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
+	uid, _, ok := h.authorize(w, r, domain.PermOrdersStatusUpdate)
 	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
-	if role != domain.RoleAdmin {
-		http.Error(w, "forbidden", 403)
 		return
 	}
 	idStr := mux.Vars(r)["id"]
@@ -315,16 +457,22 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	if err := h.Orders.UpdateStatus(uint(id64), body.InternalNotes, body.Status, uid); err != nil {
-		http.Error(w, err.Error(), 400)
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+	newVersion, err := h.Orders.UpdateStatus(r.Context(), uint(id64), version, body.InternalNotes, body.Status, uid)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
 		return
 	}
+	setETag(w, newVersion)
 	w.WriteHeader(204)
 }
 
 // GetOrderByID godoc
 // @Summary Get order detail by ID
-// @Description Returns the detailed information of a specific order
+// @Description Returns the detailed information of a specific order, with its version as ETag
 // @Tags orders
 // @Produce json
 // @Param id path integer true "Order ID"
@@ -336,14 +484,9 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /orders/{id} [get]
 func (h *Handler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
 	idStr := mux.Vars(r)["id"]
 	id64, _ := strconv.ParseUint(idStr, 10, 64)
-	detail, err := h.Orders.GetDetailByID(uint(id64))
+	detail, err := h.Orders.GetDetailByID(r.Context(), uint(id64))
 	if err != nil {
 		// we don't have gorm here; treat any error as not found unless it's clearly a server error
 		if strings.Contains(strings.ToLower(err.Error()), "not found") {
@@ -353,34 +496,92 @@ func (h *Handler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 500)
 		return
 	}
-	if role != domain.RoleAdmin && detail.UserID != uid {
+	if !can(r, domain.PermOrdersReadAll) && !scope(r).Sees(detail.UserID, detail.OrganizationID) {
 		http.Error(w, "forbidden", 403)
 		return
 	}
+	setETag(w, detail.Version)
 	_ = json.NewEncoder(w).Encode(detail)
 }
 
+// CreateReturn godoc
+// @Summary Create return order
+// @Description Owner or orders.manage. Creates a return linked to a delivered or return_to_sender order, swapping origin and destination.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path integer true "Original order ID"
+// @Param request body object{observations=string} false "Return observations"
+// @Success 201 {object} domain.Order "Created return order"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /orders/{id}/return [post]
+func (h *Handler) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermOrdersManage) {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body struct {
+		Observations string `json:"observations"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+	}
+	o, err := h.Orders.CreateReturn(r.Context(), scope(r), can(r, domain.PermOrdersManage), uint(id64), body.Observations)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(o)
+}
+
+// GetOrderHistory godoc
+// @Summary Get order timeline
+// @Description Returns the status history of an order, including failed delivery attempts and reschedules. Owner or orders.read.all.
+// @Tags orders
+// @Produce json
+// @Param id path integer true "Order ID"
+// @Success 200 {array} domain.OrderStatusHistory "Order timeline"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /orders/{id}/history [get]
+func (h *Handler) GetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	list, err := h.Orders.GetHistory(r.Context(), scope(r), can(r, domain.PermOrdersReadAll), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
 // ListPackageTypes godoc
 // @Summary List package types
-// @Description Returns package types. If ?all=1 and requester is admin, includes inactive; otherwise only active.
+// @Description Returns package types. If ?all=1 and requester has package_types.manage, includes inactive; otherwise only active.
 // @Tags package_types
 // @Produce json
-// @Param all query string false "If set to 1 and requester is admin, returns active and inactive"
+// @Param all query string false "If set to 1 and requester has package_types.manage, returns active and inactive"
 // @Success 200 {array} domain.PackageType
 // @Failure 401 {string} string "Unauthorized"
 // @Security BearerAuth
 // @Router /package-types [get]
 func (h *Handler) ListPackageTypes(w http.ResponseWriter, r *http.Request) {
-	_, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
 	includeInactive := false
-	if role == domain.RoleAdmin && r.URL.Query().Get("all") == "1" {
+	if can(r, domain.PermPackageTypesManage) && r.URL.Query().Get("all") == "1" {
 		includeInactive = true
 	}
-	list, err := h.PackageTypes.List(includeInactive)
+	list, err := h.PackageTypes.List(r.Context(), includeInactive)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
@@ -390,7 +591,7 @@ func (h *Handler) ListPackageTypes(w http.ResponseWriter, r *http.Request) {
 
 // SetPackageTypeActive godoc
 // @Summary Set PackageType active status
-// @Description Admin only. Sets is_active true/false for a PackageType
+// @Description Requires package_types.manage. Sets is_active true/false for a PackageType
 // @Tags package_types
 // @Accept json
 // @Param id path integer true "PackageType ID"
@@ -402,13 +603,8 @@ func (h *Handler) ListPackageTypes(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /package-types/{id}/active [patch]
 func (h *Handler) SetPackageTypeActive(w http.ResponseWriter, r *http.Request) {
-	_, role, ok := auth(r)
+	_, _, ok := h.authorize(w, r, domain.PermPackageTypesManage)
 	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
-	if role != domain.RoleAdmin {
-		http.Error(w, "forbidden", 403)
 		return
 	}
 	idStr := mux.Vars(r)["id"]
@@ -420,7 +616,7 @@ func (h *Handler) SetPackageTypeActive(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	if err := h.PackageTypes.ToggleActive(uint(id64), body.Active); err != nil {
+	if err := h.PackageTypes.ToggleActive(r.Context(), uint(id64), body.Active); err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
@@ -430,7 +626,7 @@ func (h *Handler) SetPackageTypeActive(w http.ResponseWriter, r *http.Request) {
 // Addresses Handlers
 // CreateAddress
 // @Summary Create address (with coordinates)
-// @Description Creates coordinates first if provided, then address; CustomerID is set from JWT. Clients create their own; admins can also create for themselves only in this endpoint.
+// @Description Creates coordinates first if provided, then address; CustomerID is set from JWT. Clients create their own; staff can also create for themselves only in this endpoint.
 // @Tags addresses
 // @Accept json
 // @Produce json
@@ -441,13 +637,8 @@ func (h *Handler) SetPackageTypeActive(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /addresses [post]
 func (h *Handler) CreateAddress(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
+	_, _, ok := h.authorize(w, r, domain.PermAddressesCreate)
 	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
-	if role != domain.RoleClient && role != domain.RoleAdmin {
-		http.Error(w, "forbidden", 403)
 		return
 	}
 	var req usecase.AddressRequest
@@ -455,7 +646,7 @@ func (h *Handler) CreateAddress(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	addr, _, err := h.Addresses.Create(uid, req)
+	addr, _, err := h.Addresses.Create(r.Context(), scope(r), req)
 	if err != nil {
 		http.Error(w, err.Error(), 400)
 		return
@@ -466,32 +657,30 @@ func (h *Handler) CreateAddress(w http.ResponseWriter, r *http.Request) {
 
 // ListAddresses
 // @Summary List addresses
-// @Description Clients see only their addresses (active by default). Admins can pass ?all=1 to see all, and ?include_inactive=1 to include inactive.
+// @Description Clients see only their addresses (active by default). Staff with addresses.manage.all can pass ?all=1 to see all, ?customer_id to see one customer, and ?include_inactive=1 to include inactive.
 // @Tags addresses
 // @Produce json
-// @Param all query string false "Admin only: if set to 1, list all users' addresses"
-// @Param include_inactive query string false "Admin only: if set to 1, include inactive addresses"
+// @Param all query string false "addresses.manage.all only: if set to 1, list all users' addresses"
+// @Param include_inactive query string false "addresses.manage.all only: if set to 1, include inactive addresses"
 // @Param customer_id query string false "Represent customer ID; if set, only addresses for this customer are returned"
 // @Success 200 {array} domain.Address
 // @Failure 401 {string} string "Unauthorized"
 // @Security BearerAuth
 // @Router /addresses [get]
 func (h *Handler) ListAddresses(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
+	sc := scope(r)
 
-	if idStr := r.URL.Query().Get("customer_id"); idStr != "" {
+	canManage := can(r, domain.PermAddressesManageAll)
+	// Only staff allowed to manage every address may look at another customer's
+	if idStr := r.URL.Query().Get("customer_id"); idStr != "" && canManage {
 		if id64, err := strconv.ParseUint(idStr, 10, 64); err == nil {
-			uid = uint(id64)
+			sc = domain.Scope{UserID: uint(id64)}
 		}
 	}
 
-	includeInactive := role == domain.RoleAdmin && r.URL.Query().Get("include_inactive") == "1"
-	all := role == domain.RoleAdmin && r.URL.Query().Get("all") == "1"
-	list, err := h.Addresses.List(uid, role, includeInactive, all)
+	includeInactive := canManage && r.URL.Query().Get("include_inactive") == "1"
+	all := canManage && r.URL.Query().Get("all") == "1"
+	list, err := h.Addresses.List(r.Context(), sc, canManage, includeInactive, all)
 
 	if err != nil {
 		http.Error(w, err.Error(), 500)
@@ -502,6 +691,7 @@ func (h *Handler) ListAddresses(w http.ResponseWriter, r *http.Request) {
 
 // GetAddress
 // @Summary Get single address
+// @Description Returns the address with its version as ETag
 // @Tags addresses
 // @Produce json
 // @Param id path integer true "Address ID"
@@ -511,39 +701,37 @@ func (h *Handler) ListAddresses(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /addresses/{id} [get]
 func (h *Handler) GetAddress(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
 	idStr := mux.Vars(r)["id"]
 	id64, _ := strconv.ParseUint(idStr, 10, 64)
-	isAdmin := role == domain.RoleAdmin
-	a, err := h.Addresses.Get(uid, isAdmin, uint(id64))
+	isAdmin := can(r, domain.PermAddressesManageAll)
+	a, err := h.Addresses.Get(r.Context(), scope(r), isAdmin, uint(id64))
 	if err != nil {
 		http.Error(w, err.Error(), 404)
 		return
 	}
+	setETag(w, a.Version)
 	_ = json.NewEncoder(w).Encode(a)
 }
 
 // UpdateAddress
 // @Summary Update address (and coordinates)
+// @Description Requires If-Match with the address ETag; the new ETag is returned.
 // @Tags addresses
 // @Accept json
 // @Produce json
 // @Param id path integer true "Address ID"
+// @Param If-Match header string true "ETag of the address as read by the client"
 // @Param request body object{street=string,exterior_number=string,interior_number=string,neighborhood=string,postal_code=string,city=string,state=string,country=string,is_active=boolean,coordinates=object{latitude=number,longitude=number}} true "Address update"
 // @Success 200 {object} domain.Address
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 403 {string} string "Forbidden"
+// @Failure 412 {string} string "Address changed since it was read"
+// @Failure 428 {string} string "If-Match required"
 // @Security BearerAuth
 // @Router /addresses/{id} [put]
 func (h *Handler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
+	if !h.authorizeAny(w, r, domain.PermAddressesCreate, domain.PermAddressesManageAll) {
 		return
 	}
 	idStr := mux.Vars(r)["id"]
@@ -553,17 +741,22 @@ func (h *Handler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	addr, _, err := h.Addresses.Update(uid, role == domain.RoleAdmin, uint(id64), req)
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+	addr, _, err := h.Addresses.Update(r.Context(), scope(r), can(r, domain.PermAddressesManageAll), uint(id64), version, req)
 	if err != nil {
-		http.Error(w, err.Error(), 400)
+		http.Error(w, err.Error(), errStatus(err, 400))
 		return
 	}
+	setETag(w, addr.Version)
 	_ = json.NewEncoder(w).Encode(addr)
 }
 
 // DeleteAddress
 // @Summary Delete address
-// @Description Deletes address only if it is not referenced by any order. Owner or admin only.
+// @Description Deletes address only if it is not referenced by any order. Owner or addresses.manage.all only.
 // @Tags addresses
 // @Param id path integer true "Address ID"
 // @Success 204 "No content"
@@ -573,14 +766,12 @@ func (h *Handler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /addresses/{id} [delete]
 func (h *Handler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
+	if !h.authorizeAny(w, r, domain.PermAddressesCreate, domain.PermAddressesManageAll) {
 		return
 	}
 	idStr := mux.Vars(r)["id"]
 	id64, _ := strconv.ParseUint(idStr, 10, 64)
-	if err := h.Addresses.Delete(uid, role == domain.RoleAdmin, uint(id64)); err != nil {
+	if err := h.Addresses.Delete(r.Context(), scope(r), can(r, domain.PermAddressesManageAll), uint(id64)); err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
@@ -589,20 +780,22 @@ func (h *Handler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
 
 // SetAddressActive
 // @Summary Set Address active status
+// @Description Requires If-Match with the address ETag; the new ETag is returned.
 // @Tags addresses
 // @Accept json
 // @Param id path integer true "Address ID"
+// @Param If-Match header string true "ETag of the address as read by the client"
 // @Param request body object{active=boolean} true "Desired active state"
 // @Success 204 "No content"
 // @Failure 400 {string} string "Bad request"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 403 {string} string "Forbidden"
+// @Failure 412 {string} string "Address changed since it was read"
+// @Failure 428 {string} string "If-Match required"
 // @Security BearerAuth
 // @Router /addresses/{id}/active [patch]
 func (h *Handler) SetAddressActive(w http.ResponseWriter, r *http.Request) {
-	uid, role, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
+	if !h.authorizeAny(w, r, domain.PermAddressesCreate, domain.PermAddressesManageAll) {
 		return
 	}
 	idStr := mux.Vars(r)["id"]
@@ -614,10 +807,16 @@ func (h *Handler) SetAddressActive(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	if err := h.Addresses.ToggleActive(uid, role == domain.RoleAdmin, uint(id64), body.Active); err != nil {
-		http.Error(w, err.Error(), 400)
+	version, ok := ifMatch(w, r)
+	if !ok {
 		return
 	}
+	newVersion, err := h.Addresses.ToggleActive(r.Context(), scope(r), can(r, domain.PermAddressesManageAll), uint(id64), version, body.Active)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	setETag(w, newVersion)
 	w.WriteHeader(204)
 }
 
@@ -630,11 +829,6 @@ func (h *Handler) SetAddressActive(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /orders/status [get]
 func (h *Handler) GetOrderStatus(w http.ResponseWriter, r *http.Request) {
-	_, _, ok := auth(r)
-	if !ok {
-		http.Error(w, "unauthorized", 401)
-		return
-	}
 
 	statuses := []map[string]string{
 		{"value": string(domain.OrderCreated), "label": "Creado"},
@@ -643,23 +837,30 @@ func (h *Handler) GetOrderStatus(w http.ResponseWriter, r *http.Request) {
 		{"value": string(domain.OrderInRoute), "label": "En Ruta"},
 		{"value": string(domain.OrderDelivered), "label": "Entregado"},
 		{"value": string(domain.OrderCancelled), "label": "Cancelado"},
+		{"value": string(domain.OrderDeliveryFailed), "label": "Intento Fallido"},
+		{"value": string(domain.OrderReturnToSender), "label": "Devolución al Remitente"},
+		{"value": string(domain.OrderReturned), "label": "Devuelto"},
 	}
 	_ = json.NewEncoder(w).Encode(statuses)
 }
 
-func auth(r *http.Request) (uint, domain.Role, bool) {
-	h := r.Header.Get("Authorization")
-	if h == "" || !strings.HasPrefix(h, "Bearer ") {
-		return 0, "", false
-	}
-	tokStr := strings.TrimPrefix(h, "Bearer ")
-	token, err := jwt.ParseWithClaims(tokStr, &claims{}, func(t *jwt.Token) (interface{}, error) { return jwtKey(), nil })
-	if err != nil || !token.Valid {
-		return 0, "", false
-	}
-	cl, ok := token.Claims.(*claims)
-	if !ok {
-		return 0, "", false
-	}
-	return cl.UserID, cl.Role, true
+// errStatus maps usecase sentinel errors to HTTP status codes, falling back to def
+func errStatus(err error, def int) int {
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		return 404
+	case errors.Is(err, usecase.ErrUnauthorized):
+		return 401
+	case errors.Is(err, usecase.ErrForbidden):
+		return 403
+	case errors.Is(err, usecase.ErrConflict):
+		return 409
+	case errors.Is(err, usecase.ErrUnprocessable):
+		return 422
+	case errors.Is(err, usecase.ErrPreconditionFailed):
+		return 412
+	case errors.Is(err, usecase.ErrInvalid):
+		return 400
+	}
+	return def
 }
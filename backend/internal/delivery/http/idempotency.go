@@ -0,0 +1,71 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+)
+
+// responseRecorder keeps a copy of the response written to the client
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	if rr.status == 0 {
+		rr.status = code
+	}
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+// idempotent runs next once per Idempotency-Key header, user and endpoint. Retries with the
+// same body get the stored response replayed; requests without the header run as usual.
+// Once the key is reserved, next and the bookkeeping ignore client disconnects: a cancelled
+// context would leave the key pending and every retry would get 409 until it expires.
+func (h *Handler) idempotent(w http.ResponseWriter, r *http.Request, uid uint, endpoint string, body []byte, next func(w http.ResponseWriter, r *http.Request)) {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" || h.Idempotency == nil {
+		next(w, r)
+		return
+	}
+
+	rec, replay, err := h.Idempotency.Begin(r.Context(), uid, endpoint, key, body)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	if replay {
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(rec.StatusCode)
+		_, _ = w.Write(rec.ResponseBody)
+		return
+	}
+
+	ctx := context.WithoutCancel(r.Context())
+	rr := &responseRecorder{ResponseWriter: w}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = h.Idempotency.Release(ctx, rec)
+			panic(p)
+		}
+	}()
+	next(rr, r.WithContext(ctx))
+
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	if err := h.Idempotency.Complete(ctx, rec, rr.status, rr.body.Bytes()); err != nil {
+		log.Printf("idempotency key %q not stored: %v", key, err)
+	}
+}
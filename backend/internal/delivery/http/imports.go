@@ -0,0 +1,65 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/sheet"
+	"logistics-app/backend/internal/usecase"
+)
+
+// 10 MB is far above MaxImportRows rows of order data
+const maxImportBytes = 10 << 20
+
+// ImportOrders godoc
+// @Summary Bulk import orders
+// @Description Creates one order per row of a CSV or XLSX file (first sheet) for the requester, matching or creating the origin and destination addresses. Every row is validated with the order creation rules. With dry_run=1 nothing is stored. mode=atomic (default) stores all rows or none (422 if any fails); mode=row stores the valid rows and reports the failed ones.
+// @Tags orders
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file with a header row"
+// @Param dry_run query string false "If set to 1, validates without storing"
+// @Param mode query string false "atomic (default) or row"
+// @Success 200 {object} usecase.OrderImportReport
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 422 {object} usecase.OrderImportReport "Atomic import rolled back"
+// @Security BearerAuth
+// @Router /orders/import [post]
+func (h *Handler) ImportOrders(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermOrdersCreate)
+	if !ok {
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file requerido: "+err.Error(), 400)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	records, err := sheet.Read(data)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "1"
+	mode := usecase.ImportMode(r.URL.Query().Get("mode"))
+	report, err := h.Imports.Import(r.Context(), scope(r), records, dryRun, mode)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if !report.DryRun && !report.Committed {
+		w.WriteHeader(422)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
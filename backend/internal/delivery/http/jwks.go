@@ -0,0 +1,14 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// JWKS serves the public keys that verify access tokens at /.well-known/jwks.json, outside the /api
+// base path. Each token names its key in the kid header; the set is empty with HS256 secrets.
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(h.Keys.JWKS())
+}
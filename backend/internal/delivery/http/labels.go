@@ -0,0 +1,58 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/label"
+
+	"github.com/gorilla/mux"
+)
+
+// GetOrderLabel godoc
+// @Summary Get shipping label
+// @Description Renders the 4x6 shipping label of an order with a Code128 barcode of the order number and a QR code linking to tracking. Owner or orders.read.all.
+// @Tags orders
+// @Produce application/pdf
+// @Produce application/zpl
+// @Produce image/png
+// @Param id path integer true "Order ID"
+// @Param format query string false "pdf (default), zpl or png"
+// @Success 200 {file} file "Shipping label"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Security BearerAuth
+// @Router /orders/{id}/label [get]
+func (h *Handler) GetOrderLabel(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	format := label.Format(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		format = label.FormatPDF
+	}
+	lbl, err := h.Orders.GetShippingLabel(r.Context(), scope(r), can(r, domain.PermOrdersReadAll), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	lbl.TrackingURL = strings.TrimRight(getenv("TRACKING_BASE_URL", "http://localhost:3000/tracking"), "/") + "/" + lbl.OrderNumber
+	body, contentType, err := label.Render(format, *lbl)
+	if err != nil {
+		if errors.Is(err, label.ErrUnsupportedFormat) {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", lbl.OrderNumber+"."+string(format)))
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/manifest"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/gorilla/mux"
+)
+
+// CreateManifest godoc
+// @Summary Create dispatch manifest
+// @Description Requires manifests.manage. Builds a draft manifest with the orders that left the station on the date (outbound or load scans), optionally limited to the parcels loaded into a route.
+// @Tags manifests
+// @Accept json
+// @Produce json
+// @Param request body usecase.ManifestRequest true "Manifest"
+// @Success 201 {object} domain.Manifest
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /manifests [post]
+func (h *Handler) CreateManifest(w http.ResponseWriter, r *http.Request) {
+	uid, _, ok := h.authorize(w, r, domain.PermManifestsManage)
+	if !ok {
+		return
+	}
+	var req usecase.ManifestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	m, err := h.Manifests.Create(r.Context(), req, uid)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(m)
+}
+
+// ListManifests godoc
+// @Summary List manifests
+// @Description Requires manifests.manage. Filtered by station and day.
+// @Tags manifests
+// @Produce json
+// @Param station_id query integer false "Station ID"
+// @Param date query string false "Manifest day (YYYY-MM-DD)"
+// @Success 200 {array} domain.Manifest
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /manifests [get]
+func (h *Handler) ListManifests(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermManifestsManage)
+	if !ok {
+		return
+	}
+	stationID, _ := strconv.ParseUint(r.URL.Query().Get("station_id"), 10, 64)
+	list, err := h.Manifests.List(r.Context(), uint(stationID), r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// GetManifest godoc
+// @Summary Get manifest
+// @Description Requires manifests.manage. Manifest with its orders.
+// @Tags manifests
+// @Produce json
+// @Param id path integer true "Manifest ID"
+// @Success 200 {object} domain.Manifest
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /manifests/{id} [get]
+func (h *Handler) GetManifest(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermManifestsManage)
+	if !ok {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	m, err := h.Manifests.Get(r.Context(), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(m)
+}
+
+// RefreshManifest godoc
+// @Summary Refresh draft manifest
+// @Description Requires manifests.manage. Re-collects the orders of a draft manifest. Issued manifests are frozen (409).
+// @Tags manifests
+// @Produce json
+// @Param id path integer true "Manifest ID"
+// @Success 200 {object} domain.Manifest
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Manifest already issued"
+// @Security BearerAuth
+// @Router /manifests/{id}/refresh [post]
+func (h *Handler) RefreshManifest(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermManifestsManage)
+	if !ok {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	m, err := h.Manifests.Refresh(r.Context(), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(m)
+}
+
+// IssueManifest godoc
+// @Summary Issue manifest
+// @Description Requires manifests.manage. Freezes the manifest and records its ID in the history of every listed order.
+// @Tags manifests
+// @Produce json
+// @Param id path integer true "Manifest ID"
+// @Success 200 {object} domain.Manifest
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Manifest already issued"
+// @Security BearerAuth
+// @Router /manifests/{id}/issue [post]
+func (h *Handler) IssueManifest(w http.ResponseWriter, r *http.Request) {
+	uid, _, ok := h.authorize(w, r, domain.PermManifestsManage)
+	if !ok {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	m, err := h.Manifests.Issue(r.Context(), uint(id64), uid)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(m)
+}
+
+// GetManifestDocument godoc
+// @Summary Get manifest document
+// @Description Requires manifests.manage. Renders the manifest with pieces, weights, destinations and signature lines.
+// @Tags manifests
+// @Produce application/pdf
+// @Produce text/csv
+// @Param id path integer true "Manifest ID"
+// @Param format query string false "pdf (default) or csv"
+// @Success 200 {file} file "Manifest document"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 500 {string} string "Internal server error"
+// @Security BearerAuth
+// @Router /manifests/{id}/document [get]
+func (h *Handler) GetManifestDocument(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermManifestsManage)
+	if !ok {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	format := manifest.Format(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		format = manifest.FormatPDF
+	}
+	m, err := h.Manifests.Get(r.Context(), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	body, contentType, err := manifest.Render(format, *m)
+	if err != nil {
+		if errors.Is(err, manifest.ErrUnsupportedFormat) {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	disposition := "inline"
+	if format == manifest.FormatCSV {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, m.ManifestNumber+"."+string(format)))
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,297 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/label"
+)
+
+// mfaChallengeTTL is how long the user has to enter the second factor after the password
+const mfaChallengeTTL = 5 * time.Minute
+
+const purposeMFA = "mfa"
+
+type mfaChallengeResponse struct {
+	MFARequired bool `json:"mfa_required"`
+	// The account must enroll first, using the mfa_token at /mfa/enroll and /mfa/confirm
+	EnrollmentRequired bool   `json:"enrollment_required"`
+	MFAToken           string `json:"mfa_token"`
+	ExpiresIn          int    `json:"expires_in"`
+}
+
+type mfaEnrollResponse struct {
+	Secret string `json:"secret"`
+	URL    string `json:"otpauth_url"`
+	// PNG QR code of otpauth_url as a data URL
+	QRCode string `json:"qr_code"`
+}
+
+type mfaCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	// Set when the confirmation completed a login
+	*tokenResponse
+}
+
+func (h *Handler) writeMFAChallenge(w http.ResponseWriter, u *domain.User) {
+	s, err := h.signToken(u, "", purposeMFA, mfaChallengeTTL)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(202)
+	_ = json.NewEncoder(w).Encode(mfaChallengeResponse{
+		MFARequired:        true,
+		EnrollmentRequired: !u.MFAEnabled,
+		MFAToken:           s,
+		ExpiresIn:          int(mfaChallengeTTL.Seconds()),
+	})
+}
+
+// mfaChallenge verifies an MFA challenge token that was not used yet
+func (h *Handler) mfaChallenge(ctx context.Context, token string) (*claims, bool) {
+	t, err := h.Keys.Parse(strings.TrimSpace(token), &claims{})
+	if err != nil || !t.Valid {
+		return nil, false
+	}
+	cl, ok := t.Claims.(*claims)
+	if !ok || cl.Purpose != purposeMFA || h.Sessions.IsRevoked(ctx, cl.ID) {
+		return nil, false
+	}
+	return cl, true
+}
+
+// mfaCaller accepts an access token or, during a login that requires enrollment, the MFA challenge token
+func (h *Handler) mfaCaller(r *http.Request) (uid uint, challenge *claims, ok bool) {
+	if p, err := h.authenticate(r); err == nil {
+		return p.UserID, nil, true
+	}
+	cl, ok := h.mfaChallenge(r.Context(), strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if !ok {
+		return 0, nil, false
+	}
+	return cl.UserID, cl, true
+}
+
+// completeLogin closes the challenge so it can't be used twice and opens the session
+func (h *Handler) completeLogin(ctx context.Context, u *domain.User, challenge *claims) (*tokenResponse, error) {
+	if err := h.Sessions.Logout(ctx, "", challenge.ID, challenge.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+	if err := h.LoginGuard.Success(ctx, u.Email); err != nil {
+		log.Printf("login throttle: %v", err)
+	}
+	sessionID, refresh, err := h.Sessions.Start(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	return h.newTokenResponse(u, sessionID, refresh)
+}
+
+// LoginMFA godoc
+// @Summary Complete login with second factor
+// @Description Exchanges the mfa_token returned by /login and a TOTP or recovery code for access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body object{mfa_token=string,code=string} true "Challenge token and TOTP or recovery code"
+// @Success 200 {object} tokenResponse "Access and refresh tokens"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Invalid challenge or code"
+// @Failure 403 {string} string "MFA enrollment required"
+// @Failure 429 {string} string "Too many failed attempts, see Retry-After"
+// @Router /login/mfa [post]
+func (h *Handler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	cl, ok := h.mfaChallenge(r.Context(), body.MFAToken)
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	u, err := h.Users.GetByID(r.Context(), cl.UserID)
+	if err != nil || !u.IsActive {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	if !u.MFAEnabled {
+		http.Error(w, "debe activar la autenticación de dos factores", 403)
+		return
+	}
+
+	ip := clientIP(r)
+	if err := h.LoginGuard.Check(r.Context(), u.Email, ip); err != nil {
+		if !writeLocked(w, err) {
+			http.Error(w, err.Error(), 500)
+		}
+		return
+	}
+	if err := h.MFA.Verify(r.Context(), u.ID, body.Code); err != nil {
+		if err := h.LoginGuard.Failure(r.Context(), u.Email, ip); err != nil {
+			log.Printf("login throttle: %v", err)
+		}
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+
+	resp, err := h.completeLogin(r.Context(), u, cl)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// EnrollMFA godoc
+// @Summary Start MFA enrollment
+// @Description Creates a TOTP secret and returns it with its otpauth URL and QR code. Accepts an access token or the mfa_token of a login that requires enrollment. MFA is active only after /mfa/confirm.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} mfaEnrollResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "MFA already enabled"
+// @Security BearerAuth
+// @Router /mfa/enroll [post]
+func (h *Handler) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	uid, _, ok := h.mfaCaller(r)
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	e, err := h.MFA.Enroll(r.Context(), uid)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	png, err := label.QRCodePNG(e.URL, 256)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(mfaEnrollResponse{
+		Secret: e.Secret,
+		URL:    e.URL,
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	})
+}
+
+// ConfirmMFA godoc
+// @Summary Confirm MFA enrollment
+// @Description Enables MFA with a code from the authenticator app and returns 10 single-use recovery codes, shown only once. When called with an mfa_token the login is completed and tokens are included.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body object{code=string} true "TOTP code"
+// @Success 200 {object} mfaCodesResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized or invalid code"
+// @Failure 409 {string} string "MFA already enabled"
+// @Security BearerAuth
+// @Router /mfa/confirm [post]
+func (h *Handler) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	uid, challenge, ok := h.mfaCaller(r)
+	if !ok {
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	codes, err := h.MFA.Confirm(r.Context(), uid, body.Code)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+
+	resp := mfaCodesResponse{RecoveryCodes: codes}
+	if challenge != nil {
+		u, err := h.Users.GetByID(r.Context(), uid)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		if resp.tokenResponse, err = h.completeLogin(r.Context(), u, challenge); err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate MFA recovery codes
+// @Description Replaces the recovery codes after checking a current TOTP or recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body object{code=string} true "TOTP or recovery code"
+// @Success 200 {object} mfaCodesResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized or invalid code"
+// @Security BearerAuth
+// @Router /mfa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	uid, _ := caller(r)
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	codes, err := h.MFA.RegenerateRecoveryCodes(r.Context(), uid, body.Code)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(mfaCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA godoc
+// @Summary Disable MFA
+// @Description Turns MFA off after checking a current TOTP or recovery code. Not allowed for roles where MFA is mandatory.
+// @Tags auth
+// @Accept json
+// @Param request body object{code=string} true "TOTP or recovery code"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized or invalid code"
+// @Failure 403 {string} string "MFA is mandatory for the role"
+// @Security BearerAuth
+// @Router /mfa/disable [post]
+func (h *Handler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	uid, role := caller(r)
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.MFA.Disable(r.Context(), uid, role, body.Code); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	w.WriteHeader(204)
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/gorilla/mux"
+)
+
+// EditOrder godoc
+// @Summary Edit order
+// @Description Owner or orders.manage, only while the order is `created`. Changes destination, package type, weight, quantity or observations; omitted fields are kept. Requires If-Match with the order ETag; the new ETag is returned.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path integer true "Order ID"
+// @Param If-Match header string true "ETag of the order as read by the client"
+// @Param request body usecase.OrderEditRequest true "Fields to change"
+// @Success 200 {object} domain.Order
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Order is no longer editable"
+// @Failure 412 {string} string "Order changed since it was read"
+// @Failure 428 {string} string "If-Match required"
+// @Security BearerAuth
+// @Router /orders/{id} [patch]
+func (h *Handler) EditOrder(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermOrdersManage) {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var req usecase.OrderEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	version, ok := ifMatch(w, r)
+	if !ok {
+		return
+	}
+	o, _, err := h.OrderEdits.Edit(r.Context(), scope(r), can(r, domain.PermOrdersManage), uint(id64), version, req)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	setETag(w, o.Version)
+	_ = json.NewEncoder(w).Encode(o)
+}
+
+// ListOrderChanges godoc
+// @Summary Order change log
+// @Description Owner or orders.read.all. Field-level changes made by order edits, oldest first; rows with the same version belong to the same edit.
+// @Tags orders
+// @Produce json
+// @Param id path integer true "Order ID"
+// @Success 200 {array} domain.OrderChange
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /orders/{id}/changes [get]
+func (h *Handler) ListOrderChanges(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	list, err := h.OrderEdits.ListChanges(r.Context(), scope(r), can(r, domain.PermOrdersReadAll), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
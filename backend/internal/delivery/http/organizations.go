@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"logistics-app/backend/internal/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// CreateOrganization godoc
+// @Summary Create organization
+// @Description Opens an organization with the caller as its owner. Only clients that don't belong to an organization yet. Addresses, orders and pickups created afterwards belong to the organization.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param request body object{name=string} true "Organization"
+// @Success 201 {object} domain.Organization
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 409 {string} string "Already a member of an organization"
+// @Security BearerAuth
+// @Router /organization [post]
+func (h *Handler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	uid, _ := caller(r)
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	o, err := h.Organizations.Create(r.Context(), uid, body.Name)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(o)
+}
+
+// GetOrganization godoc
+// @Summary Get my organization
+// @Description Returns the caller's organization with its members
+// @Tags organizations
+// @Produce json
+// @Success 200 {object} domain.Organization
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "Not a member of an organization"
+// @Security BearerAuth
+// @Router /organization [get]
+func (h *Handler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	o, err := h.Organizations.Get(r.Context(), scope(r))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(o)
+}
+
+// InviteMember godoc
+// @Summary Invite member
+// @Description Owner only. Emails a single-use link to join the organization with the given role (owner, shipper or viewer). Inviting the same email again revokes the earlier link.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param request body object{email=string,role=string} true "Invitation"
+// @Success 201 {object} domain.OrganizationInvitation
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not a member of an organization"
+// @Failure 409 {string} string "Already a member"
+// @Security BearerAuth
+// @Router /organization/invitations [post]
+func (h *Handler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Email string         `json:"email"`
+		Role  domain.OrgRole `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	inv, err := h.Organizations.Invite(r.Context(), scope(r), body.Email, body.Role)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(inv)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept invitation
+// @Description Joins the organization of an invitation addressed to the caller's email. Users already in an organization must leave it first.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param request body object{token=string} true "Invitation token from the email"
+// @Success 200 {object} domain.Organization
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Invitation for another email"
+// @Failure 409 {string} string "Already a member of an organization"
+// @Failure 422 {string} string "Invalid, used or expired invitation"
+// @Security BearerAuth
+// @Router /organization/invitations/accept [post]
+func (h *Handler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	uid, _ := caller(r)
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	o, err := h.Organizations.Accept(r.Context(), uid, body.Token)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(o)
+}
+
+// UpdateMemberRole godoc
+// @Summary Change member role
+// @Description Owner only. The organization always keeps at least one owner.
+// @Tags organizations
+// @Accept json
+// @Param id path integer true "User ID of the member"
+// @Param request body object{role=string} true "New role: owner, shipper or viewer"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Member not found"
+// @Failure 409 {string} string "Last owner"
+// @Security BearerAuth
+// @Router /organization/members/{id} [patch]
+func (h *Handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body struct {
+		Role domain.OrgRole `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.Organizations.UpdateMemberRole(r.Context(), scope(r), uint(id64), body.Role); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// RemoveMember godoc
+// @Summary Remove member
+// @Description Owners remove any member and members can remove themselves to leave. The organization keeps the rows they created and always keeps at least one owner.
+// @Tags organizations
+// @Param id path integer true "User ID of the member"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Member not found"
+// @Failure 409 {string} string "Last owner"
+// @Security BearerAuth
+// @Router /organization/members/{id} [delete]
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	if err := h.Organizations.RemoveMember(r.Context(), scope(r), uint(id64)); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(204)
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/gorilla/mux"
+)
+
+// ListPickupSlots godoc
+// @Summary List pickup slots
+// @Description Returns the pickup time windows and their capacity per zone. An empty zone is the default for every zone.
+// @Tags pickups
+// @Produce json
+// @Success 200 {array} domain.PickupSlot
+// @Failure 401 {string} string "Unauthorized"
+// @Security BearerAuth
+// @Router /pickup-slots [get]
+func (h *Handler) ListPickupSlots(w http.ResponseWriter, r *http.Request) {
+	list, err := h.Pickups.ListSlots(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// SavePickupSlot godoc
+// @Summary Create or update pickup slot
+// @Description Requires pickup_slots.manage. Sets the capacity of a zone and time window (HH:MM).
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Param request body object{zone=string,window_start=string,window_end=string,capacity=integer,is_active=boolean} true "Pickup slot"
+// @Success 200 {object} domain.PickupSlot
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /pickup-slots [put]
+func (h *Handler) SavePickupSlot(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermPickupSlotsManage)
+	if !ok {
+		return
+	}
+	slot := domain.PickupSlot{IsActive: true}
+	if err := json.NewDecoder(r.Body).Decode(&slot); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	slot.ID = 0
+	if err := h.Pickups.SaveSlot(r.Context(), &slot); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(slot)
+}
+
+// BookPickup godoc
+// @Summary Book pickup
+// @Description Requires orders.create or pickups.manage.all. Schedules a courier pickup at an origin address within a time window, optionally grouping created orders from that address.
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Param request body usecase.PickupRequest true "Pickup request"
+// @Success 201 {object} domain.Pickup
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /pickups [post]
+func (h *Handler) BookPickup(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermPickupsManageAll) {
+		return
+	}
+	var req usecase.PickupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	p, err := h.Pickups.Book(r.Context(), scope(r), can(r, domain.PermPickupsManageAll), req)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// ListPickups godoc
+// @Summary List pickups
+// @Description Clients see their pickups. Staff with pickups.manage.all can pass ?all=1 to see every customer; ?date=YYYY-MM-DD returns the scheduled pickups due that day.
+// @Tags pickups
+// @Produce json
+// @Param all query string false "pickups.manage.all only: if set to 1, list pickups of all customers"
+// @Param date query string false "Only scheduled pickups due on this date (YYYY-MM-DD)"
+// @Success 200 {array} domain.Pickup
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Security BearerAuth
+// @Router /pickups [get]
+func (h *Handler) ListPickups(w http.ResponseWriter, r *http.Request) {
+	all := r.URL.Query().Get("all") == "1"
+	list, err := h.Pickups.List(r.Context(), scope(r), can(r, domain.PermPickupsManageAll), all, r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// GetPickup godoc
+// @Summary Get pickup
+// @Tags pickups
+// @Produce json
+// @Param id path integer true "Pickup ID"
+// @Success 200 {object} domain.Pickup
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /pickups/{id} [get]
+func (h *Handler) GetPickup(w http.ResponseWriter, r *http.Request) {
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	p, err := h.Pickups.Get(r.Context(), scope(r), can(r, domain.PermPickupsManageAll), uint(id64))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// ReschedulePickup godoc
+// @Summary Reschedule pickup
+// @Description Owner or pickups.manage.all. Moves a scheduled pickup to another date and time window.
+// @Tags pickups
+// @Accept json
+// @Produce json
+// @Param id path integer true "Pickup ID"
+// @Param request body object{date=string,window_start=string,window_end=string} true "New date and window"
+// @Success 200 {object} domain.Pickup
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /pickups/{id} [patch]
+func (h *Handler) ReschedulePickup(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermPickupsManageAll) {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body struct {
+		Date        string `json:"date"`
+		WindowStart string `json:"window_start"`
+		WindowEnd   string `json:"window_end"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	p, err := h.Pickups.Reschedule(r.Context(), scope(r), can(r, domain.PermPickupsManageAll), uint(id64), body.Date, body.WindowStart, body.WindowEnd)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// CancelPickup godoc
+// @Summary Cancel pickup
+// @Description Owner or pickups.manage.all. Cancels a scheduled pickup and releases its grouped orders.
+// @Tags pickups
+// @Param id path integer true "Pickup ID"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /pickups/{id}/cancel [patch]
+func (h *Handler) CancelPickup(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAny(w, r, domain.PermOrdersCreate, domain.PermPickupsManageAll) {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	if err := h.Pickups.Cancel(r.Context(), scope(r), can(r, domain.PermPickupsManageAll), uint(id64)); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(204)
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/export"
+
+	"github.com/gorilla/mux"
+)
+
+// ExportMyData godoc
+// @Summary Export own personal data
+// @Description Access right (ARCO / GDPR): returns a ZIP with the caller's profile, addresses, orders and their status history as JSON files. Each export is recorded in the audit log.
+// @Tags privacy
+// @Produce application/zip
+// @Success 200 {file} file "ZIP archive"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Security BearerAuth
+// @Router /me/export [get]
+func (h *Handler) ExportMyData(w http.ResponseWriter, r *http.Request) {
+	uid, _ := caller(r)
+	d, err := h.Privacy.Export(r.Context(), uid, clientIP(r))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	// Built in memory so a failure is still answered with an error status
+	var buf bytes.Buffer
+	if err := export.WritePersonalData(&buf, d); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="personal-data-%d-%s.zip"`, uid, d.ExportedAt.Format("20060102")))
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// RequestErasure godoc
+// @Summary Request erasure of own personal data
+// @Description Cancellation right (ARCO / GDPR): files a request, reviewed by an administrator, to anonymize the caller's account and personal addresses. Orders are kept for accounting. One request may be pending at a time.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param request body object{reason=string} false "Reason"
+// @Success 201 {object} domain.ErasureRequest
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 409 {string} string "A request is already pending"
+// @Security BearerAuth
+// @Router /me/erasure-request [post]
+func (h *Handler) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	uid, _ := caller(r)
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	e, err := h.Privacy.RequestErasure(r.Context(), uid, clientIP(r), body.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(e)
+}
+
+// ListErasureRequests godoc
+// @Summary List erasure requests
+// @Description Returns the personal data erasure requests, oldest first (privacy.manage only)
+// @Tags privacy
+// @Produce json
+// @Param status query string false "pending, completed or rejected"
+// @Success 200 {array} domain.ErasureRequest
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /erasure-requests [get]
+func (h *Handler) ListErasureRequests(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := h.authorize(w, r, domain.PermPrivacyManage); !ok {
+		return
+	}
+	list, err := h.Privacy.ListErasureRequests(r.Context(), domain.ErasureStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// reviewBody is the optional note of a review, required to reject
+type reviewBody struct {
+	Note string `json:"note"`
+}
+
+// ApproveErasure godoc
+// @Summary Approve erasure request
+// @Description Erases the requester's personal data at once: the account is anonymized and deleted and their personal addresses lose street and number; orders and organization addresses are kept. Needs privacy.manage, and another administrator than the requester. Recorded in the audit log.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param id path integer true "Erasure request ID"
+// @Param request body reviewBody false "Review note"
+// @Success 200 {object} domain.ErasureRequest
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Already reviewed, last admin or last owner"
+// @Security BearerAuth
+// @Router /erasure-requests/{id}/approve [post]
+func (h *Handler) ApproveErasure(w http.ResponseWriter, r *http.Request) {
+	p, _ := domain.PrincipalFrom(r.Context())
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body reviewBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	e, err := h.Privacy.ApproveErasure(r.Context(), *p, clientIP(r), uint(id64), body.Note)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(e)
+}
+
+// RejectErasure godoc
+// @Summary Reject erasure request
+// @Description Rejects a pending request with a note explaining why (privacy.manage only). Recorded in the audit log.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param id path integer true "Erasure request ID"
+// @Param request body reviewBody true "Reason of the rejection"
+// @Success 200 {object} domain.ErasureRequest
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Already reviewed"
+// @Security BearerAuth
+// @Router /erasure-requests/{id}/reject [post]
+func (h *Handler) RejectErasure(w http.ResponseWriter, r *http.Request) {
+	p, _ := domain.PrincipalFrom(r.Context())
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body reviewBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	e, err := h.Privacy.RejectErasure(r.Context(), *p, clientIP(r), uint(id64), body.Note)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(e)
+}
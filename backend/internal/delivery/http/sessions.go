@@ -0,0 +1,123 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// accessTokenTTL is short since sessions are renewed with the refresh token
+const accessTokenTTL = 15 * time.Minute
+
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// signToken signs a JWT for the user with a fresh id
+func (h *Handler) signToken(u *domain.User, sessionID, purpose string, ttl time.Duration) (string, error) {
+	jti, err := usecase.NewTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	cl := &claims{
+		UserID:    u.ID,
+		Role:      u.Role,
+		SessionID: sessionID,
+		Purpose:   purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	return h.Keys.Sign(cl)
+}
+
+func (h *Handler) newTokenResponse(u *domain.User, sessionID, refresh string) (*tokenResponse, error) {
+	s, err := h.signToken(u, sessionID, "", accessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &tokenResponse{
+		Token:        s,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// writeTokens signs an access token for the user's session and writes it with the refresh token
+func (h *Handler) writeTokens(w http.ResponseWriter, u *domain.User, sessionID, refresh string) {
+	resp, err := h.newTokenResponse(u, sessionID, refresh)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Each refresh token works once; presenting a used one revokes the whole session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body object{refresh_token=string} true "Refresh token"
+// @Success 200 {object} tokenResponse "Access and refresh tokens"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Invalid, expired or reused refresh token"
+// @Failure 500 {string} string "Internal server error"
+// @Router /token/refresh [post]
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	userID, sessionID, refresh, err := h.Sessions.Rotate(r.Context(), body.RefreshToken)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+
+	u, err := h.Users.GetByID(r.Context(), userID)
+	if err != nil || u == nil || !u.IsActive {
+		_ = h.Sessions.Logout(r.Context(), sessionID, "", time.Time{})
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+	h.writeTokens(w, u, sessionID, refresh)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revokes the current session: its refresh tokens stop working and the access token is rejected from now on
+// @Tags auth
+// @Success 204 "No content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Internal server error"
+// @Security BearerAuth
+// @Router /logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	p, _ := domain.PrincipalFrom(r.Context())
+	if err := h.Sessions.Logout(r.Context(), p.SessionID, p.TokenID, p.ExpiresAt); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.WriteHeader(204)
+}
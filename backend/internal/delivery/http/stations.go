@@ -0,0 +1,163 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/gorilla/mux"
+)
+
+// ListStations godoc
+// @Summary List stations
+// @Description Returns active stations. If ?all=1 and requester has stations.manage, includes inactive.
+// @Tags stations
+// @Produce json
+// @Param all query string false "If set to 1 and requester has stations.manage, returns active and inactive"
+// @Success 200 {array} domain.Station
+// @Failure 401 {string} string "Unauthorized"
+// @Security BearerAuth
+// @Router /stations [get]
+func (h *Handler) ListStations(w http.ResponseWriter, r *http.Request) {
+	includeInactive := can(r, domain.PermStationsManage) && r.URL.Query().Get("all") == "1"
+	list, err := h.Stations.List(r.Context(), includeInactive)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// CreateStation godoc
+// @Summary Create station
+// @Description Requires stations.manage.
+// @Tags stations
+// @Accept json
+// @Produce json
+// @Param request body object{code=string,name=string,city=string} true "Station"
+// @Success 201 {object} domain.Station
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /stations [post]
+func (h *Handler) CreateStation(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermStationsManage)
+	if !ok {
+		return
+	}
+	var st domain.Station
+	if err := json.NewDecoder(r.Body).Decode(&st); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.Stations.Create(r.Context(), &st); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(st)
+}
+
+// SetStationActive godoc
+// @Summary Set Station active status
+// @Description Requires stations.manage. Sets is_active true/false for a Station
+// @Tags stations
+// @Accept json
+// @Param id path integer true "Station ID"
+// @Param request body object{active=boolean} true "Desired active state"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /stations/{id}/active [patch]
+func (h *Handler) SetStationActive(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermStationsManage)
+	if !ok {
+		return
+	}
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var body struct {
+		Active bool `json:"active"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.Stations.ToggleActive(r.Context(), uint(id64), body.Active); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// RecordScan godoc
+// @Summary Record barcode scan
+// @Description Requires scans.record. Resolves the order by order_number and applies the status implied by the scan type (inbound, outbound, load, unload). Repeating the last scan returns it with 200 and changes nothing; scans that don't fit the order status are rejected with 409.
+// @Tags stations
+// @Accept json
+// @Produce json
+// @Param request body usecase.ScanRequest true "Scan"
+// @Success 200 {object} domain.Scan "Duplicate scan, already applied"
+// @Success 201 {object} domain.Scan "Applied scan"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Order not found"
+// @Failure 409 {string} string "Scan does not fit the order status"
+// @Security BearerAuth
+// @Router /scans [post]
+func (h *Handler) RecordScan(w http.ResponseWriter, r *http.Request) {
+	uid, _, ok := h.authorize(w, r, domain.PermScansRecord)
+	if !ok {
+		return
+	}
+	var req usecase.ScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	scan, duplicate, err := h.Scans.Record(r.Context(), req, uid)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	if !duplicate {
+		w.WriteHeader(201)
+	}
+	_ = json.NewEncoder(w).Encode(scan)
+}
+
+// ListScans godoc
+// @Summary List scans
+// @Description Requires scans.read. Full scan log, including rejected scans, filtered by order, station and day.
+// @Tags stations
+// @Produce json
+// @Param order_id query integer false "Order ID"
+// @Param station_id query integer false "Station ID"
+// @Param date query string false "Day of the scans (YYYY-MM-DD)"
+// @Success 200 {array} domain.Scan
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /scans [get]
+func (h *Handler) ListScans(w http.ResponseWriter, r *http.Request) {
+	_, _, ok := h.authorize(w, r, domain.PermScansRead)
+	if !ok {
+		return
+	}
+	orderID, _ := strconv.ParseUint(r.URL.Query().Get("order_id"), 10, 64)
+	stationID, _ := strconv.ParseUint(r.URL.Query().Get("station_id"), 10, 64)
+	list, err := h.Scans.List(r.Context(), uint(orderID), uint(stationID), r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(list)
+}
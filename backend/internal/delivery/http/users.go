@@ -0,0 +1,186 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/gorilla/mux"
+)
+
+// CreateUser godoc
+// @Summary Create user with a role
+// @Description Creates an account with any role, e.g. operators or administrators (users.manage only), and emails a verification link. Roles other than client need a password of at least 12 characters. The creation is recorded in the audit log.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body usecase.NewUserRequest true "User details"
+// @Success 201 {object} domain.User "Created user"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /admin/users [post]
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	p, _ := domain.PrincipalFrom(r.Context())
+	var req usecase.NewUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	u, err := h.Users.CreatePrivileged(r.Context(), *p, clientIP(r), req)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	// The link can be requested again, so a mail failure doesn't fail the creation
+	if err := h.Accounts.SendVerification(r.Context(), u); err != nil {
+		log.Printf("verification email to user %d: %v", u.ID, err)
+	}
+	w.WriteHeader(201)
+	_ = json.NewEncoder(w).Encode(u)
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description Returns a page of users ordered by id (users.read.all only). q searches email and full name.
+// @Tags users
+// @Produce json
+// @Param q query string false "Search in email and full name"
+// @Param role query string false "Role"
+// @Param active query boolean false "Active flag"
+// @Param page query integer false "Page, from 1"
+// @Param page_size query integer false "Page size, 20 by default and 100 at most"
+// @Success 200 {object} usecase.UserPage
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Router /users [get]
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := h.authorize(w, r, domain.PermUsersReadAll); !ok {
+		return
+	}
+
+	qs := r.URL.Query()
+	q := usecase.UserQuery{Query: qs.Get("q"), Role: domain.Role(qs.Get("role"))}
+	if v := qs.Get("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "active debe ser true o false", 400)
+			return
+		}
+		q.Active = &active
+	}
+	if v := qs.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "page inválido", 400)
+			return
+		}
+		q.Page = n
+	}
+	if v := qs.Get("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "page_size inválido", 400)
+			return
+		}
+		q.PageSize = n
+	}
+
+	page, err := h.Users.List(r.Context(), q)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// UpdateUser godoc
+// @Summary Update user
+// @Description Updates the fields sent. Users may change their own full_name and phone; role and is_active need users.manage and can't be changed on the own account. Deactivating a user ends its sessions, and the last active admin can't be demoted or deactivated.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path integer true "User ID"
+// @Param request body usecase.UserUpdate true "Fields to change"
+// @Success 200 {object} domain.User
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Last active admin"
+// @Security BearerAuth
+// @Router /users/{id} [patch]
+func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	p, _ := domain.PrincipalFrom(r.Context())
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	var req usecase.UserUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	u, err := h.Users.Update(r.Context(), *p, clientIP(r), uint(id64), req)
+	if err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(u)
+}
+
+// ChangePassword godoc
+// @Summary Change own password
+// @Description Sets a new password for the caller, who must confirm the current one. The caller's other sessions are ended; the current one stays signed in.
+// @Tags users
+// @Accept json
+// @Param request body object{current_password=string,new_password=string} true "Current and new password"
+// @Success 204 "No content"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Wrong current password"
+// @Security BearerAuth
+// @Router /users/me/password [post]
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	p, _ := domain.PrincipalFrom(r.Context())
+	var body struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if err := h.Users.ChangePassword(r.Context(), p.UserID, p.SessionID, body.CurrentPassword, body.NewPassword); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 400))
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// ForceLogout godoc
+// @Summary Force logout
+// @Description Ends every session of the user: refresh tokens stop working and access tokens already issued are rejected (users.manage or own account only).
+// @Tags users
+// @Param id path integer true "User ID"
+// @Success 204 "No content"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Router /users/{id}/logout [post]
+func (h *Handler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	p, _ := domain.PrincipalFrom(r.Context())
+	idStr := mux.Vars(r)["id"]
+	id64, _ := strconv.ParseUint(idStr, 10, 64)
+	if err := h.Users.ForceLogout(r.Context(), *p, uint(id64)); err != nil {
+		http.Error(w, err.Error(), errStatus(err, 500))
+		return
+	}
+	w.WriteHeader(204)
+}
@@ -12,8 +12,10 @@ type Coordinates struct {
 
 // Address table
 type Address struct {
-	ID             uint      `json:"id" gorm:"primaryKey"`
-	CustomerID     uint      `json:"customer_id" gorm:"not null"`
+	ID         uint `json:"id" gorm:"primaryKey"`
+	CustomerID uint `json:"customer_id" gorm:"not null"`
+	// Set when the address belongs to an organization; then every member shares it
+	OrganizationID *uint     `json:"organization_id" gorm:"index"`
 	Street         string    `json:"street" gorm:"size:255;not null"`
 	ExteriorNumber string    `json:"exterior_number" gorm:"size:10"`
 	InteriorNumber string    `json:"interior_number" gorm:"size:10"`
@@ -26,4 +28,6 @@ type Address struct {
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 	IsActive       bool      `json:"is_active" gorm:"default:true;not null"`
+	// Optimistic lock, bumped on every update and exposed as ETag
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// APIKeyPrefix starts every API key, so they are told apart from JWTs and easy to spot in leaks
+const APIKeyPrefix = "lk_"
+
+// API keys table: organization credentials for server-to-server integrations. The key is shown
+// once and stored as its SHA-256; Prefix is its first characters, kept to recognize it in lists.
+type APIKey struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID uint   `json:"organization_id" gorm:"not null;index"`
+	Name           string `json:"name" gorm:"size:100;not null"`
+	Prefix         string `json:"prefix" gorm:"size:16;not null"`
+	KeyHash        string `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	// Subset of the client permissions the key grants
+	Permissions []Permission `json:"permissions" gorm:"serializer:json;type:text;not null"`
+	// Requests per minute, 0 uses the default limit
+	RateLimit uint `json:"rate_limit" gorm:"not null;default:0"`
+	// Owner who created or last rotated the key; requests act on their behalf
+	CreatedBy uint `json:"created_by" gorm:"not null"`
+	// Key this one replaced on rotation
+	RotatedFromID *uint      `json:"rotated_from_id"`
+	LastUsedAt    *time.Time `json:"last_used_at"`
+	// Set on rotation with a grace period: the old key keeps working until then
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Active reports whether the key can authenticate at now
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyPermissions are the permissions a key may be granted: those of a client
+func APIKeyPermissions() []Permission {
+	return RoleClient.Permissions()
+}
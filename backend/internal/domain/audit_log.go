@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Audit log table: security relevant events
+type AuditLog struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	Action string `json:"action" gorm:"size:50;not null;index"`
+	// User who performed the action, nil for anonymous or system events
+	ActorID *uint `json:"actor_id" gorm:"index"`
+	// What the action was about, e.g. "email:ana@example.com" or "user:12"
+	Subject   string    `json:"subject" gorm:"size:255"`
+	IP        string    `json:"ip" gorm:"size:64"`
+	Details   string    `json:"details" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+const (
+	AuditLoginLockout   = "login.lockout"
+	AuditAdminBootstrap = "admin.bootstrap"
+	// An administrator created an account, changed its role or deleted it
+	AuditUserCreate     = "user.create"
+	AuditUserRoleChange = "user.role_change"
+	AuditUserDelete     = "user.delete"
+	// Data protection (ARCO) requests: access through the export, cancellation through erasure
+	AuditPrivacyExport         = "privacy.export"
+	AuditPrivacyErasureRequest = "privacy.erasure_request"
+	AuditPrivacyErasureApprove = "privacy.erasure_approve"
+	AuditPrivacyErasureReject  = "privacy.erasure_reject"
+)
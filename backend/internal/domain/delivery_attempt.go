@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type DeliveryFailureReason string
+
+const (
+	FailureRecipientAbsent DeliveryFailureReason = "absent"
+	FailureWrongAddress    DeliveryFailureReason = "wrong_address"
+	FailureRefused         DeliveryFailureReason = "refused"
+)
+
+// Delivery attempts table
+type DeliveryAttempt struct {
+	ID            uint                  `json:"id" gorm:"primaryKey"`
+	OrderID       uint                  `json:"order_id" gorm:"not null;index"`
+	AttemptNumber uint                  `json:"attempt_number" gorm:"not null"`
+	Reason        DeliveryFailureReason `json:"reason" gorm:"type:delivery_failure_reason_enum;not null"`
+	Notes         string                `json:"notes" gorm:"type:text"`
+	AttemptedAt   time.Time             `json:"attempted_at"`
+	RecordedBy    uint                  `json:"recorded_by" gorm:"not null"`
+}
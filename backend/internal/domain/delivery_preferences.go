@@ -0,0 +1,19 @@
+package domain
+
+type LeaveWith string
+
+const (
+	LeaveWithNobody    LeaveWith = ""
+	LeaveWithNeighbor  LeaveWith = "neighbor"
+	LeaveWithConcierge LeaveWith = "concierge"
+)
+
+// DeliveryPreferences are the recipient's structured delivery instructions, stored on the order
+type DeliveryPreferences struct {
+	WindowStart        string    `json:"window_start" gorm:"size:5"`
+	WindowEnd          string    `json:"window_end" gorm:"size:5"`
+	LeaveWith          LeaveWith `json:"leave_with" gorm:"size:20"`
+	AccessInstructions string    `json:"access_instructions" gorm:"type:text"`
+	RecipientName      string    `json:"recipient_name" gorm:"size:255"`
+	RecipientPhone     string    `json:"recipient_phone" gorm:"size:20"`
+}
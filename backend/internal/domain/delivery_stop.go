@@ -0,0 +1,15 @@
+package domain
+
+// DeliveryStop is a projection of an order in route as the driver needs it at the door
+type DeliveryStop struct {
+	OrderID                uint                `json:"order_id"`
+	OrderNumber            string              `json:"order_number"`
+	DestinationFullAddress string              `json:"destination_full_address"`
+	RecipientName          string              `json:"recipient_name"`
+	RecipientPhone         string              `json:"recipient_phone"`
+	Preferences            DeliveryPreferences `json:"preferences"`
+	Observations           string              `json:"observations"`
+	Quantity               uint                `json:"quantity"`
+	SizeCode               PackageSize         `json:"size_code"`
+	ScheduledDeliveryDate  *string             `json:"scheduled_delivery_date"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Idempotency keys table: the stored response of a request, replayed on retries with the same key.
+// StatusCode is 0 while the first request is still being processed.
+type IdempotencyKey struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_user_endpoint_key"`
+	Endpoint     string    `json:"endpoint" gorm:"size:100;not null;uniqueIndex:idx_idempotency_user_endpoint_key"`
+	Key          string    `json:"key" gorm:"size:255;not null;uniqueIndex:idx_idempotency_user_endpoint_key"`
+	RequestHash  string    `json:"request_hash" gorm:"size:64;not null"`
+	StatusCode   int       `json:"status_code" gorm:"not null;default:0"`
+	ResponseBody []byte    `json:"-" gorm:"type:bytea"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"not null;index"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Login throttles table: failed login counters per account ("email:<email>") and per client ("ip:<addr>")
+type LoginThrottle struct {
+	Key           string    `json:"key" gorm:"primaryKey;size:320"`
+	Failures      uint      `json:"failures" gorm:"not null;default:0"`
+	LastFailureAt time.Time `json:"last_failure_at"`
+	// Lockouts in a row, the next lockout lasts twice as long
+	LockCount   uint       `json:"lock_count" gorm:"not null;default:0"`
+	LockedUntil *time.Time `json:"locked_until"`
+}
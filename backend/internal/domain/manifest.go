@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+type ManifestStatus string
+
+const (
+	ManifestDraft  ManifestStatus = "draft"
+	ManifestIssued ManifestStatus = "issued"
+)
+
+// Manifests table: orders leaving a station, or loaded into a route, on a date.
+// Items are a snapshot and are frozen once the manifest is issued.
+type Manifest struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	ManifestNumber string         `json:"manifest_number" gorm:"size:50;uniqueIndex;not null"`
+	StationID      uint           `json:"station_id" gorm:"not null;index"`
+	StationCode    string         `json:"station_code" gorm:"size:10;not null"`
+	RouteCode      string         `json:"route_code" gorm:"size:50"`
+	ManifestDate   time.Time      `json:"manifest_date" gorm:"type:date;not null;index"`
+	DriverName     string         `json:"driver_name" gorm:"size:255"`
+	VehiclePlate   string         `json:"vehicle_plate" gorm:"size:20"`
+	Status         ManifestStatus `json:"status" gorm:"type:manifest_status_enum;default:draft;not null"`
+	TotalPieces    uint           `json:"total_pieces" gorm:"not null;default:0"`
+	TotalWeightKg  float64        `json:"total_weight_kg" gorm:"type:decimal(10,2);not null;default:0"`
+	CreatedBy      uint           `json:"created_by" gorm:"not null"`
+	IssuedBy       *uint          `json:"issued_by"`
+	IssuedAt       *time.Time     `json:"issued_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Items          []ManifestItem `json:"items" gorm:"foreignKey:ManifestID"`
+}
+
+// Manifest items table
+type ManifestItem struct {
+	ID                     uint        `json:"id" gorm:"primaryKey"`
+	ManifestID             uint        `json:"manifest_id" gorm:"not null;index"`
+	OrderID                uint        `json:"order_id" gorm:"not null;index"`
+	OrderNumber            string      `json:"order_number" gorm:"size:50;not null"`
+	Pieces                 uint        `json:"pieces" gorm:"not null"`
+	WeightKg               float64     `json:"weight_kg" gorm:"type:decimal(5,2)"`
+	SizeCode               PackageSize `json:"size_code" gorm:"type:package_size_enum"`
+	RecipientName          string      `json:"recipient_name" gorm:"size:255"`
+	DestinationFullAddress string      `json:"destination_full_address" gorm:"type:text"`
+	Zone                   string      `json:"zone" gorm:"size:10"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// MFA recovery codes table: single-use codes that replace a TOTP code, stored as their SHA-256
+type MFARecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
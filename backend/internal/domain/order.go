@@ -11,8 +11,18 @@ const (
 	OrderInRoute   OrderStatus = "in_route"
 	OrderDelivered OrderStatus = "delivered"
 	OrderCancelled OrderStatus = "cancelled"
+	// Failed delivery flow
+	OrderDeliveryFailed OrderStatus = "delivery_failed"
+	OrderReturnToSender OrderStatus = "return_to_sender"
+	// Terminal status of an order whose return has been delivered back to the sender
+	OrderReturned OrderStatus = "returned"
 )
 
+// Terminal reports whether the status closes the order, so it can't move to another one
+func (s OrderStatus) Terminal() bool {
+	return s == OrderDelivered || s == OrderCancelled || s == OrderReturned
+}
+
 type PackageSize string
 
 const (
@@ -33,10 +43,23 @@ type Order struct {
 	ActualWeightKg       float64     `json:"actual_weight_kg" gorm:"type:decimal(5,2)"`
 	Status               OrderStatus `json:"status" gorm:"type:order_status_enum;default:created;not null"`
 	CustomerID           uint        `json:"customer_id" gorm:"not null"`
-	CreatedBy            uint        `json:"created_by" gorm:"not null"`
-	UpdatedBy            *uint       `json:"updated_by"`
-	CreatedAt            time.Time   `json:"created_at"`
-	UpdatedAt            time.Time   `json:"updated_at"`
-	Observations         string      `json:"observations" gorm:"type:text"`
-	InternalNotes        string      `json:"internal_notes" gorm:"type:text"`
+	// Organization that owns the order, shared by its members
+	OrganizationID *uint     `json:"organization_id" gorm:"index"`
+	CreatedBy      uint      `json:"created_by" gorm:"not null"`
+	UpdatedBy      *uint     `json:"updated_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Observations   string    `json:"observations" gorm:"type:text"`
+	InternalNotes  string    `json:"internal_notes" gorm:"type:text"`
+	// Delivery attempts
+	DeliveryAttempts      uint       `json:"delivery_attempts" gorm:"default:0;not null"`
+	ScheduledDeliveryDate *time.Time `json:"scheduled_delivery_date" gorm:"type:date"`
+	// Reverse logistics: set on return orders, points to the original order
+	ReturnOfOrderID *uint `json:"return_of_order_id" gorm:"uniqueIndex"`
+	// Pickup the order is grouped in, if any
+	PickupID *uint `json:"pickup_id" gorm:"index"`
+	// Recipient delivery preferences
+	DeliveryPreferences DeliveryPreferences `json:"delivery_preferences" gorm:"embedded;embeddedPrefix:pref_"`
+	// Optimistic lock, bumped on every update and exposed as ETag
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
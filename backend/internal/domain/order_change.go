@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Order change log table: one row per field modified by an order edit
+type OrderChange struct {
+	ID      uint `json:"id" gorm:"primaryKey"`
+	OrderID uint `json:"order_id" gorm:"not null;index"`
+	// Order version produced by the edit; groups the fields changed together
+	Version   uint      `json:"version" gorm:"not null"`
+	Field     string    `json:"field" gorm:"size:50;not null"`
+	OldValue  string    `json:"old_value" gorm:"type:text"`
+	NewValue  string    `json:"new_value" gorm:"type:text"`
+	ChangedBy uint      `json:"changed_by" gorm:"not null"`
+	ChangedAt time.Time `json:"changed_at"`
+}
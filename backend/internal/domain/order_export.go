@@ -0,0 +1,11 @@
+package domain
+
+// OrderExportRow is an OrderListItem plus the detail columns offered by order exports
+type OrderExportRow struct {
+	OrderListItem
+	Observations          string              `json:"observations"`
+	DeliveryAttempts      uint                `json:"delivery_attempts"`
+	ScheduledDeliveryDate string              `json:"scheduled_delivery_date"`
+	UpdatedAt             string              `json:"updated_at"`
+	DeliveryPreferences   DeliveryPreferences `json:"delivery_preferences"`
+}
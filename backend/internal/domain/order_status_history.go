@@ -11,4 +11,5 @@ type OrderStatusHistory struct {
 	ChangedAt      time.Time   `json:"changed_at"`
 	ChangedBy      uint        `json:"changed_by" gorm:"not null"`
 	Notes          string      `json:"notes" gorm:"type:text"`
+	ManifestID     *uint       `json:"manifest_id" gorm:"index"`
 }
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+type OrgRole string
+
+const (
+	// Manages members and invitations, and ships like a shipper
+	OrgOwner OrgRole = "owner"
+	// Creates and changes the organization's orders, addresses and pickups
+	OrgShipper OrgRole = "shipper"
+	// Only reads the organization's orders and addresses
+	OrgViewer OrgRole = "viewer"
+)
+
+func (r OrgRole) Valid() bool {
+	return r == OrgOwner || r == OrgShipper || r == OrgViewer
+}
+
+// CanShip reports whether the member may create and change the organization's data
+func (r OrgRole) CanShip() bool {
+	return r == OrgOwner || r == OrgShipper
+}
+
+// Organization table: a business account whose members share addresses, orders and pickups
+type Organization struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"size:255;not null"`
+	CreatedBy uint      `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Members   []User    `json:"members,omitempty" gorm:"foreignKey:OrganizationID"`
+}
+
+// Organization invitations table: the token is mailed to the invitee and stored as its SHA-256
+type OrganizationInvitation struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	OrganizationID uint       `json:"organization_id" gorm:"not null;index"`
+	Email          string     `json:"email" gorm:"size:255;not null;index"`
+	Role           OrgRole    `json:"role" gorm:"size:20;not null"`
+	TokenHash      string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	InvitedBy      uint       `json:"invited_by" gorm:"not null"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Scope is whose customer data a caller works with: their own and, for organization members,
+// the organization's. Rows created inside an organization belong to it rather than to the member
+// who created them, so a member who leaves stops seeing them.
+type Scope struct {
+	UserID         uint
+	OrganizationID *uint
+	OrgRole        OrgRole
+	// Set for API keys: only the organization's rows, never the personal ones of the user behind the key
+	OrganizationOnly bool
+}
+
+// Sees reports whether a row owned by customerID, or by organizationID when set, is in the scope
+func (s Scope) Sees(customerID uint, organizationID *uint) bool {
+	if organizationID == nil {
+		return !s.OrganizationOnly && customerID == s.UserID
+	}
+	return s.OrganizationID != nil && *organizationID == *s.OrganizationID
+}
+
+// Writable narrows the scope to the rows the caller may change: viewers only read the organization's
+func (s Scope) Writable() Scope {
+	if !s.OrgRole.CanShip() {
+		s.OrganizationID = nil
+	}
+	return s
+}
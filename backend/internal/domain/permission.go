@@ -0,0 +1,92 @@
+package domain
+
+// Permission is a named action a role may perform. Handlers check permissions, never role names,
+// so a new role only needs an entry in rolePermissions.
+type Permission string
+
+const (
+	// Create, import, edit and export the caller's own orders
+	PermOrdersCreate Permission = "orders.create"
+	// See any customer's orders, their history, labels and change logs
+	PermOrdersReadAll Permission = "orders.read.all"
+	// Edit, return or reschedule the delivery of any customer's order
+	PermOrdersManage       Permission = "orders.manage"
+	PermOrdersStatusUpdate Permission = "orders.status.update"
+	PermOrdersExportAll    Permission = "orders.export.all"
+
+	PermDeliveriesRecord Permission = "deliveries.record"
+	PermDeliveriesRoute  Permission = "deliveries.route"
+
+	// Manage the caller's own addresses
+	PermAddressesCreate    Permission = "addresses.create"
+	PermAddressesManageAll Permission = "addresses.manage.all"
+
+	PermPickupsManageAll  Permission = "pickups.manage.all"
+	PermPickupSlotsManage Permission = "pickup_slots.manage"
+
+	PermPackageTypesManage Permission = "package_types.manage"
+	PermStationsManage     Permission = "stations.manage"
+	PermScansRecord        Permission = "scans.record"
+	PermScansRead          Permission = "scans.read"
+	PermManifestsManage    Permission = "manifests.manage"
+
+	PermUsersReadAll Permission = "users.read.all"
+	PermUsersDelete  Permission = "users.delete"
+	// Change any user's profile, role and active flag, and end their sessions
+	PermUsersManage Permission = "users.manage"
+
+	// Review personal data erasure requests
+	PermPrivacyManage Permission = "privacy.manage"
+)
+
+// AllPermissions lists every permission; admins are granted all of them
+var AllPermissions = []Permission{
+	PermOrdersCreate, PermOrdersReadAll, PermOrdersManage, PermOrdersStatusUpdate, PermOrdersExportAll,
+	PermDeliveriesRecord, PermDeliveriesRoute,
+	PermAddressesCreate, PermAddressesManageAll,
+	PermPickupsManageAll, PermPickupSlotsManage,
+	PermPackageTypesManage, PermStationsManage, PermScansRecord, PermScansRead, PermManifestsManage,
+	PermUsersReadAll, PermUsersDelete, PermUsersManage,
+	PermPrivacyManage,
+}
+
+var rolePermissions = map[Role][]Permission{
+	RoleClient: {PermOrdersCreate, PermAddressesCreate},
+	RoleAdmin:  AllPermissions,
+	// Plans routes, pickups and manifests and moves orders through their statuses
+	RoleDispatcher: {
+		PermOrdersReadAll, PermOrdersStatusUpdate, PermDeliveriesRecord, PermDeliveriesRoute,
+		PermPickupsManageAll, PermPickupSlotsManage, PermScansRead, PermManifestsManage,
+	},
+	// Receives and dispatches packages at a station
+	RoleStationClerk: {PermOrdersReadAll, PermScansRecord, PermScansRead, PermManifestsManage},
+	// Acts on behalf of customers
+	RoleSupport: {
+		PermOrdersReadAll, PermOrdersManage, PermAddressesManageAll, PermPickupsManageAll, PermUsersReadAll,
+	},
+	RoleFinance: {PermOrdersReadAll, PermOrdersExportAll, PermUsersReadAll},
+}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants p
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Permissions returns the permissions granted to the role
+func (r Role) Permissions() []Permission {
+	return append([]Permission(nil), rolePermissions[r]...)
+}
+
+// Roles lists every known role
+var Roles = []Role{RoleClient, RoleAdmin, RoleDispatcher, RoleStationClerk, RoleSupport, RoleFinance}
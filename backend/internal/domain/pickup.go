@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type PickupStatus string
+
+const (
+	PickupScheduled PickupStatus = "scheduled"
+	PickupCancelled PickupStatus = "cancelled"
+)
+
+// Pickups table: a courier visit to an origin address within a time window
+type Pickup struct {
+	ID              uint         `json:"id" gorm:"primaryKey"`
+	CustomerID      uint         `json:"customer_id" gorm:"not null;index"`
+	OrganizationID  *uint        `json:"organization_id" gorm:"index"`
+	OriginAddressID uint         `json:"origin_address_id" gorm:"not null"`
+	Zone            string       `json:"zone" gorm:"size:10;not null;index:idx_pickups_slot"`
+	PickupDate      time.Time    `json:"pickup_date" gorm:"type:date;not null;index:idx_pickups_slot"`
+	WindowStart     string       `json:"window_start" gorm:"size:5;not null;index:idx_pickups_slot"`
+	WindowEnd       string       `json:"window_end" gorm:"size:5;not null;index:idx_pickups_slot"`
+	Status          PickupStatus `json:"status" gorm:"type:pickup_status_enum;default:scheduled;not null"`
+	Notes           string       `json:"notes" gorm:"type:text"`
+	CreatedBy       uint         `json:"created_by" gorm:"not null"`
+	UpdatedBy       *uint        `json:"updated_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	OrderIDs        []uint       `json:"order_ids" gorm:"-"`
+}
+
+// Pickup slots table: capacity per zone and time window. An empty zone applies to every zone
+// without a specific slot.
+type PickupSlot struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Zone        string    `json:"zone" gorm:"size:10;not null;uniqueIndex:idx_pickup_slot_window"`
+	WindowStart string    `json:"window_start" gorm:"size:5;not null;uniqueIndex:idx_pickup_slot_window"`
+	WindowEnd   string    `json:"window_end" gorm:"size:5;not null;uniqueIndex:idx_pickup_slot_window"`
+	Capacity    uint      `json:"capacity" gorm:"not null"`
+	IsActive    bool      `json:"is_active" gorm:"default:true;not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ZoneFromPostalCode derives the operational zone of an address from its postal code prefix
+func ZoneFromPostalCode(postalCode string) string {
+	if len(postalCode) < 3 {
+		return postalCode
+	}
+	return postalCode[:3]
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID      uint
+	Role        Role
+	Permissions []Permission
+	// organization the user belongs to and their role in it, if any
+	OrganizationID *uint
+	OrgRole        OrgRole
+	// Set when the request authenticated with an organization API key instead of a user session
+	APIKeyID uint
+	// jti of the access token, its login session and expiry, used to revoke it
+	TokenID   string
+	SessionID string
+	ExpiresAt time.Time
+}
+
+// Can reports whether the principal's role grants p
+func (p *Principal) Can(perm Permission) bool {
+	for _, granted := range p.Permissions {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope returns the data the principal reaches as a customer
+func (p *Principal) Scope() Scope {
+	return Scope{UserID: p.UserID, OrganizationID: p.OrganizationID, OrgRole: p.OrgRole, OrganizationOnly: p.APIKeyID != 0}
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal stored in ctx by the authentication middleware
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
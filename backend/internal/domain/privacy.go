@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type ErasureStatus string
+
+const (
+	ErasurePending ErasureStatus = "pending"
+	// Approved and carried out: the user and their personal addresses were anonymized
+	ErasureCompleted ErasureStatus = "completed"
+	ErasureRejected  ErasureStatus = "rejected"
+)
+
+func (s ErasureStatus) Valid() bool {
+	return s == ErasurePending || s == ErasureCompleted || s == ErasureRejected
+}
+
+// Erasure requests table: a user's cancellation request (ARCO) and its review. Kept after the
+// erasure as the record that it was asked for and by whom it was approved.
+type ErasureRequest struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	UserID     uint          `json:"user_id" gorm:"not null;index"`
+	Reason     string        `json:"reason" gorm:"type:text"`
+	Status     ErasureStatus `json:"status" gorm:"size:20;not null;default:pending;index"`
+	ReviewedBy *uint         `json:"reviewed_by"`
+	ReviewedAt *time.Time    `json:"reviewed_at"`
+	ReviewNote string        `json:"review_note" gorm:"type:text"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// PersonalData is what the export hands a user about themself (access right)
+type PersonalData struct {
+	Profile       User                 `json:"profile"`
+	Addresses     []Address            `json:"addresses"`
+	Orders        []Order              `json:"orders"`
+	StatusHistory []OrderStatusHistory `json:"status_history"`
+	ExportedAt    time.Time            `json:"exported_at"`
+}
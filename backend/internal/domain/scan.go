@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type ScanType string
+
+const (
+	ScanInbound  ScanType = "inbound"
+	ScanOutbound ScanType = "outbound"
+	ScanLoad     ScanType = "load"
+	ScanUnload   ScanType = "unload"
+)
+
+type ScanResult string
+
+const (
+	ScanApplied  ScanResult = "applied"
+	ScanRejected ScanResult = "rejected"
+)
+
+// Scans table: every barcode read at a station, applied or rejected
+type Scan struct {
+	ID             uint         `json:"id" gorm:"primaryKey"`
+	OrderID        *uint        `json:"order_id" gorm:"index"`
+	Barcode        string       `json:"barcode" gorm:"size:100;not null"`
+	ScanType       ScanType     `json:"scan_type" gorm:"type:scan_type_enum;not null"`
+	StationID      uint         `json:"station_id" gorm:"not null;index"`
+	DeviceID       string       `json:"device_id" gorm:"size:100;not null"`
+	RouteCode      string       `json:"route_code" gorm:"size:50;index"`
+	Result         ScanResult   `json:"result" gorm:"type:scan_result_enum;not null"`
+	PreviousStatus *OrderStatus `json:"previous_status" gorm:"type:order_status_enum"`
+	NewStatus      *OrderStatus `json:"new_status" gorm:"type:order_status_enum"`
+	Reason         string       `json:"reason" gorm:"type:text"`
+	ScannedBy      uint         `json:"scanned_by" gorm:"not null"`
+	ScannedAt      time.Time    `json:"scanned_at" gorm:"index"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Refresh tokens table. Tokens are opaque and stored as their SHA-256; every refresh consumes the
+// token and issues a new one in the same family (the login session)
+type RefreshToken struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	FamilyID     string     `json:"family_id" gorm:"size:64;not null;index"`
+	TokenHash    string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt       *time.Time `json:"used_at"`
+	ReplacedByID *uint      `json:"replaced_by_id"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Revoked access tokens table: JWT ids rejected until the token would have expired anyway
+type RevokedAccessToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey;size:64"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
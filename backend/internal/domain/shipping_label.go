@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// ShippingLabel holds what is printed on the 4x6 label of an order
+type ShippingLabel struct {
+	OrderNumber      string
+	CreatedAt        time.Time
+	SenderName       string
+	SenderAddress    []string
+	RecipientName    string
+	RecipientPhone   string
+	RecipientAddress []string
+	SizeCode         PackageSize
+	WeightKg         float64
+	Quantity         uint
+	Zone             string
+	TrackingURL      string
+}
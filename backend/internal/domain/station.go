@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Stations table: warehouses and cross-docks where parcels are scanned
+type Station struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Code      string    `json:"code" gorm:"size:10;uniqueIndex;not null"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	City      string    `json:"city" gorm:"size:100"`
+	IsActive  bool      `json:"is_active" gorm:"default:true;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,12 +1,22 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Role string
 
 const (
 	RoleClient Role = "client"
 	RoleAdmin  Role = "admin"
+	// Operator roles, see rolePermissions
+	RoleDispatcher   Role = "dispatcher"
+	RoleStationClerk Role = "station_clerk"
+	RoleSupport      Role = "support"
+	RoleFinance      Role = "finance"
 )
 
 // User table
@@ -22,4 +32,43 @@ type User struct {
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 	UpdatedBy        *uint     `json:"updated_by"`
+	// Set once the user confirms the email through the verification link
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// TOTP second factor; the secret is stored on enrollment and MFAEnabled set once a code confirms it
+	MFAEnabled bool   `json:"mfa_enabled" gorm:"default:false;not null"`
+	MFASecret  string `json:"-" gorm:"size:64"`
+	// Last accepted TOTP time step, so a code can't be replayed
+	MFALastStep int64 `json:"-" gorm:"default:0;not null"`
+	// Access tokens issued before this instant are rejected, set on force logout and deactivation
+	SessionsRevokedAt *time.Time `json:"-"`
+	// Organization the user belongs to, at most one, and the role inside it
+	OrganizationID *uint   `json:"organization_id" gorm:"index"`
+	OrgRole        OrgRole `json:"org_role,omitempty" gorm:"size:20;not null;default:''"`
+	// Soft delete: the row stays, anonymized, so orders and addresses keep pointing to it
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// DeletedUserName replaces the name of a deleted user
+const DeletedUserName = "Usuario eliminado"
+
+// DeletedUserEmail is the placeholder email of a deleted user; unique per user, so the original
+// address can register again
+func DeletedUserEmail(id uint) string {
+	return fmt.Sprintf("deleted-%d@invalid", id)
+}
+
+// Permissions returns what the user may do: those of the role, minus creating orders and
+// addresses for organization viewers, who only read the organization's data
+func (u *User) Permissions() []Permission {
+	perms := u.Role.Permissions()
+	if u.OrganizationID == nil || u.OrgRole.CanShip() {
+		return perms
+	}
+	out := perms[:0]
+	for _, p := range perms {
+		if p != PermOrdersCreate && p != PermAddressesCreate {
+			out = append(out, p)
+		}
+	}
+	return out
 }
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type UserTokenPurpose string
+
+const (
+	TokenPasswordReset     UserTokenPurpose = "password_reset"
+	TokenEmailVerification UserTokenPurpose = "email_verification"
+)
+
+// User tokens table: single-use, time-limited tokens sent by email, stored as their SHA-256
+type UserToken struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    uint             `json:"user_id" gorm:"not null;index"`
+	Purpose   UserTokenPurpose `json:"purpose" gorm:"type:user_token_purpose_enum;not null"`
+	TokenHash string           `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time        `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time       `json:"used_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
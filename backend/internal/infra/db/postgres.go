@@ -31,6 +31,12 @@ func Connect() (*Database, error) {
 		return nil, err
 	}
 
+	for _, v := range []string{"dispatcher", "station_clerk", "support", "finance"} {
+		if err := database.Exec("ALTER TYPE user_role_enum ADD VALUE IF NOT EXISTS '" + v + "'").Error; err != nil {
+			return nil, err
+		}
+	}
+
 	if err := database.Exec("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'package_size_enum') THEN CREATE TYPE package_size_enum AS ENUM ('S','M','L','XL'); END IF; END $$;").Error; err != nil {
 		return nil, err
 	}
@@ -39,6 +45,37 @@ func Connect() (*Database, error) {
 		return nil, err
 	}
 
+	// Values added after the enum was first created
+	for _, v := range []string{"delivery_failed", "return_to_sender", "returned"} {
+		if err := database.Exec("ALTER TYPE order_status_enum ADD VALUE IF NOT EXISTS '" + v + "'").Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := database.Exec("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'delivery_failure_reason_enum') THEN CREATE TYPE delivery_failure_reason_enum AS ENUM ('absent','wrong_address','refused'); END IF; END $$;").Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.Exec("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'pickup_status_enum') THEN CREATE TYPE pickup_status_enum AS ENUM ('scheduled','cancelled'); END IF; END $$;").Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.Exec("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'scan_type_enum') THEN CREATE TYPE scan_type_enum AS ENUM ('inbound','outbound','load','unload'); END IF; END $$;").Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.Exec("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'scan_result_enum') THEN CREATE TYPE scan_result_enum AS ENUM ('applied','rejected'); END IF; END $$;").Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.Exec("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'manifest_status_enum') THEN CREATE TYPE manifest_status_enum AS ENUM ('draft','issued'); END IF; END $$;").Error; err != nil {
+		return nil, err
+	}
+
+	if err := database.Exec("DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'user_token_purpose_enum') THEN CREATE TYPE user_token_purpose_enum AS ENUM ('password_reset','email_verification'); END IF; END $$;").Error; err != nil {
+		return nil, err
+	}
+
 	log.Println("connected to postgres")
 
 	return &Database{database}, nil
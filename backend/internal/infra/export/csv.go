@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"logistics-app/backend/internal/domain"
+)
+
+// rows buffered before flushing to the output
+const csvFlushRows = 100
+
+type csvWriter struct {
+	w       *csv.Writer
+	detail  bool
+	started bool
+	pending int
+}
+
+func newCSVWriter(w io.Writer, detail bool) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), detail: detail}
+}
+
+func (c *csvWriter) header() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.w.Write(columns(c.detail))
+}
+
+func (c *csvWriter) Write(row domain.OrderExportRow) error {
+	if err := c.header(); err != nil {
+		return err
+	}
+
+	vals := values(row, c.detail)
+	record := make([]string, len(vals))
+	for i, v := range vals {
+		record[i] = format(v)
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+
+	if c.pending++; c.pending >= csvFlushRows {
+		c.pending = 0
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.header(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,91 @@
+// Package export writes order lists as CSV, XLSX or a PDF summary, one row at a time, and a user's
+// personal data as a ZIP of JSON files.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"logistics-app/backend/internal/domain"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatPDF  Format = "pdf"
+)
+
+var ErrUnsupportedFormat = errors.New("formato de exportación no soportado (csv, xlsx, pdf)")
+
+// OrderWriter receives the exported orders in order; Close completes the document
+type OrderWriter interface {
+	Write(row domain.OrderExportRow) error
+	Close() error
+}
+
+// NewOrderWriter returns a writer for the format and the document content type.
+// detail adds the detail columns to CSV and XLSX; the PDF is always a summary.
+func NewOrderWriter(format Format, w io.Writer, detail bool) (OrderWriter, string, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, detail), "text/csv; charset=utf-8", nil
+	case FormatXLSX:
+		ow, err := newXLSXWriter(w, detail)
+		return ow, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", err
+	case FormatPDF:
+		return newPDFWriter(w), "application/pdf", nil
+	}
+
+	return nil, "", ErrUnsupportedFormat
+}
+
+var (
+	listColumns   = []string{"id", "order_number", "created_at", "full_name", "origin_full_address", "destination_full_address", "quantity", "actual_weight_kg", "size_code", "status"}
+	detailColumns = []string{"observations", "delivery_attempts", "scheduled_delivery_date", "updated_at", "window_start", "window_end", "leave_with", "access_instructions", "recipient_name", "recipient_phone"}
+)
+
+func columns(detail bool) []string {
+	if !detail {
+		return listColumns
+	}
+	return append(append([]string{}, listColumns...), detailColumns...)
+}
+
+// values keeps numbers typed so spreadsheets can sum them and neutralizes text that a spreadsheet
+// would evaluate as a formula
+func values(r domain.OrderExportRow, detail bool) []interface{} {
+	v := []interface{}{
+		r.ID, r.OrderNumber, r.CreatedAt, r.FullName, r.OriginFullAddress, r.DestinationFullAddress,
+		r.Quantity, r.ActualWeightKg, string(r.SizeCode), string(r.Status),
+	}
+	if detail {
+		p := r.DeliveryPreferences
+		v = append(v, r.Observations, r.DeliveryAttempts, r.ScheduledDeliveryDate, r.UpdatedAt,
+			p.WindowStart, p.WindowEnd, string(p.LeaveWith), p.AccessInstructions, p.RecipientName, p.RecipientPhone)
+	}
+	for i, val := range v {
+		if s, ok := val.(string); ok {
+			v[i] = escapeFormula(s)
+		}
+	}
+	return v
+}
+
+// escapeFormula prefixes a quote to text starting with a character that makes Excel, LibreOffice
+// or Google Sheets read the cell as a formula (CSV injection)
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func format(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return fmt.Sprintf("%.2f", f)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,149 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"logistics-app/backend/internal/domain"
+	"sort"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// orders listed after the summary; the totals always cover every order
+const pdfMaxRows = 500
+
+type statusTotals struct {
+	orders int
+	pieces uint
+	weight float64
+}
+
+// pdfWriter aggregates the rows and renders the summary on Close
+type pdfWriter struct {
+	out    io.Writer
+	totals map[domain.OrderStatus]*statusTotals
+	all    statusTotals
+	rows   []domain.OrderListItem
+}
+
+func newPDFWriter(w io.Writer) *pdfWriter {
+	return &pdfWriter{out: w, totals: map[domain.OrderStatus]*statusTotals{}}
+}
+
+func (p *pdfWriter) Write(row domain.OrderExportRow) error {
+	t, ok := p.totals[row.Status]
+	if !ok {
+		t = &statusTotals{}
+		p.totals[row.Status] = t
+	}
+	for _, s := range []*statusTotals{t, &p.all} {
+		s.orders++
+		s.pieces += row.Quantity
+		s.weight += row.ActualWeightKg
+	}
+
+	if len(p.rows) < pdfMaxRows {
+		p.rows = append(p.rows, row.OrderListItem)
+	}
+	return nil
+}
+
+func (p *pdfWriter) Close() error {
+	pdf := gofpdf.New("L", "mm", "Letter", "")
+	pdf.SetTitle("Resumen de órdenes", true)
+	pdf.SetMargins(12, 12, 12)
+	pdf.SetAutoPageBreak(true, 15)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, tr(fmt.Sprintf("página %d/{nb}", pdf.PageNo())), "", 0, "R", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 9, tr("Resumen de órdenes"), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr("Generado: "+time.Now().Format("2006-01-02 15:04")), "", 1, "L", false, 0, "")
+	pdf.Ln(3)
+
+	cell := func(w float64, s, align string, fill bool) {
+		pdf.CellFormat(w, 6, tr(s), "1", 0, align, fill, 0, "")
+	}
+
+	// totals by status
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	cell(50, "Estado", "L", true)
+	cell(30, "Órdenes", "R", true)
+	cell(30, "Piezas", "R", true)
+	cell(30, "Peso kg", "R", true)
+	pdf.Ln(-1)
+	statuses := make([]string, 0, len(p.totals))
+	for s := range p.totals {
+		statuses = append(statuses, string(s))
+	}
+	sort.Strings(statuses)
+	pdf.SetFont("Helvetica", "", 9)
+	for _, s := range statuses {
+		t := p.totals[domain.OrderStatus(s)]
+		cell(50, s, "L", false)
+		cell(30, fmt.Sprint(t.orders), "R", false)
+		cell(30, fmt.Sprint(t.pieces), "R", false)
+		cell(30, fmt.Sprintf("%.2f", t.weight), "R", false)
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	cell(50, "TOTAL", "L", false)
+	cell(30, fmt.Sprint(p.all.orders), "R", false)
+	cell(30, fmt.Sprint(p.all.pieces), "R", false)
+	cell(30, fmt.Sprintf("%.2f", p.all.weight), "R", false)
+	pdf.Ln(10)
+
+	// order listing
+	widths := []float64{42, 22, 50, 96, 14, 18, 30}
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 8)
+		for i, h := range []string{"Orden", "Fecha", "Cliente", "Destino", "Piezas", "Peso kg", "Estado"} {
+			cell(widths[i], h, "C", true)
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 8)
+	}
+	header()
+	for _, r := range p.rows {
+		if pdf.GetY() > 190 {
+			pdf.AddPage()
+			header()
+		}
+		vals := []string{r.OrderNumber, r.CreatedAt, r.FullName, r.DestinationFullAddress, fmt.Sprint(r.Quantity), fmt.Sprintf("%.2f", r.ActualWeightKg), string(r.Status)}
+		for i, v := range vals {
+			align := "L"
+			if i >= 4 && i <= 5 {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], 6, fit(pdf, tr(v), widths[i]-2), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	if p.all.orders > len(p.rows) {
+		pdf.Ln(2)
+		pdf.CellFormat(0, 6, tr(fmt.Sprintf("Se muestran las primeras %d de %d órdenes; exporte CSV o XLSX para la lista completa.", len(p.rows), p.all.orders)), "", 1, "L", false, 0, "")
+	}
+
+	return pdf.Output(p.out)
+}
+
+// fit truncates s to the given width with the current font.
+// s is already translated to the single-byte font encoding, so it is cut by bytes.
+func fit(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	for len(s) > 0 && pdf.GetStringWidth(s+"...") > width {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
@@ -0,0 +1,36 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"logistics-app/backend/internal/domain"
+)
+
+// WritePersonalData writes d as a ZIP archive with one indented JSON file per section
+func WritePersonalData(w io.Writer, d *domain.PersonalData) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		v    interface{}
+	}{
+		{"profile.json", d.Profile},
+		{"addresses.json", d.Addresses},
+		{"orders.json", d.Orders},
+		{"status_history.json", d.StatusHistory},
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: d.ExportedAt})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.v); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
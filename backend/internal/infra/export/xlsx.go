@@ -0,0 +1,61 @@
+package export
+
+import (
+	"io"
+	"logistics-app/backend/internal/domain"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const xlsxSheet = "Orders"
+
+// xlsxWriter uses the excelize stream writer, which spills to a temporary file on large exports
+type xlsxWriter struct {
+	out io.Writer
+	f   *excelize.File
+	sw  *excelize.StreamWriter
+	row int
+
+	detail bool
+}
+
+func newXLSXWriter(w io.Writer, detail bool) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return nil, err
+	}
+
+	sw, err := f.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	header := columns(detail)
+	cells := make([]interface{}, len(header))
+	for i, h := range header {
+		cells[i] = excelize.Cell{Value: h}
+	}
+	if err := sw.SetRow("A1", cells); err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{out: w, f: f, sw: sw, row: 1, detail: detail}, nil
+}
+
+func (x *xlsxWriter) Write(row domain.OrderExportRow) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, values(row, x.detail))
+}
+
+func (x *xlsxWriter) Close() error {
+	defer func() { _ = x.f.Close() }()
+
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.f.Write(x.out)
+}
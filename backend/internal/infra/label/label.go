@@ -0,0 +1,202 @@
+// Package label renders 4x6 inch shipping labels as PDF, ZPL or PNG.
+//
+// Every format is drawn from the same layout, expressed in printer dots at 203 dpi
+// (8 dots per mm), so the printed label looks the same whatever the output.
+package label
+
+import (
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+)
+
+type Format string
+
+const (
+	FormatPDF Format = "pdf"
+	FormatZPL Format = "zpl"
+	FormatPNG Format = "png"
+)
+
+// 4x6 inches at 203 dpi
+const (
+	widthDots   = 812
+	heightDots  = 1218
+	dotsPerMM   = 8.0
+	marginDots  = 24
+	contentDots = widthDots - 2*marginDots
+)
+
+var ErrUnsupportedFormat = errors.New("formato de etiqueta no soportado (pdf, zpl, png)")
+
+// element kinds drawn by every renderer
+type text struct {
+	x, y, h int
+	bold    bool
+	s       string
+}
+
+type hline struct{ x, y, w, thick int }
+
+type code128Bar struct {
+	x, y, h, module int
+	data            string
+	modules         []bool
+}
+
+type qrCode struct {
+	x, y, module int
+	data         string
+	modules      [][]bool
+}
+
+type layout struct {
+	texts    []text
+	lines    []hline
+	barcodes []code128Bar
+	qrs      []qrCode
+}
+
+// Render draws the label in the requested format and returns the bytes and their content type
+func Render(format Format, l domain.ShippingLabel) ([]byte, string, error) {
+	lay, err := build(l)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case FormatPDF:
+		b, err := renderPDF(lay, l.OrderNumber)
+		return b, "application/pdf", err
+	case FormatZPL:
+		return renderZPL(lay), "application/zpl", nil
+	case FormatPNG:
+		b, err := renderPNG(lay)
+		return b, "image/png", err
+	}
+
+	return nil, "", ErrUnsupportedFormat
+}
+
+// fit truncates s to the characters that fit in width dots at height h
+func fit(s string, h, width int) string {
+	max := width * 10 / (h * 6)
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	if max < 2 {
+		return ""
+	}
+	return string(r[:max-1]) + "…"
+}
+
+// bitmap returns the dark modules of an unscaled barcode
+func bitmap(bc barcode.Barcode) [][]bool {
+	b := bc.Bounds()
+	rows := make([][]bool, 0, b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := make([]bool, 0, b.Dx())
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, _, _, _ := bc.At(x, y).RGBA()
+			row = append(row, r < 0x8000)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func build(l domain.ShippingLabel) (*layout, error) {
+	lay := &layout{}
+	t := func(x, y, h int, bold bool, s string, width int) {
+		lay.texts = append(lay.texts, text{x: x, y: y, h: h, bold: bold, s: fit(s, h, width)})
+	}
+	line := func(y int) {
+		lay.lines = append(lay.lines, hline{x: marginDots, y: y, w: contentDots, thick: 4})
+	}
+
+	// Header: brand, date and destination zone
+	t(marginDots, 28, 40, true, "LOGISTICS APP", 540)
+	t(marginDots, 80, 26, false, "Fecha: "+l.CreatedAt.Format("02/01/2006"), 540)
+	t(600, 24, 24, true, "ZONA", 188)
+	t(600, 52, 72, true, l.Zone, 188)
+	line(140)
+
+	// Sender
+	t(marginDots, 156, 22, true, "REMITENTE", contentDots)
+	t(marginDots, 184, 28, false, l.SenderName, contentDots)
+	for i, s := range l.SenderAddress {
+		if i == 3 {
+			break
+		}
+		t(marginDots, 218+i*28, 24, false, s, contentDots)
+	}
+	line(310)
+
+	// Recipient
+	t(marginDots, 326, 24, true, "DESTINATARIO", contentDots)
+	t(marginDots, 358, 40, true, l.RecipientName, contentDots)
+	if l.RecipientPhone != "" {
+		t(marginDots, 404, 28, false, "Tel: "+l.RecipientPhone, contentDots)
+	}
+	for i, s := range l.RecipientAddress {
+		if i == 3 {
+			break
+		}
+		t(marginDots, 442+i*38, 32, false, s, contentDots)
+	}
+	line(564)
+
+	// Package
+	t(marginDots, 580, 22, true, "TAMAÑO", 240)
+	t(marginDots, 610, 64, true, string(l.SizeCode), 240)
+	t(290, 580, 22, true, "PESO", 240)
+	t(290, 616, 44, true, fmt.Sprintf("%.2f kg", l.WeightKg), 260)
+	t(560, 580, 22, true, "PIEZAS", 228)
+	t(560, 616, 44, true, fmt.Sprintf("%d", l.Quantity), 228)
+	line(700)
+
+	// Code128 of the order number
+	bc, err := code128.Encode(l.OrderNumber)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo generar el código de barras: %w", err)
+	}
+	mods := bitmap(bc)[0]
+	module := contentDots / len(mods)
+	if module > 4 {
+		module = 4
+	}
+	if module < 1 {
+		return nil, errors.New("el número de orden es demasiado largo para el código de barras")
+	}
+	lay.barcodes = append(lay.barcodes, code128Bar{
+		x:       (widthDots - len(mods)*module) / 2,
+		y:       724,
+		h:       170,
+		module:  module,
+		data:    l.OrderNumber,
+		modules: mods,
+	})
+	t((widthDots-len([]rune(l.OrderNumber))*19)/2, 910, 32, true, l.OrderNumber, contentDots)
+	line(966)
+
+	// QR linking to tracking
+	q, err := qr.Encode(l.TrackingURL, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo generar el código QR: %w", err)
+	}
+	grid := bitmap(q)
+	qm := 210 / len(grid)
+	if qm < 2 {
+		qm = 2
+	}
+	lay.qrs = append(lay.qrs, qrCode{x: marginDots, y: 986, module: qm, data: l.TrackingURL, modules: grid})
+	t(270, 1000, 28, true, "Rastrea tu envío", 518)
+	t(270, 1040, 20, false, l.TrackingURL, 518)
+
+	return lay, nil
+}
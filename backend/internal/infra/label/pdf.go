@@ -0,0 +1,61 @@
+package label
+
+import (
+	"bytes"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+func mm(dots int) float64 { return float64(dots) / dotsPerMM }
+
+func renderPDF(lay *layout, title string) ([]byte, error) {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: mm(widthDots), Ht: mm(heightDots)},
+	})
+	pdf.SetTitle(title, true)
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	pdf.SetFillColor(0, 0, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for _, l := range lay.lines {
+		pdf.Rect(mm(l.x), mm(l.y), mm(l.w), mm(l.thick), "F")
+	}
+
+	for _, t := range lay.texts {
+		style := ""
+		if t.bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 0)
+		pdf.SetFontUnitSize(mm(t.h))
+		// Text draws on the baseline, layout positions are top-left
+		pdf.Text(mm(t.x), mm(t.y)+mm(t.h)*0.8, tr(t.s))
+	}
+
+	for _, b := range lay.barcodes {
+		for i, dark := range b.modules {
+			if dark {
+				pdf.Rect(mm(b.x+i*b.module), mm(b.y), mm(b.module), mm(b.h), "F")
+			}
+		}
+	}
+
+	for _, q := range lay.qrs {
+		for y, row := range q.modules {
+			for x, dark := range row {
+				if dark {
+					pdf.Rect(mm(q.x+x*q.module), mm(q.y+y*q.module), mm(q.module), mm(q.module), "F")
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,105 @@
+package label
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	fontsOnce sync.Once
+	regular   *opentype.Font
+	bold      *opentype.Font
+	fontsErr  error
+)
+
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		if regular, fontsErr = opentype.Parse(goregular.TTF); fontsErr != nil {
+			return
+		}
+		bold, fontsErr = opentype.Parse(gobold.TTF)
+	})
+	return fontsErr
+}
+
+func fill(img *image.Gray, x, y, w, h int) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h), image.Black, image.Point{}, draw.Src)
+}
+
+func renderPNG(lay *layout) ([]byte, error) {
+	if err := loadFonts(); err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray(image.Rect(0, 0, widthDots, heightDots))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for _, l := range lay.lines {
+		fill(img, l.x, l.y, l.w, l.thick)
+	}
+
+	faces := map[[2]int]font.Face{}
+	defer func() {
+		for _, f := range faces {
+			_ = f.Close()
+		}
+	}()
+	for _, t := range lay.texts {
+		key := [2]int{t.h, 0}
+		f := regular
+		if t.bold {
+			key[1] = 1
+			f = bold
+		}
+		face, ok := faces[key]
+		if !ok {
+			var err error
+			face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: float64(t.h), DPI: 72, Hinting: font.HintingFull})
+			if err != nil {
+				return nil, err
+			}
+			faces[key] = face
+		}
+		d := font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(color.Black),
+			Face: face,
+			Dot:  fixed.P(t.x, t.y+t.h*8/10),
+		}
+		d.DrawString(t.s)
+	}
+
+	for _, b := range lay.barcodes {
+		for i, dark := range b.modules {
+			if dark {
+				fill(img, b.x+i*b.module, b.y, b.module, b.h)
+			}
+		}
+	}
+
+	for _, q := range lay.qrs {
+		for y, row := range q.modules {
+			for x, dark := range row {
+				if dark {
+					fill(img, q.x+x*q.module, q.y+y*q.module, q.module, q.module)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,46 @@
+package label
+
+import (
+	"fmt"
+	"strings"
+)
+
+// zplField strips the ZPL command prefixes so data cannot inject commands
+func zplField(s string) string {
+	return strings.NewReplacer("^", " ", "~", " ").Replace(s)
+}
+
+// renderZPL emits the layout as ZPL II; barcodes are rendered by the printer itself
+func renderZPL(lay *layout) []byte {
+	var b strings.Builder
+	b.WriteString("^XA\n^CI28\n")
+	fmt.Fprintf(&b, "^PW%d\n^LL%d\n", widthDots, heightDots)
+
+	for _, l := range lay.lines {
+		fmt.Fprintf(&b, "^FO%d,%d^GB%d,%d,%d^FS\n", l.x, l.y, l.w, l.thick, l.thick)
+	}
+
+	for _, t := range lay.texts {
+		// ^A0 is the scalable font; bold is emulated with a wider character
+		w := t.h * 9 / 10
+		if t.bold {
+			w = t.h
+		}
+		fmt.Fprintf(&b, "^FO%d,%d^A0N,%d,%d^FD%s^FS\n", t.x, t.y, t.h, w, zplField(t.s))
+	}
+
+	for _, c := range lay.barcodes {
+		fmt.Fprintf(&b, "^FO%d,%d^BY%d^BCN,%d,N,N,N^FD%s^FS\n", c.x, c.y, c.module, c.h, zplField(c.data))
+	}
+
+	for _, q := range lay.qrs {
+		mag := q.module
+		if mag > 10 {
+			mag = 10
+		}
+		fmt.Fprintf(&b, "^FO%d,%d^BQN,2,%d^FDMA,%s^FS\n", q.x, q.y, mag, zplField(q.data))
+	}
+
+	b.WriteString("^XZ\n")
+	return []byte(b.String())
+}
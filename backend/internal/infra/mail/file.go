@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer is meant for development: it writes each message as an .eml file in Dir,
+// or to the log when Dir is empty
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m *FileMailer) Send(to, subject, body string) error {
+	msg := message(m.From, clean(to), clean(subject), body)
+
+	if m.Dir == "" {
+		log.Printf("mail to %s\n%s", to, msg)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%d.eml", time.Now().Format("20060102T150405"), time.Now().UnixNano()%1_000_000)
+	return os.WriteFile(filepath.Join(m.Dir, name), msg, 0o644)
+}
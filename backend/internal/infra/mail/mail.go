@@ -0,0 +1,26 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// message renders a plain-text RFC 5322 message
+func message(from, to, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// clean drops line breaks so header values cannot inject extra headers
+func clean(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
@@ -0,0 +1,37 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"strconv"
+)
+
+func weight(kg float64) string { return strconv.FormatFloat(kg, 'f', 2, 64) }
+
+// renderCSV writes one row per order followed by a totals row
+func renderCSV(m domain.Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{
+		{"manifest_number", "station", "route", "date", "status", "#", "order_number", "pieces", "weight_kg", "size", "recipient", "destination", "zone"},
+	}
+	for i, it := range m.Items {
+		rows = append(rows, []string{
+			m.ManifestNumber, m.StationCode, m.RouteCode, m.ManifestDate.Format("2006-01-02"), string(m.Status),
+			strconv.Itoa(i + 1), it.OrderNumber, fmt.Sprint(it.Pieces), weight(it.WeightKg), string(it.SizeCode),
+			it.RecipientName, it.DestinationFullAddress, it.Zone,
+		})
+	}
+	rows = append(rows, []string{
+		m.ManifestNumber, m.StationCode, m.RouteCode, m.ManifestDate.Format("2006-01-02"), string(m.Status),
+		"", "TOTAL", fmt.Sprint(m.TotalPieces), weight(m.TotalWeightKg), "", "", "", "",
+	})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
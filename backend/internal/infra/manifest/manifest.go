@@ -0,0 +1,37 @@
+// Package manifest renders dispatch manifests as a printable PDF or as CSV.
+package manifest
+
+import (
+	"errors"
+	"logistics-app/backend/internal/domain"
+)
+
+type Format string
+
+const (
+	FormatPDF Format = "pdf"
+	FormatCSV Format = "csv"
+)
+
+var ErrUnsupportedFormat = errors.New("formato de manifiesto no soportado (pdf, csv)")
+
+// Render returns the document bytes and its content type
+func Render(format Format, m domain.Manifest) ([]byte, string, error) {
+	switch format {
+	case FormatPDF:
+		b, err := renderPDF(m)
+		return b, "application/pdf", err
+	case FormatCSV:
+		b, err := renderCSV(m)
+		return b, "text/csv; charset=utf-8", err
+	}
+
+	return nil, "", ErrUnsupportedFormat
+}
+
+func statusLabel(m domain.Manifest) string {
+	if m.Status == domain.ManifestIssued {
+		return "EMITIDO"
+	}
+	return "BORRADOR"
+}
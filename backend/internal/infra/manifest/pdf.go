@@ -0,0 +1,119 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// column widths in mm on a landscape letter page
+var columns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"#", 10, "C"},
+	{"Orden", 42, "L"},
+	{"Piezas", 16, "C"},
+	{"Peso kg", 18, "R"},
+	{"Tam.", 12, "C"},
+	{"Destinatario", 45, "L"},
+	{"Destino", 98, "L"},
+	{"Zona", 14, "C"},
+}
+
+func renderPDF(m domain.Manifest) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "Letter", "")
+	pdf.SetTitle(m.ManifestNumber, true)
+	pdf.SetMargins(12, 12, 12)
+	pdf.SetAutoPageBreak(true, 15)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, c := range columns {
+			pdf.CellFormat(c.width, 7, tr(c.title), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, tr(fmt.Sprintf("%s - página %d/{nb}", m.ManifestNumber, pdf.PageNo())), "", 0, "R", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 9, tr("Manifiesto de salida "+m.ManifestNumber), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	info := fmt.Sprintf("Estación: %s   Fecha: %s   Estado: %s", m.StationCode, m.ManifestDate.Format("2006-01-02"), statusLabel(m))
+	if m.RouteCode != "" {
+		info += "   Ruta: " + m.RouteCode
+	}
+	pdf.CellFormat(0, 6, tr(info), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("Chofer: %s   Vehículo: %s", m.DriverName, m.VehiclePlate)), "", 1, "L", false, 0, "")
+	if m.IssuedAt != nil {
+		pdf.CellFormat(0, 6, tr("Emitido: "+m.IssuedAt.Format("2006-01-02 15:04")), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	header()
+	pdf.SetFont("Helvetica", "", 8)
+	for i, it := range m.Items {
+		if pdf.GetY() > 190 {
+			pdf.AddPage()
+			header()
+			pdf.SetFont("Helvetica", "", 8)
+		}
+		values := []string{
+			fmt.Sprint(i + 1), it.OrderNumber, fmt.Sprint(it.Pieces), weight(it.WeightKg), string(it.SizeCode),
+			it.RecipientName, it.DestinationFullAddress, it.Zone,
+		}
+		for j, c := range columns {
+			pdf.CellFormat(c.width, 6, fit(pdf, tr(values[j]), c.width-2), "1", 0, c.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(columns[0].width+columns[1].width, 7, "TOTAL", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(columns[2].width, 7, fmt.Sprint(m.TotalPieces), "1", 0, "C", false, 0, "")
+	pdf.CellFormat(columns[3].width, 7, weight(m.TotalWeightKg), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(0, 7, tr(fmt.Sprintf("%d órdenes", len(m.Items))), "1", 1, "L", false, 0, "")
+
+	// signature lines
+	if pdf.GetY() > 170 {
+		pdf.AddPage()
+	}
+	pdf.Ln(22)
+	pdf.SetFont("Helvetica", "", 9)
+	y := pdf.GetY()
+	for i, who := range []string{"Entrega (estación)", "Recibe (chofer)"} {
+		x := 20 + float64(i)*130
+		pdf.Line(x, y, x+100, y)
+		pdf.SetXY(x, y+1)
+		pdf.CellFormat(100, 5, tr(who+" - nombre y firma"), "", 0, "C", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fit truncates s to the given width with the current font.
+// s is already translated to the single-byte font encoding, so it is cut by bytes.
+func fit(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	for len(s) > 0 && pdf.GetStringWidth(s+"...") > width {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
@@ -0,0 +1,65 @@
+// Package sheet reads and writes tabular files (CSV and XLSX) for order imports and exports.
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var ErrEmpty = errors.New("el archivo no tiene filas")
+
+// xlsx files are zip archives
+var zipMagic = []byte("PK\x03\x04")
+
+// Read returns the rows of a CSV file or of the first sheet of an XLSX workbook.
+// The format is detected from the content; CSV may use comma or semicolon separators.
+func Read(data []byte) ([][]string, error) {
+	var (
+		rows [][]string
+		err  error
+	)
+	if bytes.HasPrefix(data, zipMagic) {
+		rows, err = readXLSX(data)
+	} else {
+		rows, err = readCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmpty
+	}
+	return rows, nil
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrEmpty
+	}
+	return f.GetRows(sheets[0])
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	// spreadsheets in Spanish locales export with semicolons
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		r.Comma = ';'
+	}
+	return r.ReadAll()
+}
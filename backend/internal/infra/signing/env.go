@@ -0,0 +1,60 @@
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// MinSecretLength is the shortest JWT_SECRET accepted outside dev mode
+const MinSecretLength = 32
+
+// weakSecrets are well-known values that must never sign production tokens
+var weakSecrets = map[string]bool{
+	"dev_secret": true, "secret": true, "changeme": true, "change_me": true, "jwt_secret": true, "password": true,
+}
+
+// CheckSecret rejects missing, short or well-known HMAC secrets
+func CheckSecret(secret string) error {
+	switch {
+	case secret == "":
+		return errors.New("JWT_SECRET is not set")
+	case weakSecrets[strings.ToLower(secret)]:
+		return errors.New("JWT_SECRET is a well-known default value")
+	case len(secret) < MinSecretLength:
+		return fmt.Errorf("JWT_SECRET must be at least %d characters", MinSecretLength)
+	}
+	return nil
+}
+
+// FromEnv builds the key set from the environment. With JWT_KEYS_DIR tokens are signed with the
+// RSA/Ed25519 keys of that directory (JWT_ACTIVE_KID picks the signer); otherwise with HS256 and
+// JWT_SECRET, accepting JWT_PREVIOUS_SECRETS (comma separated) for rotation. Outside dev mode a
+// missing or weak secret is an error; in dev mode it falls back to an insecure default.
+func FromEnv(devMode bool) (*KeySet, error) {
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		return LoadDir(dir, os.Getenv("JWT_ACTIVE_KID"))
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	if err := CheckSecret(secret); err != nil {
+		if !devMode {
+			return nil, fmt.Errorf("%w (set a random secret of at least %d characters, or APP_ENV=dev for local development)", err, MinSecretLength)
+		}
+		log.Printf("WARNING: %v; using an insecure secret, allowed only because APP_ENV=dev", err)
+		if secret == "" {
+			secret = "dev_secret"
+		}
+	}
+
+	var previous [][]byte
+	for _, p := range strings.Split(os.Getenv("JWT_PREVIOUS_SECRETS"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			previous = append(previous, []byte(p))
+		}
+	}
+
+	return NewHMAC([]byte(secret), previous...), nil
+}
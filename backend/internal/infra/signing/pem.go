@@ -0,0 +1,109 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSABits = 2048
+
+// parsePEM reads a PKCS#8 / PKCS#1 private key or a PKIX public key, RSA or Ed25519
+func parsePEM(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM block", kid)
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM type %q", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", kid, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("key %s: RSA keys must have at least %d bits", kid, minRSABits)
+		}
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, signKey: k, verifyKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("key %s: RSA keys must have at least %d bits", kid, minRSABits)
+		}
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, verifyKey: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, signKey: k, verifyKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, verifyKey: k}, nil
+	}
+	return nil, fmt.Errorf("key %s: only RSA and Ed25519 keys are supported", kid)
+}
+
+// LoadDir loads every <kid>.pem file of dir. The key named activeKID signs; it may be omitted when
+// the directory holds a single private key. Other keys, including public-only ones of retired
+// private keys, only verify.
+func LoadDir(dir, activeKID string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var keys []*Key
+	var private []*Key
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		k, err := parsePEM(strings.TrimSuffix(filepath.Base(f), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+		if k.signKey != nil {
+			private = append(private, k)
+		}
+	}
+
+	var active *Key
+	switch {
+	case activeKID != "":
+		for _, k := range private {
+			if k.ID == activeKID {
+				active = k
+			}
+		}
+		if active == nil {
+			return nil, fmt.Errorf("no private key %s.pem in %s", activeKID, dir)
+		}
+	case len(private) == 1:
+		active = private[0]
+	case len(private) == 0:
+		return nil, fmt.Errorf("no private key in %s", dir)
+	default:
+		return nil, errors.New("several private keys found, set the active one with JWT_ACTIVE_KID")
+	}
+
+	return newKeySet(active, keys...), nil
+}
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"logistics-app/backend/internal/domain"
 	"logistics-app/backend/internal/infra/db"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -19,11 +21,11 @@ type AddressWithCoords struct {
 	Coordinates *domain.Coordinates `json:"coordinates,omitempty"`
 }
 
-func (r *AddressGormRepo) CreateWithCoordinates(customerID uint, payload AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
+func (r *AddressGormRepo) CreateWithCoordinates(ctx context.Context, owner domain.Scope, payload AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
 	var createdAddr domain.Address
 	var createdCoord *domain.Coordinates
 
-	if err := r.db.Transaction(func(tx *gorm.DB) error {
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var coordID *uint
 		if payload.Coordinates != nil {
 			c := *payload.Coordinates
@@ -34,7 +36,8 @@ func (r *AddressGormRepo) CreateWithCoordinates(customerID uint, payload Address
 			coordID = &c.ID
 		}
 		a := payload.Address
-		a.CustomerID = customerID
+		a.CustomerID = owner.UserID
+		a.OrganizationID = owner.OrganizationID
 		a.CoordinateID = coordID
 		if err := tx.Create(&a).Error; err != nil {
 			return err
@@ -48,20 +51,25 @@ func (r *AddressGormRepo) CreateWithCoordinates(customerID uint, payload Address
 	return &createdAddr, createdCoord, nil
 }
 
-func (r *AddressGormRepo) UpdateWithCoordinates(requesterID uint, isAdmin bool, id uint, payload AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
+// UpdateWithCoordinates overwrites the address when it is still at version (0 skips the check)
+func (r *AddressGormRepo) UpdateWithCoordinates(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, payload AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
 	var outAddr domain.Address
 	var outCoord *domain.Coordinates
 
-	if err := r.db.Transaction(func(tx *gorm.DB) error {
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var existing domain.Address
 		q := tx.Where("id = ?", id)
 		if !isAdmin {
-			q = q.Where("customer_id = ?", requesterID)
+			q = inScope(q, scope, "")
 		}
 		if err := q.First(&existing).Error; err != nil {
 			return err
 		}
 
+		if version != 0 && existing.Version != version {
+			return ErrStaleVersion
+		}
+
 		if payload.Coordinates != nil {
 			if existing.CoordinateID != nil {
 				// update existing coord
@@ -95,9 +103,15 @@ func (r *AddressGormRepo) UpdateWithCoordinates(requesterID uint, isAdmin bool,
 			"city":            payload.Address.City,
 			"state":           payload.Address.State,
 			"country":         payload.Address.Country,
+			"coordinate_id":   existing.CoordinateID,
+			"version":         gorm.Expr("version + 1"),
 		}
-		if err := tx.Model(&domain.Address{}).Where("id = ?", existing.ID).Updates(u).Error; err != nil {
-			return err
+		res := tx.Model(&domain.Address{}).Where("id = ? AND version = ?", existing.ID, existing.Version).Updates(u)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrStaleVersion
 		}
 
 		if err := tx.First(&outAddr, existing.ID).Error; err != nil {
@@ -112,12 +126,12 @@ func (r *AddressGormRepo) UpdateWithCoordinates(requesterID uint, isAdmin bool,
 	return &outAddr, outCoord, nil
 }
 
-func (r *AddressGormRepo) FindByID(requesterID uint, isAdmin bool, id uint) (*domain.Address, error) {
+func (r *AddressGormRepo) FindByID(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) (*domain.Address, error) {
 	var a domain.Address
-	q := r.db.Where("id = ?", id)
+	q := r.db.WithContext(ctx).Where("id = ?", id)
 
 	if !isAdmin {
-		q = q.Where("customer_id = ?", requesterID)
+		q = inScope(q, scope, "")
 	}
 
 	if err := q.First(&a).Error; err != nil {
@@ -127,12 +141,33 @@ func (r *AddressGormRepo) FindByID(requesterID uint, isAdmin bool, id uint) (*do
 	return &a, nil
 }
 
-func (r *AddressGormRepo) List(requesterID uint, isAdmin bool, includeInactive bool) ([]domain.Address, error) {
+// FindMatch returns an active address of the scope with the same street, numbers, postal code and city
+// (case and surrounding spaces ignored), or nil when there is none
+func (r *AddressGormRepo) FindMatch(ctx context.Context, scope domain.Scope, a domain.Address) (*domain.Address, error) {
+	var list []domain.Address
+	norm := func(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+
+	if err := inScope(r.db.WithContext(ctx), scope, "").Where("is_active = ?", true).
+		Where("lower(trim(street)) = ? AND lower(trim(exterior_number)) = ? AND lower(trim(interior_number)) = ?",
+			norm(a.Street), norm(a.ExteriorNumber), norm(a.InteriorNumber)).
+		Where("trim(postal_code) = ? AND lower(trim(city)) = ?", strings.TrimSpace(a.PostalCode), norm(a.City)).
+		Order("id asc").Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+func (r *AddressGormRepo) List(ctx context.Context, scope domain.Scope, isAdmin bool, includeInactive bool) ([]domain.Address, error) {
 	var list []domain.Address
-	q := r.db.Model(&domain.Address{})
+	q := r.db.WithContext(ctx).Model(&domain.Address{})
 
 	if !isAdmin {
-		q = q.Where("customer_id = ?", requesterID).Where("is_active = ?", true)
+		q = inScope(q, scope, "").Where("is_active = ?", true)
 	} else if !includeInactive {
 		q = q.Where("is_active = ?", true)
 	}
@@ -144,33 +179,43 @@ func (r *AddressGormRepo) List(requesterID uint, isAdmin bool, includeInactive b
 	return list, nil
 }
 
-func (r *AddressGormRepo) ToggleActive(requesterID uint, isAdmin bool, id uint, active bool) error {
+// ToggleActive sets is_active when the address is still at version (0 skips the check)
+func (r *AddressGormRepo) ToggleActive(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, active bool) error {
 	// Only owner or admin can toggle
-	q := r.db.Model(&domain.Address{}).Where("id = ?", id)
+	q := r.db.WithContext(ctx).Model(&domain.Address{}).Where("id = ?", id)
 
 	if !isAdmin {
-		q = q.Where("customer_id = ?", requesterID)
+		q = inScope(q, scope, "")
+	}
+	if version != 0 {
+		q = q.Where("version = ?", version)
 	}
-	res := q.Update("is_active", active)
+	res := q.Updates(map[string]interface{}{"is_active": active, "version": gorm.Expr("version + 1")})
 
 	if res.Error != nil {
 		return res.Error
 	}
 
 	if res.RowsAffected == 0 {
+		if version != 0 {
+			var n int64
+			if err := r.db.WithContext(ctx).Model(&domain.Address{}).Where("id = ?", id).Count(&n).Error; err == nil && n > 0 {
+				return ErrStaleVersion
+			}
+		}
 		return gorm.ErrRecordNotFound
 	}
 
 	return nil
 }
 
-func (r *AddressGormRepo) Delete(requesterID uint, isAdmin bool, id uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *AddressGormRepo) Delete(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var a domain.Address
 		q := tx.Where("id = ?", id)
 
 		if !isAdmin {
-			q = q.Where("customer_id = ?", requesterID)
+			q = inScope(q, scope, "")
 		}
 
 		if err := q.First(&a).Error; err != nil {
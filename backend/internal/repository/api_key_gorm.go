@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyTouchInterval limits how often last_used_at is written for a busy key
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyGormRepo struct{ db *gorm.DB }
+
+func NewAPIKeyGormRepo(database *db.Database) *APIKeyGormRepo {
+	return &APIKeyGormRepo{db: database.DB}
+}
+
+func (r *APIKeyGormRepo) Create(ctx context.Context, k *domain.APIKey) error {
+	return r.db.WithContext(ctx).Create(k).Error
+}
+
+// ListByOrganization returns the organization's keys, newest first
+func (r *APIKeyGormRepo) ListByOrganization(ctx context.Context, orgID uint) ([]domain.APIKey, error) {
+	var list []domain.APIKey
+
+	if err := r.db.WithContext(ctx).Where("organization_id = ?", orgID).Order("id desc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *APIKeyGormRepo) FindByID(ctx context.Context, id uint) (*domain.APIKey, error) {
+	var k domain.APIKey
+
+	if err := r.db.WithContext(ctx).First(&k, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &k, nil
+}
+
+// FindByHash returns the key with that hash, or nil when there is none
+func (r *APIKeyGormRepo) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var list []domain.APIKey
+
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", hash).Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+// Rotate stores next and retires old: revoked at once, or expiring at oldUntil for a grace period.
+// ErrTokenUsed when old was revoked or rotated by a concurrent request.
+func (r *APIKeyGormRepo) Rotate(ctx context.Context, old *domain.APIKey, oldUntil *time.Time, next *domain.APIKey) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"revoked_at": time.Now()}
+		if oldUntil != nil {
+			updates = map[string]interface{}{"expires_at": *oldUntil}
+		}
+		res := tx.Model(&domain.APIKey{}).
+			Where("id = ? AND revoked_at IS NULL AND NOT EXISTS (SELECT 1 FROM api_keys n WHERE n.rotated_from_id = ?)", old.ID, old.ID).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrTokenUsed
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+func (r *APIKeyGormRepo) Revoke(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// Touch records a use of the key, at most once per apiKeyTouchInterval
+func (r *APIKeyGormRepo) Touch(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-apiKeyTouchInterval)).
+		Update("last_used_at", now).Error
+}
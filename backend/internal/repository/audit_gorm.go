@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+
+	"gorm.io/gorm"
+)
+
+type AuditGormRepo struct{ db *gorm.DB }
+
+func NewAuditGormRepo(database *db.Database) *AuditGormRepo {
+	return &AuditGormRepo{db: database.DB}
+}
+
+func (r *AuditGormRepo) Create(ctx context.Context, a *domain.AuditLog) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DeliveryAttemptGormRepo struct{ db *gorm.DB }
+
+func NewDeliveryAttemptGormRepo(database *db.Database) *DeliveryAttemptGormRepo {
+	return &DeliveryAttemptGormRepo{db: database.DB}
+}
+
+// RecordAttempt stores the attempt, bumps the order counter, moves the order to next
+// and writes the timeline entry in a single transaction.
+func (r *DeliveryAttemptGormRepo) RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt, next domain.OrderStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Guard against concurrent attempts: the order must still be in route with the expected counter
+		res := tx.Model(&domain.Order{}).
+			Where("id = ? AND status = ? AND delivery_attempts = ?", a.OrderID, domain.OrderInRoute, a.AttemptNumber-1).
+			Updates(map[string]interface{}{
+				"delivery_attempts":       a.AttemptNumber,
+				"status":                  next,
+				"scheduled_delivery_date": nil,
+				"updated_by":              a.RecordedBy,
+				"version":                 gorm.Expr("version + 1"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("order is no longer in route")
+		}
+
+		if a.AttemptedAt.IsZero() {
+			a.AttemptedAt = time.Now()
+		}
+		if err := tx.Create(a).Error; err != nil {
+			return err
+		}
+
+		notes := fmt.Sprintf("delivery attempt #%d failed: %s", a.AttemptNumber, a.Reason)
+		if n := strings.TrimSpace(a.Notes); n != "" {
+			notes += ". " + n
+		}
+		h := domain.OrderStatusHistory{
+			OrderID:        a.OrderID,
+			PreviousStatus: domain.OrderInRoute,
+			NewStatus:      next,
+			ChangedAt:      a.AttemptedAt,
+			ChangedBy:      a.RecordedBy,
+			Notes:          notes,
+		}
+		return tx.Create(&h).Error
+	})
+}
+
+func (r *DeliveryAttemptGormRepo) ListByOrder(ctx context.Context, orderID uint) ([]domain.DeliveryAttempt, error) {
+	var list []domain.DeliveryAttempt
+
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("attempt_number asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Reschedule sets the redelivery date when the order is still awaiting redelivery at version
+func (r *DeliveryAttemptGormRepo) Reschedule(ctx context.Context, orderID uint, version uint, date time.Time, changedBy uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Order{}).
+			Where("id = ? AND status = ? AND version = ?", orderID, domain.OrderDeliveryFailed, version).
+			Updates(map[string]interface{}{"scheduled_delivery_date": date, "updated_by": changedBy, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		h := domain.OrderStatusHistory{
+			OrderID:        orderID,
+			PreviousStatus: domain.OrderDeliveryFailed,
+			NewStatus:      domain.OrderDeliveryFailed,
+			ChangedAt:      time.Now(),
+			ChangedBy:      changedBy,
+			Notes:          "redelivery scheduled for " + date.Format("2006-01-02"),
+		}
+		return tx.Create(&h).Error
+	})
+}
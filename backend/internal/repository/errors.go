@@ -0,0 +1,21 @@
+package repository
+
+import "errors"
+
+// ErrStaleVersion is returned when a guarded update finds the row at another version than expected
+var ErrStaleVersion = errors.New("stale version")
+
+// ErrTokenUsed is returned when a single-use token was consumed by another request
+var ErrTokenUsed = errors.New("token already used")
+
+// errRefreshUsed rolls back a rotation that lost the race for the refresh token
+var errRefreshUsed = errors.New("refresh token already used")
+
+// ErrAlreadyMember is returned when a user who already belongs to an organization would join another
+var ErrAlreadyMember = errors.New("user already belongs to an organization")
+
+// ErrTerminalStatus is returned when a status change targets an order that is already closed
+var ErrTerminalStatus = errors.New("order is in a terminal status")
+
+// ErrDuplicateOrderNumber is returned when another order already uses the order number
+var ErrDuplicateOrderNumber = errors.New("duplicate order number")
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyGormRepo struct{ db *gorm.DB }
+
+func NewIdempotencyGormRepo(database *db.Database) *IdempotencyGormRepo {
+	return &IdempotencyGormRepo{db: database.DB}
+}
+
+// Find returns the live record for the key, or nil when there is none or it expired
+func (r *IdempotencyGormRepo) Find(ctx context.Context, userID uint, endpoint, key string) (*domain.IdempotencyKey, error) {
+	var list []domain.IdempotencyKey
+
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND endpoint = ? AND key = ? AND expires_at > ?", userID, endpoint, key, time.Now()).
+		Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+// Reserve inserts the pending record after purging expired ones. It returns false when
+// another request already holds the key.
+func (r *IdempotencyGormRepo) Reserve(ctx context.Context, k *domain.IdempotencyKey) (bool, error) {
+	reserved := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", time.Now()).Delete(&domain.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(k)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		reserved = res.RowsAffected == 1
+		return nil
+	})
+
+	return reserved, err
+}
+
+func (r *IdempotencyGormRepo) Complete(ctx context.Context, id uint, statusCode int, body []byte) error {
+	return r.db.WithContext(ctx).Model(&domain.IdempotencyKey{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status_code": statusCode, "response_body": body}).Error
+}
+
+func (r *IdempotencyGormRepo) Release(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.IdempotencyKey{}, id).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LoginThrottleGormRepo struct{ db *gorm.DB }
+
+func NewLoginThrottleGormRepo(database *db.Database) *LoginThrottleGormRepo {
+	return &LoginThrottleGormRepo{db: database.DB}
+}
+
+// Find returns the counters of the key, or nil when it has none
+func (r *LoginThrottleGormRepo) Find(ctx context.Context, key string) (*domain.LoginThrottle, error) {
+	var list []domain.LoginThrottle
+
+	if err := r.db.WithContext(ctx).Where("key = ?", key).Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+// RegisterFailure atomically counts a failed attempt. Failures older than window start a new count,
+// and the lockout streak is forgotten after a quiet day.
+func (r *LoginThrottleGormRepo) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginThrottle, error) {
+	var t domain.LoginThrottle
+
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO login_throttles (key, failures, last_failure_at, lock_count)
+		VALUES (?, 1, ?, 0)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			lock_count = CASE WHEN login_throttles.last_failure_at < ? THEN 0 ELSE login_throttles.lock_count END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING *`,
+		key, now, now.Add(-window), now.Add(-24*time.Hour)).Scan(&t).Error
+
+	return &t, err
+}
+
+// Lock locks the key until the given time and restarts its failure count. It returns false when
+// a concurrent request already locked it.
+func (r *LoginThrottleGormRepo) Lock(ctx context.Context, key string, threshold uint, until time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.LoginThrottle{}).Where("key = ? AND failures >= ?", key, threshold).
+		Updates(map[string]interface{}{"failures": 0, "locked_until": until, "lock_count": gorm.Expr("lock_count + 1")})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *LoginThrottleGormRepo) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("key = ?", key).Delete(&domain.LoginThrottle{}).Error
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ManifestGormRepo struct{ db *gorm.DB }
+
+func NewManifestGormRepo(database *db.Database) *ManifestGormRepo {
+	return &ManifestGormRepo{db: database.DB}
+}
+
+// internal struct for scanning manifest item rows
+type manifestItemRow struct {
+	Id                uint
+	OrderNumber       string
+	Quantity          uint
+	ActualWeightKg    float64
+	SizeCode          domain.PackageSize
+	FullName          string
+	PrefRecipientName string
+	ADStreet          string
+	ADExterior        string
+	ADInterior        string
+	ADNeighborhood    string
+	ADCity            string
+	ADPostal          string
+}
+
+// CollectItems snapshots the orders that left the station (outbound or load scans) on the day,
+// optionally limited to one route. Orders already on another issued manifest of the station for
+// the day are skipped, whatever its route, so an order isn't on both a station-wide and a route
+// manifest. Other days stay open for orders that leave again after a failed delivery.
+func (r *ManifestGormRepo) CollectItems(ctx context.Context, stationID uint, day time.Time, routeCode string, manifestID uint) ([]domain.ManifestItem, error) {
+	scans := r.db.WithContext(ctx).Model(&domain.Scan{}).Select("order_id").
+		Where("station_id = ? AND result = ? AND scan_type IN ? AND new_status = ?",
+			stationID, domain.ScanApplied, []domain.ScanType{domain.ScanOutbound, domain.ScanLoad}, domain.OrderInRoute).
+		Where("scanned_at >= ? AND scanned_at < ?", day, day.AddDate(0, 0, 1))
+	if routeCode != "" {
+		scans = scans.Where("route_code = ?", routeCode)
+	}
+
+	issued := r.db.WithContext(ctx).Table("manifest_items as mi").Select("mi.order_id").
+		Joins("inner join manifests m on m.id = mi.manifest_id").
+		Where("m.status = ? AND m.station_id = ? AND m.manifest_date = ? AND m.id <> ?",
+			domain.ManifestIssued, stationID, day, manifestID)
+
+	var rows []manifestItemRow
+	q := r.db.WithContext(ctx).Table("orders as o").
+		Select("o.id, o.order_number, o.quantity, o.actual_weight_kg, pt.size_code, u.full_name, o.pref_recipient_name, ad.street as ad_street, ad.exterior_number as ad_exterior, ad.interior_number as ad_interior, ad.neighborhood as ad_neighborhood, ad.city as ad_city, ad.postal_code as ad_postal").
+		Joins("inner join users u on o.customer_id = u.id").
+		Joins("inner join addresses ad on o.destination_address_id = ad.id").
+		Joins("inner join package_types pt on o.package_type_id = pt.id").
+		Where("o.id IN (?)", scans).
+		Where("o.id NOT IN (?)", issued).
+		Order("ad.postal_code asc, o.order_number asc")
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.ManifestItem, 0, len(rows))
+	for _, row := range rows {
+		name := row.PrefRecipientName
+		if name == "" {
+			name = row.FullName
+		}
+		items = append(items, domain.ManifestItem{
+			OrderID:                row.Id,
+			OrderNumber:            row.OrderNumber,
+			Pieces:                 row.Quantity,
+			WeightKg:               row.ActualWeightKg,
+			SizeCode:               row.SizeCode,
+			RecipientName:          name,
+			DestinationFullAddress: strings.TrimSpace(strings.Join([]string{row.ADStreet, row.ADExterior, row.ADInterior, row.ADNeighborhood, row.ADCity, row.ADPostal}, " ")),
+			Zone:                   domain.ZoneFromPostalCode(row.ADPostal),
+		})
+	}
+
+	return items, nil
+}
+
+func (r *ManifestGormRepo) Create(ctx context.Context, m *domain.Manifest) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+// ReplaceItems swaps the snapshot of a draft manifest; issued manifests are left untouched
+func (r *ManifestGormRepo) ReplaceItems(ctx context.Context, m *domain.Manifest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Manifest{}).
+			Where("id = ? AND status = ?", m.ID, domain.ManifestDraft).
+			Updates(map[string]interface{}{"total_pieces": m.TotalPieces, "total_weight_kg": m.TotalWeightKg, "updated_at": time.Now()})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return errors.New("manifest is not a draft")
+		}
+
+		if err := tx.Where("manifest_id = ?", m.ID).Delete(&domain.ManifestItem{}).Error; err != nil {
+			return err
+		}
+
+		for i := range m.Items {
+			m.Items[i].ID = 0
+			m.Items[i].ManifestID = m.ID
+		}
+		if len(m.Items) == 0 {
+			return nil
+		}
+		return tx.Create(&m.Items).Error
+	})
+}
+
+// Issue freezes a draft manifest and stamps its ID on the history of every listed order
+func (r *ManifestGormRepo) Issue(ctx context.Context, m *domain.Manifest, issuedBy uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&domain.Manifest{}).
+			Where("id = ? AND status = ?", m.ID, domain.ManifestDraft).
+			Updates(map[string]interface{}{"status": domain.ManifestIssued, "issued_by": issuedBy, "issued_at": now})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return errors.New("manifest is not a draft")
+		}
+
+		for _, it := range m.Items {
+			var o domain.Order
+			if err := tx.Select("id", "status").First(&o, it.OrderID).Error; err != nil {
+				return err
+			}
+			h := domain.OrderStatusHistory{
+				OrderID:        o.ID,
+				PreviousStatus: o.Status,
+				NewStatus:      o.Status,
+				ChangedAt:      now,
+				ChangedBy:      issuedBy,
+				Notes:          fmt.Sprintf("listed on manifest %s", m.ManifestNumber),
+				ManifestID:     &m.ID,
+			}
+			if err := tx.Create(&h).Error; err != nil {
+				return err
+			}
+		}
+
+		m.Status = domain.ManifestIssued
+		m.IssuedBy = &issuedBy
+		m.IssuedAt = &now
+		return nil
+	})
+}
+
+func (r *ManifestGormRepo) FindByID(ctx context.Context, id uint) (*domain.Manifest, error) {
+	var m domain.Manifest
+
+	if err := r.db.WithContext(ctx).Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id asc")
+	}).First(&m, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (r *ManifestGormRepo) List(ctx context.Context, stationID uint, day *time.Time) ([]domain.Manifest, error) {
+	var list []domain.Manifest
+	q := r.db.WithContext(ctx).Model(&domain.Manifest{})
+
+	if stationID != 0 {
+		q = q.Where("station_id = ?", stationID)
+	}
+
+	if day != nil {
+		q = q.Where("manifest_date = ?", *day)
+	}
+
+	if err := q.Order("manifest_date desc, id desc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MFAGormRepo struct{ db *gorm.DB }
+
+func NewMFAGormRepo(database *db.Database) *MFAGormRepo {
+	return &MFAGormRepo{db: database.DB}
+}
+
+// FindUser loads the user including the MFA columns
+func (r *MFAGormRepo) FindUser(ctx context.Context, userID uint) (*domain.User, error) {
+	var u domain.User
+	if err := r.db.WithContext(ctx).First(&u, userID).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// SetPendingSecret stores a new secret for a user that has not enabled MFA yet
+func (r *MFAGormRepo) SetPendingSecret(ctx context.Context, userID uint, secret string) error {
+	res := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ? AND mfa_enabled = ?", userID, false).
+		Updates(map[string]interface{}{"mfa_secret": secret, "mfa_last_step": 0})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
+func replaceCodes(tx *gorm.DB, userID uint, hashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&domain.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]domain.MFARecoveryCode, len(hashes))
+	for i, h := range hashes {
+		codes[i] = domain.MFARecoveryCode{UserID: userID, CodeHash: h}
+	}
+	return tx.Create(&codes).Error
+}
+
+// Enable turns MFA on with the step of the confirming code and stores the recovery codes
+func (r *MFAGormRepo) Enable(ctx context.Context, userID uint, step int64, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.User{}).Where("id = ? AND mfa_enabled = ?", userID, false).
+			Updates(map[string]interface{}{"mfa_enabled": true, "mfa_last_step": step})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+		return replaceCodes(tx, userID, hashes)
+	})
+}
+
+// UseStep records the TOTP step as used; false when that step or a later one was already used
+func (r *MFAGormRepo) UseStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ? AND mfa_last_step < ?", userID, step).Update("mfa_last_step", step)
+	return res.RowsAffected == 1, res.Error
+}
+
+// UseRecoveryCode consumes an unused recovery code of the user; false when there is none
+func (r *MFAGormRepo) UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *MFAGormRepo) ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceCodes(tx, userID, hashes)
+	})
+}
+
+// Disable clears the secret and deletes the recovery codes
+func (r *MFAGormRepo) Disable(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"mfa_enabled": false, "mfa_secret": "", "mfa_last_step": 0}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.MFARecoveryCode{}).Error
+	})
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+
+	"gorm.io/gorm"
+)
+
+type OrderChangeGormRepo struct{ db *gorm.DB }
+
+func NewOrderChangeGormRepo(database *db.Database) *OrderChangeGormRepo {
+	return &OrderChangeGormRepo{db: database.DB}
+}
+
+// ApplyEdit writes the edited columns and the change log in one transaction. The order must still be
+// `created` and at version; otherwise nothing is written and ErrStaleVersion is returned.
+func (r *OrderChangeGormRepo) ApplyEdit(ctx context.Context, orderID uint, version uint, updates map[string]interface{}, changes []domain.OrderChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates["version"] = gorm.Expr("version + 1")
+		res := tx.Model(&domain.Order{}).
+			Where("id = ? AND version = ? AND status = ?", orderID, version, domain.OrderCreated).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Create(&changes).Error
+	})
+}
+
+func (r *OrderChangeGormRepo) ListByOrder(ctx context.Context, orderID uint) ([]domain.OrderChange, error) {
+	var list []domain.OrderChange
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("changed_at ASC, id ASC").Find(&list).Error
+	return list, err
+}
@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"logistics-app/backend/internal/domain"
 	"logistics-app/backend/internal/infra/db"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
@@ -38,28 +40,39 @@ func NewOrderGormRepo(database *db.Database) *OrderGormRepo {
 	return &OrderGormRepo{db: database.DB}
 }
 
-func (r *OrderGormRepo) Create(o *domain.Order) error {
-	return r.db.Create(o).Error
+// Create runs in its own (nested) transaction so a duplicate order number only rolls back
+// to the savepoint and the caller can retry inside an import transaction
+func (r *OrderGormRepo) Create(ctx context.Context, o *domain.Order) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(o).Error
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && strings.Contains(pgErr.ConstraintName, "order_number") {
+		return ErrDuplicateOrderNumber
+	}
+	return err
 }
 
-func (r *OrderGormRepo) FindByID(id uint) (*domain.Order, error) {
+func (r *OrderGormRepo) FindByID(ctx context.Context, id uint) (*domain.Order, error) {
 	var o domain.Order
 
-	if err := r.db.First(&o, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&o, id).Error; err != nil {
 		return nil, err
 	}
 	return &o, nil
 }
 
-func (r *OrderGormRepo) FindDetailByID(id uint) (*domain.OrderDetail, error) {
+func (r *OrderGormRepo) FindDetailByID(ctx context.Context, id uint) (*domain.OrderDetail, error) {
 	var d domain.OrderDetail
 
-	q := r.db.Table("orders as o").
-		Select("o.id, o.order_number, o.created_at, u.id as user_id, u.full_name, o.origin_address_id, ao.street as ao_street, ao.exterior_number as ao_exterior, ao.neighborhood as ao_neighborhood, ao.city as ao_city, ao.postal_code as ao_postal, o.destination_address_id, ad.street as ad_street, ad.exterior_number as ad_exterior, ad.neighborhood as ad_neighborhood, ad.city as ad_city, ad.postal_code as ad_postal, o.quantity, o.actual_weight_kg, o.package_type_id, pt.size_code, o.observations, o.internal_notes, o.updated_at, o.status").
+	q := r.db.WithContext(ctx).Table("orders as o").
+		Select("o.id, o.order_number, o.created_at, u.id as user_id, o.organization_id, u.full_name, o.origin_address_id, ao.street as ao_street, ao.exterior_number as ao_exterior, ao.neighborhood as ao_neighborhood, ao.city as ao_city, ao.postal_code as ao_postal, o.destination_address_id, ad.street as ad_street, ad.exterior_number as ad_exterior, ad.neighborhood as ad_neighborhood, ad.city as ad_city, ad.postal_code as ad_postal, o.quantity, o.actual_weight_kg, o.package_type_id, pt.size_code, o.observations, o.internal_notes, o.updated_at, o.status, o.delivery_attempts, o.scheduled_delivery_date, o.return_of_order_id, coalesce(oo.order_number, '') as return_of_order_number, ro.id as return_order_id, coalesce(ro.order_number, '') as return_order_number, o.pref_window_start, o.pref_window_end, o.pref_leave_with, o.pref_access_instructions, o.pref_recipient_name, o.pref_recipient_phone, o.version").
 		Joins("inner join users u on o.customer_id = u.id").
 		Joins("inner join addresses ao on o.origin_address_id = ao.id").
 		Joins("inner join addresses ad on o.destination_address_id = ad.id").
 		Joins("inner join package_types pt on o.package_type_id = pt.id").
+		Joins("left join orders oo on o.return_of_order_id = oo.id").
+		Joins("left join orders ro on ro.return_of_order_id = o.id").
 		Where("o.id = ?", id)
 
 	if err := q.Take(&d).Error; err != nil {
@@ -69,73 +82,221 @@ func (r *OrderGormRepo) FindDetailByID(id uint) (*domain.OrderDetail, error) {
 	return &d, nil
 }
 
-func (r *OrderGormRepo) FindByCustomer(customerID uint) ([]domain.Order, error) {
+func (r *OrderGormRepo) FindReturnOf(ctx context.Context, orderID uint) (*domain.Order, error) {
+	var o domain.Order
+
+	if err := r.db.WithContext(ctx).Where("return_of_order_id = ?", orderID).First(&o).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *OrderGormRepo) FindByCustomer(ctx context.Context, scope domain.Scope) ([]domain.Order, error) {
 	var list []domain.Order
 
-	if err := r.db.Where("customer_id = ?", customerID).Find(&list).Error; err != nil {
+	if err := inScope(r.db.WithContext(ctx), scope, "").Find(&list).Error; err != nil {
 		return nil, err
 	}
 
 	return list, nil
 }
 
-func (r *OrderGormRepo) FindAll() ([]domain.Order, error) {
+func (r *OrderGormRepo) FindAll(ctx context.Context) ([]domain.Order, error) {
 	var list []domain.Order
 
-	if err := r.db.Find(&list).Error; err != nil {
+	if err := r.db.WithContext(ctx).Find(&list).Error; err != nil {
 		return nil, err
 	}
 
 	return list, nil
 }
 
-func (r *OrderGormRepo) findJoined(base *gorm.DB) ([]domain.OrderListItem, error) {
-	var rows []orderJoinedRow
-	q := base.Table("orders as o").
-		Select("o.id, o.order_number, o.created_at, u.full_name, ao.street as ao_street, ao.exterior_number as ao_exterior, ao.neighborhood as ao_neighborhood, ao.city as ao_city, ao.postal_code as ao_postal, ad.street as ad_street, ad.exterior_number as ad_exterior, ad.neighborhood as ad_neighborhood, ad.city as ad_city, ad.postal_code as ad_postal, o.quantity, o.actual_weight_kg, pt.size_code, o.status").
+const joinedColumns = "o.id, o.order_number, o.created_at, u.full_name, ao.street as ao_street, ao.exterior_number as ao_exterior, ao.neighborhood as ao_neighborhood, ao.city as ao_city, ao.postal_code as ao_postal, ad.street as ad_street, ad.exterior_number as ad_exterior, ad.neighborhood as ad_neighborhood, ad.city as ad_city, ad.postal_code as ad_postal, o.quantity, o.actual_weight_kg, pt.size_code, o.status"
+
+func joinedQuery(base *gorm.DB) *gorm.DB {
+	return base.Table("orders as o").
 		Joins("inner join users u on o.customer_id = u.id").
 		Joins("inner join addresses ao on o.origin_address_id = ao.id").
 		Joins("inner join addresses ad on o.destination_address_id = ad.id").
 		Joins("inner join package_types pt on o.package_type_id = pt.id")
+}
+
+// map row to DTO
+func (rrow orderJoinedRow) listItem() domain.OrderListItem {
+	origin := strings.TrimSpace(strings.Join([]string{rrow.AOStreet, rrow.AOExterior, rrow.AONeighborhood, rrow.AOCity, rrow.AOPostal}, " "))
+	dest := strings.TrimSpace(strings.Join([]string{rrow.ADStreet, rrow.ADExterior, rrow.ADNeighborhood, rrow.ADCity, rrow.ADPostal}, " "))
+	return domain.OrderListItem{
+		ID:                     rrow.Id,
+		OrderNumber:            rrow.OrderNumber,
+		CreatedAt:              rrow.CreatedAt.Format("02/01/2006"),
+		FullName:               rrow.FullName,
+		OriginFullAddress:      origin,
+		DestinationFullAddress: dest,
+		Quantity:               rrow.Quantity,
+		ActualWeightKg:         rrow.ActualWeightKg,
+		SizeCode:               rrow.SizeCode,
+		Status:                 rrow.Status,
+	}
+}
+
+func (r *OrderGormRepo) findJoined(base *gorm.DB) ([]domain.OrderListItem, error) {
+	var rows []orderJoinedRow
+	q := joinedQuery(base).Select(joinedColumns)
 	if err := q.Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
-	// map rows to DTO
 	items := make([]domain.OrderListItem, 0, len(rows))
 	for _, rrow := range rows {
-		origin := strings.TrimSpace(strings.Join([]string{rrow.AOStreet, rrow.AOExterior, rrow.AONeighborhood, rrow.AOCity, rrow.AOPostal}, " "))
-		dest := strings.TrimSpace(strings.Join([]string{rrow.ADStreet, rrow.ADExterior, rrow.ADNeighborhood, rrow.ADCity, rrow.ADPostal}, " "))
-		item := domain.OrderListItem{
-			ID:                     rrow.Id,
-			OrderNumber:            rrow.OrderNumber,
-			CreatedAt:              rrow.CreatedAt.Format("02/01/2006"),
-			FullName:               rrow.FullName,
-			OriginFullAddress:      origin,
-			DestinationFullAddress: dest,
-			Quantity:               rrow.Quantity,
-			ActualWeightKg:         rrow.ActualWeightKg,
-			SizeCode:               rrow.SizeCode,
-			Status:                 rrow.Status,
-		}
-		items = append(items, item)
+		items = append(items, rrow.listItem())
 	}
 
 	return items, nil
 }
 
-func (r *OrderGormRepo) FindJoinedByCustomer(customerID uint) ([]domain.OrderListItem, error) {
-	base := r.db.Where("o.customer_id = ?", customerID)
+// internal struct for scanning export rows
+type orderExportRow struct {
+	Joined                orderJoinedRow `gorm:"embedded"`
+	Observations          string
+	DeliveryAttempts      uint
+	ScheduledDeliveryDate *time.Time
+	UpdatedAt             time.Time
+	Preferences           domain.DeliveryPreferences `gorm:"embedded;embeddedPrefix:pref_"`
+}
+
+// StreamJoined calls fn for every order of the scope (all orders when scope is nil),
+// reading the rows one at a time from the database cursor instead of loading the whole list
+func (r *OrderGormRepo) StreamJoined(ctx context.Context, scope *domain.Scope, fn func(domain.OrderExportRow) error) error {
+	base := r.db.WithContext(ctx)
+	if scope != nil {
+		base = inScope(base, *scope, "o.")
+	}
+
+	rows, err := joinedQuery(base).
+		Select(joinedColumns + ", o.observations, o.delivery_attempts, o.scheduled_delivery_date, o.updated_at, o.pref_window_start, o.pref_window_end, o.pref_leave_with, o.pref_access_instructions, o.pref_recipient_name, o.pref_recipient_phone").
+		Order("o.id asc").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row orderExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+
+		out := domain.OrderExportRow{
+			OrderListItem:       row.Joined.listItem(),
+			Observations:        row.Observations,
+			DeliveryAttempts:    row.DeliveryAttempts,
+			UpdatedAt:           row.UpdatedAt.Format("02/01/2006 15:04"),
+			DeliveryPreferences: row.Preferences,
+		}
+		if row.ScheduledDeliveryDate != nil {
+			out.ScheduledDeliveryDate = row.ScheduledDeliveryDate.Format("02/01/2006")
+		}
+		if err := fn(out); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *OrderGormRepo) FindJoinedByCustomer(ctx context.Context, scope domain.Scope) ([]domain.OrderListItem, error) {
+	base := inScope(r.db.WithContext(ctx), scope, "o.")
 	return r.findJoined(base)
 }
 
-func (r *OrderGormRepo) FindJoinedAll() ([]domain.OrderListItem, error) {
-	base := r.db
+func (r *OrderGormRepo) FindJoinedAll(ctx context.Context) ([]domain.OrderListItem, error) {
+	base := r.db.WithContext(ctx)
 	return r.findJoined(base)
 }
 
-func (r *OrderGormRepo) UpdateStatus(id uint, internalNotes string, status domain.OrderStatus, changedBy uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *OrderGormRepo) FindHistory(ctx context.Context, orderID uint) ([]domain.OrderStatusHistory, error) {
+	var list []domain.OrderStatusHistory
+
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("changed_at asc, id asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// internal struct for scanning delivery stop rows
+type deliveryStopRow struct {
+	Id                    uint
+	OrderNumber           string
+	FullName              string
+	Phone                 string
+	ADStreet              string
+	ADExterior            string
+	ADInterior            string
+	ADNeighborhood        string
+	ADCity                string
+	ADPostal              string
+	Quantity              uint
+	SizeCode              domain.PackageSize
+	Observations          string
+	ScheduledDeliveryDate *time.Time
+	Preferences           domain.DeliveryPreferences `gorm:"embedded;embeddedPrefix:pref_"`
+}
+
+// FindDeliveryStops lists the orders out for delivery, earliest preferred window first
+func (r *OrderGormRepo) FindDeliveryStops(ctx context.Context) ([]domain.DeliveryStop, error) {
+	var rows []deliveryStopRow
+	q := r.db.WithContext(ctx).Table("orders as o").
+		Select("o.id, o.order_number, u.full_name, u.phone, ad.street as ad_street, ad.exterior_number as ad_exterior, ad.interior_number as ad_interior, ad.neighborhood as ad_neighborhood, ad.city as ad_city, ad.postal_code as ad_postal, o.quantity, pt.size_code, o.observations, o.scheduled_delivery_date, o.pref_window_start, o.pref_window_end, o.pref_leave_with, o.pref_access_instructions, o.pref_recipient_name, o.pref_recipient_phone").
+		Joins("inner join users u on o.customer_id = u.id").
+		Joins("inner join addresses ad on o.destination_address_id = ad.id").
+		Joins("inner join package_types pt on o.package_type_id = pt.id").
+		Where("o.status = ?", domain.OrderInRoute).
+		Order("coalesce(nullif(o.pref_window_start, ''), '99:99') asc, o.id asc")
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	stops := make([]domain.DeliveryStop, 0, len(rows))
+	for _, row := range rows {
+		dest := strings.TrimSpace(strings.Join([]string{row.ADStreet, row.ADExterior, row.ADInterior, row.ADNeighborhood, row.ADCity, row.ADPostal}, " "))
+		// fall back to the customer when no distinct recipient was given
+		name, phone := row.Preferences.RecipientName, row.Preferences.RecipientPhone
+		if name == "" {
+			name = row.FullName
+		}
+		if phone == "" {
+			phone = row.Phone
+		}
+		var scheduled *string
+		if row.ScheduledDeliveryDate != nil {
+			d := row.ScheduledDeliveryDate.Format("2006-01-02")
+			scheduled = &d
+		}
+		stops = append(stops, domain.DeliveryStop{
+			OrderID:                row.Id,
+			OrderNumber:            row.OrderNumber,
+			DestinationFullAddress: dest,
+			RecipientName:          name,
+			RecipientPhone:         phone,
+			Preferences:            row.Preferences,
+			Observations:           row.Observations,
+			Quantity:               row.Quantity,
+			SizeCode:               row.SizeCode,
+			ScheduledDeliveryDate:  scheduled,
+		})
+	}
+
+	return stops, nil
+}
+
+// UpdateStatus changes the status when the order is still at version (0 skips the check)
+// and returns the new version
+func (r *OrderGormRepo) UpdateStatus(ctx context.Context, id uint, version uint, internalNotes string, status domain.OrderStatus, changedBy uint) (uint, error) {
+	var newVersion uint
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var o domain.Order
 
 		if err := tx.First(&o, id).Error; err != nil {
@@ -146,9 +307,23 @@ func (r *OrderGormRepo) UpdateStatus(id uint, internalNotes string, status domai
 		}
 		prev := o.Status
 
-		if err := tx.Model(&domain.Order{}).Where("id = ?", id).Updates(map[string]interface{}{"internal_notes": internalNotes, "status": status, "updated_by": changedBy}).Error; err != nil {
-			return err
+		if version != 0 && o.Version != version {
+			return ErrStaleVersion
+		}
+		if prev.Terminal() {
+			return ErrTerminalStatus
+		}
+
+		res := tx.Model(&domain.Order{}).Where("id = ? AND version = ?", id, o.Version).
+			Updates(map[string]interface{}{"internal_notes": internalNotes, "status": status, "updated_by": changedBy, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
 		}
+		if res.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+		newVersion = o.Version + 1
+
 		h := domain.OrderStatusHistory{
 			OrderID:        id,
 			PreviousStatus: prev,
@@ -159,6 +334,30 @@ func (r *OrderGormRepo) UpdateStatus(id uint, internalNotes string, status domai
 		if err := tx.Create(&h).Error; err != nil {
 			return err
 		}
+
+		// Delivering a return closes the original order
+		if status == domain.OrderDelivered && o.ReturnOfOrderID != nil {
+			var orig domain.Order
+			if err := tx.First(&orig, *o.ReturnOfOrderID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&domain.Order{}).Where("id = ?", orig.ID).Updates(map[string]interface{}{"status": domain.OrderReturned, "updated_by": changedBy, "version": gorm.Expr("version + 1")}).Error; err != nil {
+				return err
+			}
+			rh := domain.OrderStatusHistory{
+				OrderID:        orig.ID,
+				PreviousStatus: orig.Status,
+				NewStatus:      domain.OrderReturned,
+				ChangedAt:      h.ChangedAt,
+				ChangedBy:      changedBy,
+				Notes:          "return " + o.OrderNumber + " delivered to sender",
+			}
+			if err := tx.Create(&rh).Error; err != nil {
+				return err
+			}
+		}
 		return nil
 	})
+
+	return newVersion, err
 }
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationGormRepo struct{ db *gorm.DB }
+
+func NewOrganizationGormRepo(database *db.Database) *OrganizationGormRepo {
+	return &OrganizationGormRepo{db: database.DB}
+}
+
+// join adds the user to the organization; ErrAlreadyMember when they already belong to one
+func join(tx *gorm.DB, userID, orgID uint, role domain.OrgRole) error {
+	res := tx.Model(&domain.User{}).Where("id = ? AND organization_id IS NULL", userID).
+		Updates(map[string]interface{}{"organization_id": orgID, "org_role": role})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrAlreadyMember
+	}
+	return nil
+}
+
+// Create stores the organization with its creator as the first owner
+func (r *OrganizationGormRepo) Create(ctx context.Context, o *domain.Organization) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Members").Create(o).Error; err != nil {
+			return err
+		}
+		return join(tx, o.CreatedBy, o.ID, domain.OrgOwner)
+	})
+}
+
+// FindByID returns the organization with its members
+func (r *OrganizationGormRepo) FindByID(ctx context.Context, id uint) (*domain.Organization, error) {
+	var o domain.Organization
+
+	if err := r.db.WithContext(ctx).
+		Preload("Members", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, email, full_name, role, organization_id, org_role, is_active, created_at, updated_at").Order("id asc")
+		}).
+		First(&o, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// CreateInvitation stores the invitation and revokes the pending ones for the same email,
+// so only the latest link works
+func (r *OrganizationGormRepo) CreateInvitation(ctx context.Context, inv *domain.OrganizationInvitation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.OrganizationInvitation{}).
+			Where("organization_id = ? AND lower(email) = lower(?) AND accepted_at IS NULL AND revoked_at IS NULL", inv.OrganizationID, inv.Email).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(inv).Error
+	})
+}
+
+// FindInvitationByHash returns the invitation with that token hash, or nil when there is none
+func (r *OrganizationGormRepo) FindInvitationByHash(ctx context.Context, hash string) (*domain.OrganizationInvitation, error) {
+	var list []domain.OrganizationInvitation
+
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+// AcceptInvitation consumes the invitation and adds the user to its organization with its role.
+// ErrTokenUsed when a concurrent request consumed it first.
+func (r *OrganizationGormRepo) AcceptInvitation(ctx context.Context, inv *domain.OrganizationInvitation, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", inv.ID).
+			Update("accepted_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrTokenUsed
+		}
+
+		return join(tx, userID, inv.OrganizationID, inv.Role)
+	})
+}
+
+// UpdateMemberRole changes the role of a member of the organization
+func (r *OrganizationGormRepo) UpdateMemberRole(ctx context.Context, orgID, userID uint, role domain.OrgRole) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND organization_id = ?", userID, orgID).
+		Update("org_role", role).Error
+}
+
+// RemoveMember takes the user out of the organization; the organization's rows stay with it
+func (r *OrganizationGormRepo) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND organization_id = ?", userID, orgID).
+		Updates(map[string]interface{}{"organization_id": nil, "org_role": ""}).Error
+}
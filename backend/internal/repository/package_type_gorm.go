@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"logistics-app/backend/internal/domain"
 	"logistics-app/backend/internal/infra/db"
 
@@ -13,9 +14,9 @@ func NewPackageTypeGormRepo(database *db.Database) *PackageTypeGormRepo {
 	return &PackageTypeGormRepo{db: database.DB}
 }
 
-func (r *PackageTypeGormRepo) FindAll(includeInactive bool) ([]domain.PackageType, error) {
+func (r *PackageTypeGormRepo) FindAll(ctx context.Context, includeInactive bool) ([]domain.PackageType, error) {
 	var list []domain.PackageType
-	q := r.db.Model(&domain.PackageType{})
+	q := r.db.WithContext(ctx).Model(&domain.PackageType{})
 
 	if !includeInactive {
 		q = q.Where("is_active = ?", true)
@@ -28,8 +29,8 @@ func (r *PackageTypeGormRepo) FindAll(includeInactive bool) ([]domain.PackageTyp
 	return list, nil
 }
 
-func (r *PackageTypeGormRepo) SetActive(id uint, active bool) error {
-	res := r.db.Model(&domain.PackageType{}).Where("id = ?", id).Update("is_active", active)
+func (r *PackageTypeGormRepo) SetActive(ctx context.Context, id uint, active bool) error {
+	res := r.db.WithContext(ctx).Model(&domain.PackageType{}).Where("id = ?", id).Update("is_active", active)
 	if res.Error != nil {
 		return res.Error
 	}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PickupGormRepo struct{ db *gorm.DB }
+
+func NewPickupGormRepo(database *db.Database) *PickupGormRepo {
+	return &PickupGormRepo{db: database.DB}
+}
+
+// FindSlot returns the active slot for the zone and window, falling back to the default (empty zone) slot
+func (r *PickupGormRepo) FindSlot(ctx context.Context, zone, start, end string) (*domain.PickupSlot, error) {
+	var s domain.PickupSlot
+
+	err := r.db.WithContext(ctx).Where("zone IN ? AND window_start = ? AND window_end = ? AND is_active = ?", []string{zone, ""}, start, end, true).
+		Order("zone desc").
+		First(&s).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (r *PickupGormRepo) ListSlots(ctx context.Context) ([]domain.PickupSlot, error) {
+	var list []domain.PickupSlot
+
+	if err := r.db.WithContext(ctx).Order("zone asc, window_start asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// SaveSlot creates the slot or updates capacity and active flag of the existing zone/window
+func (r *PickupGormRepo) SaveSlot(ctx context.Context, s *domain.PickupSlot) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "zone"}, {Name: "window_start"}, {Name: "window_end"}},
+		DoUpdates: clause.AssignmentColumns([]string{"capacity", "is_active"}),
+	}).Create(s).Error
+}
+
+// Save creates or updates a scheduled pickup checking the slot capacity, and groups the given orders in it.
+// The slot row is locked so concurrent bookings of the same slot are serialized.
+func (r *PickupGormRepo) Save(ctx context.Context, p *domain.Pickup, slot *domain.PickupSlot, attach []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked domain.PickupSlot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, slot.ID).Error; err != nil {
+			return err
+		}
+
+		var booked int64
+		if err := tx.Model(&domain.Pickup{}).
+			Where("zone = ? AND pickup_date = ? AND window_start = ? AND window_end = ? AND status = ? AND id <> ?",
+				p.Zone, p.PickupDate, p.WindowStart, p.WindowEnd, domain.PickupScheduled, p.ID).
+			Count(&booked).Error; err != nil {
+			return err
+		}
+
+		if uint(booked) >= locked.Capacity {
+			return errors.New("pickup slot is full")
+		}
+
+		if err := tx.Save(p).Error; err != nil {
+			return err
+		}
+
+		if len(attach) == 0 {
+			return nil
+		}
+
+		owner := domain.Scope{UserID: p.CustomerID, OrganizationID: p.OrganizationID}
+		res := inScope(tx.Model(&domain.Order{}), owner, "").
+			Where("id IN ? AND origin_address_id = ? AND status = ? AND pickup_id IS NULL",
+				attach, p.OriginAddressID, domain.OrderCreated).
+			Updates(map[string]interface{}{"pickup_id": p.ID, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected != int64(len(attach)) {
+			return errors.New("only created orders from the pickup origin address without another pickup can be grouped")
+		}
+
+		return nil
+	})
+}
+
+func (r *PickupGormRepo) loadOrderIDs(ctx context.Context, list []domain.Pickup) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(list))
+	for _, p := range list {
+		ids = append(ids, p.ID)
+	}
+
+	var rows []struct {
+		ID       uint
+		PickupID uint
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.Order{}).Select("id, pickup_id").Where("pickup_id IN ?", ids).Order("id asc").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	byPickup := make(map[uint][]uint)
+	for _, row := range rows {
+		byPickup[row.PickupID] = append(byPickup[row.PickupID], row.ID)
+	}
+
+	for i := range list {
+		list[i].OrderIDs = byPickup[list[i].ID]
+		if list[i].OrderIDs == nil {
+			list[i].OrderIDs = []uint{}
+		}
+	}
+
+	return nil
+}
+
+func (r *PickupGormRepo) FindByID(ctx context.Context, id uint) (*domain.Pickup, error) {
+	var p domain.Pickup
+
+	if err := r.db.WithContext(ctx).First(&p, id).Error; err != nil {
+		return nil, err
+	}
+
+	list := []domain.Pickup{p}
+	if err := r.loadOrderIDs(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return &list[0], nil
+}
+
+// List returns the pickups of the scope (of every customer when scope is nil), optionally only those due on date
+func (r *PickupGormRepo) List(ctx context.Context, scope *domain.Scope, date *time.Time) ([]domain.Pickup, error) {
+	var list []domain.Pickup
+	q := r.db.WithContext(ctx).Model(&domain.Pickup{})
+
+	if scope != nil {
+		q = inScope(q, *scope, "")
+	}
+
+	if date != nil {
+		q = q.Where("pickup_date = ? AND status = ?", *date, domain.PickupScheduled)
+	}
+
+	if err := q.Order("pickup_date asc, window_start asc, zone asc, id asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.loadOrderIDs(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Cancel marks the pickup as cancelled and releases its orders so they can be booked again
+func (r *PickupGormRepo) Cancel(ctx context.Context, id uint, changedBy uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Pickup{}).
+			Where("id = ? AND status = ?", id, domain.PickupScheduled).
+			Updates(map[string]interface{}{"status": domain.PickupCancelled, "updated_by": changedBy})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return errors.New("pickup is not scheduled")
+		}
+
+		return tx.Model(&domain.Order{}).Where("pickup_id = ?", id).
+			Updates(map[string]interface{}{"pickup_id": nil, "version": gorm.Expr("version + 1")}).Error
+	})
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PrivacyGormRepo struct{ db *gorm.DB }
+
+func NewPrivacyGormRepo(database *db.Database) *PrivacyGormRepo {
+	return &PrivacyGormRepo{db: database.DB}
+}
+
+// PersonalData collects the user's profile with the addresses and orders they are the customer of,
+// and the status history of those orders
+func (r *PrivacyGormRepo) PersonalData(ctx context.Context, userID uint) (*domain.PersonalData, error) {
+	tx := r.db.WithContext(ctx)
+	d := &domain.PersonalData{Addresses: []domain.Address{}, Orders: []domain.Order{}, StatusHistory: []domain.OrderStatusHistory{}}
+
+	if err := tx.Select(userColumns).First(&d.Profile, userID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("customer_id = ?", userID).Order("id asc").Find(&d.Addresses).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("customer_id = ?", userID).Order("id asc").Find(&d.Orders).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("order_id IN (?)", tx.Model(&domain.Order{}).Select("id").Where("customer_id = ?", userID)).
+		Order("order_id asc, changed_at asc, id asc").Find(&d.StatusHistory).Error; err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (r *PrivacyGormRepo) CreateErasureRequest(ctx context.Context, e *domain.ErasureRequest) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+func (r *PrivacyGormRepo) FindErasureRequest(ctx context.Context, id uint) (*domain.ErasureRequest, error) {
+	var e domain.ErasureRequest
+
+	if err := r.db.WithContext(ctx).First(&e, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// HasPendingErasure reports whether the user already has a request waiting for review
+func (r *PrivacyGormRepo) HasPendingErasure(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.ErasureRequest{}).
+		Where("user_id = ? AND status = ?", userID, domain.ErasurePending).Count(&count).Error
+	return count > 0, err
+}
+
+// ListErasureRequests returns the requests with the status, or all of them when empty, oldest first
+func (r *PrivacyGormRepo) ListErasureRequests(ctx context.Context, status domain.ErasureStatus) ([]domain.ErasureRequest, error) {
+	var list []domain.ErasureRequest
+	q := r.db.WithContext(ctx)
+
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	if err := q.Order("id asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// review moves a pending request to status; ErrTokenUsed when another reviewer got there first
+func review(tx *gorm.DB, id uint, status domain.ErasureStatus, by uint, note string, now time.Time) error {
+	res := tx.Model(&domain.ErasureRequest{}).
+		Where("id = ? AND status = ?", id, domain.ErasurePending).
+		Updates(map[string]interface{}{"status": status, "reviewed_by": by, "reviewed_at": now, "review_note": note})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenUsed
+	}
+	return nil
+}
+
+func (r *PrivacyGormRepo) RejectErasure(ctx context.Context, id, by uint, note string) error {
+	return review(r.db.WithContext(ctx), id, domain.ErasureRejected, by, note, time.Now())
+}
+
+// Erase carries out an approved request at once: the personal addresses of the user lose the fields
+// that locate them (city, state and postal code stay for reporting) and the user is anonymized and
+// soft deleted. Orders are kept for accounting; organization addresses belong to the organization
+// and are left alone.
+func (r *PrivacyGormRepo) Erase(ctx context.Context, e *domain.ErasureRequest, by uint, note string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := review(tx, e.ID, domain.ErasureCompleted, by, note, now); err != nil {
+			return err
+		}
+
+		return softDeleteUser(tx, e.UserID, by, now)
+	})
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ScanGormRepo struct{ db *gorm.DB }
+
+func NewScanGormRepo(database *db.Database) *ScanGormRepo {
+	return &ScanGormRepo{db: database.DB}
+}
+
+func (r *ScanGormRepo) FindOrderByNumber(ctx context.Context, orderNumber string) (*domain.Order, error) {
+	var o domain.Order
+
+	if err := r.db.WithContext(ctx).Where("order_number = ?", orderNumber).First(&o).Error; err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// LastApplied returns the latest scan that changed the order, or nil when it has never been scanned
+func (r *ScanGormRepo) LastApplied(ctx context.Context, orderID uint) (*domain.Scan, error) {
+	var list []domain.Scan
+
+	if err := r.db.WithContext(ctx).Where("order_id = ? AND result = ?", orderID, domain.ScanApplied).
+		Order("scanned_at desc, id desc").Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+// Apply moves the order to the scan's new status, stores the scan and writes the timeline entry atomically
+func (r *ScanGormRepo) Apply(ctx context.Context, s *domain.Scan, stationCode string) error {
+	if s.OrderID == nil || s.PreviousStatus == nil || s.NewStatus == nil {
+		return errors.New("applied scans need order and statuses")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Order{}).
+			Where("id = ? AND status = ?", *s.OrderID, *s.PreviousStatus).
+			Updates(map[string]interface{}{"status": *s.NewStatus, "updated_by": s.ScannedBy, "version": gorm.Expr("version + 1")})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return errors.New("order status changed while scanning")
+		}
+
+		if s.ScannedAt.IsZero() {
+			s.ScannedAt = time.Now()
+		}
+		if err := tx.Create(s).Error; err != nil {
+			return err
+		}
+
+		h := domain.OrderStatusHistory{
+			OrderID:        *s.OrderID,
+			PreviousStatus: *s.PreviousStatus,
+			NewStatus:      *s.NewStatus,
+			ChangedAt:      s.ScannedAt,
+			ChangedBy:      s.ScannedBy,
+			Notes:          fmt.Sprintf("%s scan at station %s (device %s)", s.ScanType, stationCode, s.DeviceID),
+		}
+		return tx.Create(&h).Error
+	})
+}
+
+// Log stores a scan without touching the order, used for rejected reads
+func (r *ScanGormRepo) Log(ctx context.Context, s *domain.Scan) error {
+	if s.ScannedAt.IsZero() {
+		s.ScannedAt = time.Now()
+	}
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *ScanGormRepo) List(ctx context.Context, orderID, stationID uint, date *time.Time) ([]domain.Scan, error) {
+	var list []domain.Scan
+	q := r.db.WithContext(ctx).Model(&domain.Scan{})
+
+	if orderID != 0 {
+		q = q.Where("order_id = ?", orderID)
+	}
+
+	if stationID != 0 {
+		q = q.Where("station_id = ?", stationID)
+	}
+
+	if date != nil {
+		q = q.Where("scanned_at >= ? AND scanned_at < ?", *date, date.AddDate(0, 0, 1))
+	}
+
+	if err := q.Order("scanned_at asc, id asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
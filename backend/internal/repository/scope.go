@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"logistics-app/backend/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// inScope restricts q to the rows of the scope: the user's own rows outside any organization and,
+// for members, the rows of their organization; API keys only reach the organization's. prefix qualifies the columns in joins, e.g. "o.".
+func inScope(q *gorm.DB, s domain.Scope, prefix string) *gorm.DB {
+	if s.OrganizationOnly {
+		if s.OrganizationID == nil {
+			return q.Where("1 = 0")
+		}
+		return q.Where(prefix+"organization_id = ?", *s.OrganizationID)
+	}
+	own := prefix + "customer_id = ? AND " + prefix + "organization_id IS NULL"
+	if s.OrganizationID == nil {
+		return q.Where(own, s.UserID)
+	}
+	return q.Where("(("+own+") OR "+prefix+"organization_id = ?)", s.UserID, *s.OrganizationID)
+}
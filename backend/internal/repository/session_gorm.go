@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionGormRepo struct{ db *gorm.DB }
+
+func NewSessionGormRepo(database *db.Database) *SessionGormRepo {
+	return &SessionGormRepo{db: database.DB}
+}
+
+func (r *SessionGormRepo) CreateRefresh(ctx context.Context, t *domain.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+// FindRefreshByHash returns the token with that hash, or nil when there is none
+func (r *SessionGormRepo) FindRefreshByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var list []domain.RefreshToken
+
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+// RotateRefresh marks the token as used and stores next in one transaction. It returns false
+// when the token was already used or revoked by a concurrent request.
+func (r *SessionGormRepo) RotateRefresh(ctx context.Context, id uint, next *domain.RefreshToken) (bool, error) {
+	rotated := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+			Updates(map[string]interface{}{"used_at": time.Now(), "replaced_by_id": next.ID})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			// roll back the new token
+			return errRefreshUsed
+		}
+
+		rotated = true
+		return nil
+	})
+
+	if err == errRefreshUsed {
+		return false, nil
+	}
+	return rotated, err
+}
+
+// RevokeFamily revokes every live token of the session
+func (r *SessionGormRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// IsFamilyRevoked reports whether RevokeFamily ran for the family; rotation only marks tokens as used
+func (r *SessionGormRepo) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NOT NULL", familyID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DenyAccessToken adds the JWT id to the deny-list, purging entries of already expired tokens
+func (r *SessionGormRepo) DenyAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", time.Now()).Delete(&domain.RevokedAccessToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.RevokedAccessToken{JTI: jti, ExpiresAt: expiresAt, RevokedAt: time.Now()}).Error
+	})
+}
+
+func (r *SessionGormRepo) IsAccessTokenDenied(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.RevokedAccessToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+
+	"gorm.io/gorm"
+)
+
+type StationGormRepo struct{ db *gorm.DB }
+
+func NewStationGormRepo(database *db.Database) *StationGormRepo {
+	return &StationGormRepo{db: database.DB}
+}
+
+func (r *StationGormRepo) Create(ctx context.Context, s *domain.Station) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *StationGormRepo) FindByID(ctx context.Context, id uint) (*domain.Station, error) {
+	var s domain.Station
+
+	if err := r.db.WithContext(ctx).First(&s, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (r *StationGormRepo) FindAll(ctx context.Context, includeInactive bool) ([]domain.Station, error) {
+	var list []domain.Station
+	q := r.db.WithContext(ctx).Model(&domain.Station{})
+
+	if !includeInactive {
+		q = q.Where("is_active = ?", true)
+	}
+
+	if err := q.Order("code asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (r *StationGormRepo) SetActive(ctx context.Context, id uint, active bool) error {
+	res := r.db.WithContext(ctx).Model(&domain.Station{}).Where("id = ?", id).Update("is_active", active)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -1,10 +1,18 @@
 package repository
 
 import (
+	"context"
 	"logistics-app/backend/internal/domain"
 	"logistics-app/backend/internal/infra/db"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
+// userColumns are the user fields returned by lookups and listings, never the secrets
+const userColumns = "id, email, role, phone, full_name, is_active, created_at, updated_at, updated_by, email_verified_at, mfa_enabled, organization_id, org_role, sessions_revoked_at"
+
 type UserGormRepo struct {
 	db *db.Database
 }
@@ -13,26 +21,26 @@ func NewUserGormRepo(database *db.Database) *UserGormRepo {
 	return &UserGormRepo{db: database}
 }
 
-func (r *UserGormRepo) Create(u *domain.User) error {
-	return r.db.Create(u).Error
+func (r *UserGormRepo) Create(ctx context.Context, u *domain.User) error {
+	return r.db.WithContext(ctx).Create(u).Error
 }
 
-func (r *UserGormRepo) FindByEmail(email string) (*domain.User, error) {
+func (r *UserGormRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var u domain.User
 
-	if err := r.db.Where("email = ?", email).First(&u).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&u).Error; err != nil {
 		return nil, err
 	}
 
 	return &u, nil
 }
 
-func (r *UserGormRepo) FindByID(id uint) (*domain.User, error) {
+func (r *UserGormRepo) FindByID(ctx context.Context, id uint) (*domain.User, error) {
 	var u domain.User
 
 	// Only select allowed fields
-	if err := r.db.Model(&domain.User{}).
-		Select("id, email, role, phone, full_name, is_active, created_at, updated_at").
+	if err := r.db.WithContext(ctx).Model(&domain.User{}).
+		Select(userColumns).
 		First(&u, id).Error; err != nil {
 		return nil, err
 	}
@@ -40,6 +48,149 @@ func (r *UserGormRepo) FindByID(id uint) (*domain.User, error) {
 	return &u, nil
 }
 
-func (r *UserGormRepo) DeleteByID(id uint) error {
-	return r.db.Delete(&domain.User{}, id).Error
+// UserFilter narrows a user listing; zero values don't filter
+type UserFilter struct {
+	// Matched case-insensitively against email and full name
+	Query  string
+	Role   domain.Role
+	Active *bool
+	Offset int
+	Limit  int
+}
+
+// List returns a page of the users matching f, ordered by id, and how many match in total
+func (r *UserGormRepo) List(ctx context.Context, f UserFilter) ([]domain.User, int64, error) {
+	q := r.db.WithContext(ctx).Model(&domain.User{})
+
+	if term := strings.TrimSpace(f.Query); term != "" {
+		like := "%" + strings.ToLower(term) + "%"
+		q = q.Where("lower(email) LIKE ? OR lower(full_name) LIKE ?", like, like)
+	}
+	if f.Role != "" {
+		q = q.Where("role = ?", f.Role)
+	}
+	if f.Active != nil {
+		q = q.Where("is_active = ?", *f.Active)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []domain.User
+	if err := q.Select(userColumns).Order("id asc").Offset(f.Offset).Limit(f.Limit).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return list, total, nil
+}
+
+// CountActiveByRole counts the active users with the role
+func (r *UserGormRepo) CountActiveByRole(ctx context.Context, role domain.Role) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("role = ? AND is_active = ?", role, true).Count(&count).Error
+	return count, err
+}
+
+// Update sets the given columns of the user
+func (r *UserGormRepo) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// revokeSessions revokes every live refresh token of the user except those of keepFamily, if set
+func revokeSessions(tx *gorm.DB, userID uint, keepFamily string, now time.Time) error {
+	q := tx.Model(&domain.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if keepFamily != "" {
+		q = q.Where("family_id <> ?", keepFamily)
+	}
+	return q.Update("revoked_at", now).Error
+}
+
+// UpdatePassword stores the new password hash and revokes the user's other sessions
+func (r *UserGormRepo) UpdatePassword(ctx context.Context, id uint, passwordHash, keepFamily string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id = ?", id).Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+		return revokeSessions(tx, id, keepFamily, time.Now())
+	})
+}
+
+// RevokeSessions ends every session of the user: refresh tokens are revoked and access tokens
+// issued until now are rejected
+func (r *UserGormRepo) RevokeSessions(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&domain.User{}).Where("id = ?", id).Update("sessions_revoked_at", now).Error; err != nil {
+			return err
+		}
+		return revokeSessions(tx, id, "", now)
+	})
+}
+
+// CountOrganizationMembers counts the members of the organization and how many of them are owners
+func (r *UserGormRepo) CountOrganizationMembers(ctx context.Context, orgID uint) (members, owners int64, err error) {
+	q := r.db.WithContext(ctx).Model(&domain.User{}).Where("organization_id = ?", orgID)
+	if err = q.Count(&members).Error; err != nil {
+		return 0, 0, err
+	}
+	err = r.db.WithContext(ctx).Model(&domain.User{}).Where("organization_id = ? AND org_role = ?", orgID, domain.OrgOwner).Count(&owners).Error
+	return members, owners, err
+}
+
+// SoftDelete anonymizes the user and their personal addresses and marks the user deleted. The rows
+// stay, so their orders and history keep pointing to them; the credentials, sessions and API keys
+// they issued go away.
+func (r *UserGormRepo) SoftDelete(ctx context.Context, id, by uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return softDeleteUser(tx, id, by, time.Now())
+	})
+}
+
+// softDeleteUser blanks the user's personal addresses (organization addresses belong to the
+// organization) and anonymizes the user row
+func softDeleteUser(tx *gorm.DB, id, by uint, now time.Time) error {
+	if err := tx.Model(&domain.Address{}).
+		Where("customer_id = ? AND organization_id IS NULL", id).
+		Updates(map[string]interface{}{
+			"street":          "",
+			"exterior_number": "",
+			"interior_number": "",
+			"neighborhood":    "",
+			"coordinate_id":   nil,
+			"is_active":       false,
+			"version":         gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&domain.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":               domain.DeletedUserEmail(id),
+		"full_name":           domain.DeletedUserName,
+		"phone":               "",
+		"password":            "",
+		"mfa_enabled":         false,
+		"mfa_secret":          "",
+		"default_address_id":  nil,
+		"organization_id":     nil,
+		"org_role":            "",
+		"is_active":           false,
+		"sessions_revoked_at": now,
+		"updated_by":          by,
+		"deleted_at":          now,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("user_id = ?", id).Delete(&domain.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&domain.UserToken{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&domain.APIKey{}).Where("created_by = ? AND revoked_at IS NULL", id).Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return revokeSessions(tx, id, "", now)
 }
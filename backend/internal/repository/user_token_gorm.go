@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/db"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserTokenGormRepo struct{ db *gorm.DB }
+
+func NewUserTokenGormRepo(database *db.Database) *UserTokenGormRepo {
+	return &UserTokenGormRepo{db: database.DB}
+}
+
+// Create stores the token and drops the user's earlier unused tokens for the same purpose,
+// so only the latest link works
+func (r *UserTokenGormRepo) Create(ctx context.Context, t *domain.UserToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", t.UserID, t.Purpose).
+			Delete(&domain.UserToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(t).Error
+	})
+}
+
+// FindByHash returns the token with that hash, or nil when there is none
+func (r *UserTokenGormRepo) FindByHash(ctx context.Context, hash string) (*domain.UserToken, error) {
+	var list []domain.UserToken
+
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).Limit(1).Find(&list).Error; err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return &list[0], nil
+}
+
+// use marks the token as used; ErrTokenUsed when a concurrent request used it first
+func use(tx *gorm.DB, tokenID uint) error {
+	res := tx.Model(&domain.UserToken{}).Where("id = ? AND used_at IS NULL", tokenID).Update("used_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTokenUsed
+	}
+	return nil
+}
+
+// ResetPassword consumes the token, stores the new password hash and revokes the user's sessions
+func (r *UserTokenGormRepo) ResetPassword(ctx context.Context, tokenID, userID uint, passwordHash string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := use(tx, tokenID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.User{}).Where("id = ?", userID).Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+
+		return revokeSessions(tx, userID, "", time.Now())
+	})
+}
+
+// VerifyEmail consumes the token and marks the user's email as verified
+func (r *UserTokenGormRepo) VerifyEmail(ctx context.Context, tokenID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := use(tx, tokenID); err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.User{}).Where("id = ? AND email_verified_at IS NULL", userID).
+			Update("email_verified_at", time.Now()).Error
+	})
+}
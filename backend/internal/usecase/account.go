@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Mailer delivers plain-text emails
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type UserTokenRepo interface {
+	Create(ctx context.Context, t *domain.UserToken) error
+	FindByHash(ctx context.Context, hash string) (*domain.UserToken, error)
+	ResetPassword(ctx context.Context, tokenID, userID uint, passwordHash string) error
+	VerifyEmail(ctx context.Context, tokenID, userID uint) error
+}
+
+const (
+	DefaultPasswordResetTTL     = time.Hour
+	DefaultEmailVerificationTTL = 48 * time.Hour
+)
+
+type AccountConfig struct {
+	// Base URL of the frontend, links are built as <BaseURL>/reset-password?token=...
+	BaseURL         string
+	ResetTTL        time.Duration
+	VerificationTTL time.Duration
+	// Block login until the email is verified
+	RequireVerifiedEmail bool
+}
+
+type AccountService struct {
+	users  UserRepo
+	tokens UserTokenRepo
+	mailer Mailer
+	cfg    AccountConfig
+}
+
+func NewAccountService(users UserRepo, tokens UserTokenRepo, mailer Mailer, cfg AccountConfig) *AccountService {
+	if cfg.ResetTTL <= 0 {
+		cfg.ResetTTL = DefaultPasswordResetTTL
+	}
+	if cfg.VerificationTTL <= 0 {
+		cfg.VerificationTTL = DefaultEmailVerificationTTL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &AccountService{users: users, tokens: tokens, mailer: mailer, cfg: cfg}
+}
+
+func (s *AccountService) issue(ctx context.Context, userID uint, purpose domain.UserTokenPurpose, ttl time.Duration) (string, error) {
+	raw, err := NewTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	t := &domain.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokens.Create(ctx, t); err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// consume returns the live token of the given purpose for raw
+func (s *AccountService) consume(ctx context.Context, raw string, purpose domain.UserTokenPurpose) (*domain.UserToken, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("token requerido")
+	}
+
+	t, err := s.tokens.FindByHash(ctx, hashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	if t == nil || t.Purpose != purpose || t.UsedAt != nil || !time.Now().Before(t.ExpiresAt) {
+		return nil, fmt.Errorf("%w: el enlace es inválido, ya fue usado o expiró", ErrUnprocessable)
+	}
+
+	return t, nil
+}
+
+// used maps a token consumed concurrently to the same error as an already used one
+func used(err error) error {
+	if errors.Is(err, repository.ErrTokenUsed) {
+		return fmt.Errorf("%w: el enlace es inválido, ya fue usado o expiró", ErrUnprocessable)
+	}
+	return err
+}
+
+// RequestPasswordReset emails a reset link when the email belongs to an active user. Unknown
+// emails are ignored silently so the endpoint does not reveal which accounts exist.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return errors.New("email requerido")
+	}
+
+	u, err := s.users.FindByEmail(ctx, email)
+	if err != nil || u == nil || !u.IsActive {
+		return nil
+	}
+
+	raw, err := s.issue(ctx, u.ID, domain.TokenPasswordReset, s.cfg.ResetTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hola %s,\n\nPara definir una nueva contraseña abre el siguiente enlace (válido por %s):\n\n%s/reset-password?token=%s\n\nSi no solicitaste el cambio, ignora este mensaje.\n",
+		u.FullName, s.cfg.ResetTTL, s.cfg.BaseURL, raw)
+	return s.mailer.Send(u.Email, "Restablecer contraseña", body)
+}
+
+// ResetPassword sets a new password with a reset token; the user's sessions are revoked
+func (s *AccountService) ResetPassword(ctx context.Context, raw, password string) error {
+	if password == "" {
+		return errors.New("password requerido")
+	}
+
+	t, err := s.consume(ctx, raw, domain.TokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	u, err := s.users.FindByID(ctx, t.UserID)
+	if err != nil || u == nil {
+		return ErrNotFound
+	}
+	// Checked before the token is used, so the link still works with a valid password
+	if err := validatePassword(u.Role, password); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("no se pudo encriptar el password")
+	}
+
+	return used(s.tokens.ResetPassword(ctx, t.ID, t.UserID, string(hash)))
+}
+
+// SendVerification emails an email verification link, unless the email is already verified
+func (s *AccountService) SendVerification(ctx context.Context, u *domain.User) error {
+	if u.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	raw, err := s.issue(ctx, u.ID, domain.TokenEmailVerification, s.cfg.VerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hola %s,\n\nConfirma tu correo abriendo el siguiente enlace (válido por %s):\n\n%s/verify-email?token=%s\n",
+		u.FullName, s.cfg.VerificationTTL, s.cfg.BaseURL, raw)
+	return s.mailer.Send(u.Email, "Confirma tu correo", body)
+}
+
+// ResendVerification sends a new verification link to the user
+func (s *AccountService) ResendVerification(ctx context.Context, userID uint) error {
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil || u == nil {
+		return ErrNotFound
+	}
+
+	if u.EmailVerifiedAt != nil {
+		return fmt.Errorf("%w: el correo ya está verificado", ErrConflict)
+	}
+
+	return s.SendVerification(ctx, u)
+}
+
+func (s *AccountService) VerifyEmail(ctx context.Context, raw string) error {
+	t, err := s.consume(ctx, raw, domain.TokenEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	return used(s.tokens.VerifyEmail(ctx, t.ID, t.UserID))
+}
+
+// CanLogin rejects users with an unverified email when verification is required
+func (s *AccountService) CanLogin(u *domain.User) error {
+	if s.cfg.RequireVerifiedEmail && u.EmailVerifiedAt == nil {
+		return fmt.Errorf("%w: debe verificar su correo antes de iniciar sesión", ErrForbidden)
+	}
+	return nil
+}
@@ -1,18 +1,20 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"logistics-app/backend/internal/domain"
 	"logistics-app/backend/internal/repository"
 )
 
 type AddressRepo interface {
-	CreateWithCoordinates(customerID uint, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error)
-	UpdateWithCoordinates(requesterID uint, isAdmin bool, id uint, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error)
-	FindByID(requesterID uint, isAdmin bool, id uint) (*domain.Address, error)
-	List(requesterID uint, isAdmin bool, includeInactive bool) ([]domain.Address, error)
-	ToggleActive(requesterID uint, isAdmin bool, id uint, active bool) error
-	Delete(requesterID uint, isAdmin bool, id uint) error
+	CreateWithCoordinates(ctx context.Context, owner domain.Scope, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error)
+	UpdateWithCoordinates(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error)
+	FindByID(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) (*domain.Address, error)
+	List(ctx context.Context, scope domain.Scope, isAdmin bool, includeInactive bool) ([]domain.Address, error)
+	ToggleActive(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, active bool) error
+	Delete(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) error
+	FindMatch(ctx context.Context, scope domain.Scope, a domain.Address) (*domain.Address, error)
 }
 
 type AddressService struct{ repo AddressRepo }
@@ -57,8 +59,10 @@ func (s *AddressService) toRepoPayload(req AddressRequest) repository.AddressWit
 	return repository.AddressWithCoords{Address: addr, Coordinates: coords}
 }
 
-func (s *AddressService) Create(customerID uint, req AddressRequest) (*domain.Address, *domain.Coordinates, error) {
-	if customerID == 0 {
+// Create adds an address owned by the scope: the organization for its owners and shippers, otherwise the user
+func (s *AddressService) Create(ctx context.Context, owner domain.Scope, req AddressRequest) (*domain.Address, *domain.Coordinates, error) {
+	owner = owner.Writable()
+	if owner.UserID == 0 {
 		return nil, nil, errors.New("customerID requerido")
 	}
 
@@ -76,55 +80,92 @@ func (s *AddressService) Create(customerID uint, req AddressRequest) (*domain.Ad
 	}
 
 	payload := s.toRepoPayload(req)
-	addr, coords, err := s.repo.CreateWithCoordinates(customerID, payload)
+	addr, coords, err := s.repo.CreateWithCoordinates(ctx, owner, payload)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// allow overriding is_active on create if provided
 	if req.IsActive != nil {
-		_ = s.repo.ToggleActive(customerID, false, addr.ID, *req.IsActive)
+		_ = s.repo.ToggleActive(ctx, owner, false, addr.ID, addr.Version, *req.IsActive)
 		addr.IsActive = *req.IsActive
+		addr.Version++
 	}
 
 	return addr, coords, nil
 }
 
-func (s *AddressService) Update(requesterID uint, isAdmin bool, id uint, req AddressRequest) (*domain.Address, *domain.Coordinates, error) {
+// Update overwrites the address if it is still at version (0 skips the check)
+func (s *AddressService) Update(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, req AddressRequest) (*domain.Address, *domain.Coordinates, error) {
 	if id == 0 {
 		return nil, nil, errors.New("id requerido")
 	}
 
 	payload := s.toRepoPayload(req)
-	addr, coords, err := s.repo.UpdateWithCoordinates(requesterID, isAdmin, id, payload)
+	addr, coords, err := s.repo.UpdateWithCoordinates(ctx, scope.Writable(), isAdmin, id, version, payload)
 
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, staleVersion(err, "la dirección")
 	}
 
 	if req.IsActive != nil {
-		if err := s.repo.ToggleActive(requesterID, isAdmin, id, *req.IsActive); err != nil {
-			return nil, nil, err
+		if err := s.repo.ToggleActive(ctx, scope.Writable(), isAdmin, id, addr.Version, *req.IsActive); err != nil {
+			return nil, nil, staleVersion(err, "la dirección")
 		}
 		addr.IsActive = *req.IsActive
+		addr.Version++
 	}
 
 	return addr, coords, nil
 }
 
-func (s *AddressService) Get(requesterID uint, isAdmin bool, id uint) (*domain.Address, error) {
-	return s.repo.FindByID(requesterID, isAdmin, id)
+// FindOrCreate reuses a matching active address of the scope, or creates it. created reports which one happened.
+func (s *AddressService) FindOrCreate(ctx context.Context, owner domain.Scope, req AddressRequest) (addr *domain.Address, created bool, err error) {
+	existing, err := s.repo.FindMatch(ctx, owner.Writable(), s.toRepoPayload(req).Address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing != nil {
+		return existing, false, nil
+	}
+
+	addr, _, err = s.Create(ctx, owner, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return addr, true, nil
+}
+
+func (s *AddressService) Get(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) (*domain.Address, error) {
+	return s.repo.FindByID(ctx, scope, isAdmin, id)
 }
 
-func (s *AddressService) List(requesterID uint, role domain.Role, includeInactive bool, all bool) ([]domain.Address, error) {
-	isAdmin := role == domain.RoleAdmin
-	return s.repo.List(requesterID, isAdmin && all, includeInactive && isAdmin)
+func (s *AddressService) List(ctx context.Context, scope domain.Scope, canManage bool, includeInactive bool, all bool) ([]domain.Address, error) {
+	return s.repo.List(ctx, scope, canManage && all, includeInactive && canManage)
 }
 
-func (s *AddressService) ToggleActive(requesterID uint, isAdmin bool, id uint, active bool) error {
-	return s.repo.ToggleActive(requesterID, isAdmin, id, active)
+// ToggleActive sets is_active if the address is still at version (0 skips the check) and returns the new version
+func (s *AddressService) ToggleActive(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, active bool) (uint, error) {
+	scope = scope.Writable()
+	a, err := s.repo.FindByID(ctx, scope, isAdmin, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if version != 0 && a.Version != version {
+		return 0, preconditionFailed("la dirección")
+	}
+
+	current := a.Version
+	if err := s.repo.ToggleActive(ctx, scope, isAdmin, id, current, active); err != nil {
+		return 0, staleVersion(err, "la dirección")
+	}
+
+	return current + 1, nil
 }
 
-func (s *AddressService) Delete(requesterID uint, isAdmin bool, id uint) error {
-	return s.repo.Delete(requesterID, isAdmin, id)
+func (s *AddressService) Delete(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) error {
+	return s.repo.Delete(ctx, scope.Writable(), isAdmin, id)
 }
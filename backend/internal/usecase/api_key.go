@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"strings"
+	"time"
+)
+
+type APIKeyRepo interface {
+	Create(ctx context.Context, k *domain.APIKey) error
+	ListByOrganization(ctx context.Context, orgID uint) ([]domain.APIKey, error)
+	FindByID(ctx context.Context, id uint) (*domain.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	Rotate(ctx context.Context, old *domain.APIKey, oldUntil *time.Time, next *domain.APIKey) error
+	Revoke(ctx context.Context, id uint) error
+	Touch(ctx context.Context, id uint, now time.Time) error
+}
+
+const (
+	// Requests per minute of a key without its own limit
+	DefaultAPIKeyRateLimit = 120
+	// Longest time a rotated key keeps working next to its replacement
+	MaxAPIKeyRotationGrace = 7 * 24 * time.Hour
+)
+
+type APIKeyService struct {
+	repo        APIKeyRepo
+	limiter     *RateLimiter
+	defaultRate uint
+}
+
+func NewAPIKeyService(repo APIKeyRepo, defaultRate uint) *APIKeyService {
+	if defaultRate == 0 {
+		defaultRate = DefaultAPIKeyRateLimit
+	}
+	return &APIKeyService{repo: repo, limiter: NewRateLimiter(time.Minute), defaultRate: defaultRate}
+}
+
+// APIKeyRequest describes a key to create; Permissions must be a subset of domain.APIKeyPermissions
+type APIKeyRequest struct {
+	Name        string              `json:"name"`
+	Permissions []domain.Permission `json:"permissions"`
+	RateLimit   uint                `json:"rate_limit"`
+}
+
+// ownerOf returns the organization the scope administers; keys are managed by owners signed in as themselves
+func ownerOf(scope domain.Scope) (uint, error) {
+	if scope.OrganizationID == nil {
+		return 0, fmt.Errorf("%w: las claves de API pertenecen a una organización", ErrForbidden)
+	}
+	if scope.OrganizationOnly || scope.OrgRole != domain.OrgOwner {
+		return 0, fmt.Errorf("%w: solo un owner puede administrar las claves de API", ErrForbidden)
+	}
+	return *scope.OrganizationID, nil
+}
+
+func validKeyPermissions(perms []domain.Permission) ([]domain.Permission, error) {
+	allowed := domain.APIKeyPermissions()
+	out := make([]domain.Permission, 0, len(perms))
+	for _, p := range perms {
+		ok := false
+		for _, a := range allowed {
+			ok = ok || a == p
+		}
+		if !ok {
+			return nil, fmt.Errorf("permiso no permitido para claves de API: %q", p)
+		}
+		dup := false
+		for _, o := range out {
+			dup = dup || o == p
+		}
+		if !dup {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// newKey fills k with a fresh secret and returns the raw key, which is not stored
+func newKey(k *domain.APIKey) (string, error) {
+	secret, err := NewTokenID()
+	if err != nil {
+		return "", err
+	}
+	raw := domain.APIKeyPrefix + secret
+	k.KeyHash = hashToken(raw)
+	k.Prefix = raw[:len(domain.APIKeyPrefix)+8]
+	return raw, nil
+}
+
+// Create issues a key for the owner's organization. The raw key is returned only here.
+func (s *APIKeyService) Create(ctx context.Context, scope domain.Scope, req APIKeyRequest) (*domain.APIKey, string, error) {
+	orgID, err := ownerOf(scope)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", errors.New("name es requerido")
+	}
+
+	perms, err := validKeyPermissions(req.Permissions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	k := &domain.APIKey{
+		OrganizationID: orgID,
+		Name:           name,
+		Permissions:    perms,
+		RateLimit:      req.RateLimit,
+		CreatedBy:      scope.UserID,
+	}
+	raw, err := newKey(k)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.Create(ctx, k); err != nil {
+		return nil, "", err
+	}
+
+	return k, raw, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, scope domain.Scope) ([]domain.APIKey, error) {
+	orgID, err := ownerOf(scope)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByOrganization(ctx, orgID)
+}
+
+func (s *APIKeyService) owned(ctx context.Context, scope domain.Scope, id uint) (*domain.APIKey, error) {
+	orgID, err := ownerOf(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := s.repo.FindByID(ctx, id)
+	if err != nil || k.OrganizationID != orgID {
+		return nil, ErrNotFound
+	}
+
+	return k, nil
+}
+
+// Rotate replaces a key with a new secret keeping its name, permissions and limit. The old key is
+// revoked at once, or keeps working for grace (at most MaxAPIKeyRotationGrace) while clients switch.
+func (s *APIKeyService) Rotate(ctx context.Context, scope domain.Scope, id uint, grace time.Duration) (*domain.APIKey, string, error) {
+	if grace < 0 || grace > MaxAPIKeyRotationGrace {
+		return nil, "", fmt.Errorf("grace_period debe estar entre 0 y %s", MaxAPIKeyRotationGrace)
+	}
+
+	old, err := s.owned(ctx, scope, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	if !old.Active(now) {
+		return nil, "", fmt.Errorf("%w: la clave está revocada o expirada", ErrConflict)
+	}
+
+	next := &domain.APIKey{
+		OrganizationID: old.OrganizationID,
+		Name:           old.Name,
+		Permissions:    old.Permissions,
+		RateLimit:      old.RateLimit,
+		CreatedBy:      scope.UserID,
+		RotatedFromID:  &old.ID,
+	}
+	raw, err := newKey(next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var until *time.Time
+	if grace > 0 {
+		t := now.Add(grace)
+		until = &t
+	}
+	if err := s.repo.Rotate(ctx, old, until, next); err != nil {
+		if errors.Is(err, repository.ErrTokenUsed) {
+			return nil, "", fmt.Errorf("%w: la clave ya fue rotada o revocada", ErrConflict)
+		}
+		return nil, "", err
+	}
+
+	return next, raw, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, scope domain.Scope, id uint) error {
+	k, err := s.owned(ctx, scope, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Revoke(ctx, k.ID)
+}
+
+// Authenticate returns the active key for raw and counts the request against its rate limit,
+// failing with a *RateLimitedError once the limit is reached
+func (s *APIKeyService) Authenticate(ctx context.Context, raw string) (*domain.APIKey, error) {
+	if !strings.HasPrefix(raw, domain.APIKeyPrefix) {
+		return nil, ErrUnauthorized
+	}
+
+	k, err := s.repo.FindByHash(ctx, hashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if k == nil || !k.Active(now) {
+		return nil, ErrUnauthorized
+	}
+
+	limit := k.RateLimit
+	if limit == 0 {
+		limit = s.defaultRate
+	}
+	if err := s.limiter.Allow(fmt.Sprintf("key:%d", k.ID), limit, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Touch(ctx, k.ID, now); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"time"
+)
+
+type DeliveryAttemptRepo interface {
+	RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt, next domain.OrderStatus) error
+	ListByOrder(ctx context.Context, orderID uint) ([]domain.DeliveryAttempt, error)
+	Reschedule(ctx context.Context, orderID uint, version uint, date time.Time, changedBy uint) error
+}
+
+// DefaultMaxDeliveryAttempts is used when no explicit limit is configured
+const DefaultMaxDeliveryAttempts uint = 3
+
+type DeliveryService struct {
+	repo        DeliveryAttemptRepo
+	orders      OrderRepo
+	maxAttempts uint
+}
+
+func NewDeliveryService(r DeliveryAttemptRepo, orders OrderRepo, maxAttempts uint) *DeliveryService {
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxDeliveryAttempts
+	}
+	return &DeliveryService{repo: r, orders: orders, maxAttempts: maxAttempts}
+}
+
+func (s *DeliveryService) MaxAttempts() uint {
+	return s.maxAttempts
+}
+
+func validFailureReason(reason domain.DeliveryFailureReason) bool {
+	switch reason {
+	case domain.FailureRecipientAbsent, domain.FailureWrongAddress, domain.FailureRefused:
+		return true
+	}
+	return false
+}
+
+// RecordFailedAttempt registers a failed delivery for an order in route. Once the configured
+// maximum is reached the order moves to return_to_sender instead of delivery_failed.
+func (s *DeliveryService) RecordFailedAttempt(ctx context.Context, orderID uint, reason domain.DeliveryFailureReason, notes string, recordedBy uint) (*domain.DeliveryAttempt, error) {
+	if recordedBy == 0 {
+		return nil, errors.New("recordedBy requerido")
+	}
+
+	if !validFailureReason(reason) {
+		return nil, fmt.Errorf("motivo de intento fallido inválido: %q", reason)
+	}
+
+	o, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if o.Status != domain.OrderInRoute {
+		return nil, errors.New("solo se pueden registrar intentos de entrega para órdenes en ruta")
+	}
+
+	a := &domain.DeliveryAttempt{
+		OrderID:       o.ID,
+		AttemptNumber: o.DeliveryAttempts + 1,
+		Reason:        reason,
+		Notes:         notes,
+		AttemptedAt:   time.Now(),
+		RecordedBy:    recordedBy,
+	}
+
+	next := domain.OrderDeliveryFailed
+	if a.AttemptNumber >= s.maxAttempts {
+		next = domain.OrderReturnToSender
+	}
+
+	if err := s.repo.RecordAttempt(ctx, a, next); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (s *DeliveryService) ListAttempts(ctx context.Context, scope domain.Scope, isAdmin bool, orderID uint) ([]domain.DeliveryAttempt, error) {
+	o, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Sees(o.CustomerID, o.OrganizationID) {
+		return nil, ErrForbidden
+	}
+
+	return s.repo.ListByOrder(ctx, orderID)
+}
+
+// Reschedule lets the owner or an admin pick a new delivery date after a failed attempt.
+// The order must still be at version (0 skips the check); the new version is returned.
+func (s *DeliveryService) Reschedule(ctx context.Context, scope domain.Scope, isAdmin bool, orderID uint, version uint, date time.Time) (uint, error) {
+	o, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return 0, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Writable().Sees(o.CustomerID, o.OrganizationID) {
+		return 0, ErrForbidden
+	}
+
+	if version != 0 && o.Version != version {
+		return 0, preconditionFailed("la orden")
+	}
+
+	if o.Status != domain.OrderDeliveryFailed {
+		return 0, errors.New("solo se puede reprogramar la entrega de órdenes con intento fallido")
+	}
+
+	y, m, d := time.Now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if !day.After(today) {
+		return 0, errors.New("la nueva fecha de entrega debe ser posterior a hoy")
+	}
+
+	current := o.Version
+	if err := s.repo.Reschedule(ctx, orderID, current, day, scope.UserID); err != nil {
+		return 0, staleVersion(err, "la orden")
+	}
+
+	return current + 1, nil
+}
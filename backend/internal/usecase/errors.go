@@ -0,0 +1,29 @@
+package usecase
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when the requested resource does not exist or is not visible to the requester
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is returned when credentials or tokens are missing, invalid or revoked
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is returned when the requester is authenticated but not allowed to act on the resource
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict is returned when the request does not fit the current state of the resource
+	ErrConflict = errors.New("conflict")
+	// ErrUnprocessable is returned when the request is well formed but cannot be applied as sent
+	ErrUnprocessable = errors.New("unprocessable")
+	// ErrPreconditionFailed is returned when the resource changed since the version the client read
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrInvalid is returned when the request fails validation, so retrying it unchanged fails again
+	ErrInvalid = errors.New("invalid")
+)
+
+// invalidError marks err as ErrInvalid without changing its message
+type invalidError struct{ err error }
+
+func (e invalidError) Error() string { return e.err.Error() }
+
+func (e invalidError) Unwrap() []error { return []error{ErrInvalid, e.err} }
+
+func invalid(err error) error { return invalidError{err} }
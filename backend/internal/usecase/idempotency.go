@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"strings"
+	"time"
+)
+
+type IdempotencyRepo interface {
+	Find(ctx context.Context, userID uint, endpoint, key string) (*domain.IdempotencyKey, error)
+	Reserve(ctx context.Context, k *domain.IdempotencyKey) (bool, error)
+	Complete(ctx context.Context, id uint, statusCode int, body []byte) error
+	Release(ctx context.Context, id uint) error
+}
+
+// DefaultIdempotencyTTL is how long a stored response is replayed
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+type IdempotencyService struct {
+	repo IdempotencyRepo
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(r IdempotencyRepo, ttl time.Duration) *IdempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &IdempotencyService{repo: r, ttl: ttl}
+}
+
+// requestHash fingerprints the body; JSON is re-encoded so formatting and key order don't matter
+func requestHash(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if canonical, err := json.Marshal(v); err == nil {
+			body = canonical
+		}
+	}
+	sum := sha256.Sum256(bytes.TrimSpace(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// Begin claims the key for the user and endpoint. When a response is already stored for the same
+// body it is returned with replay=true. A different body under the key fails with ErrUnprocessable,
+// and a retry while the first request is still running with ErrConflict.
+func (s *IdempotencyService) Begin(ctx context.Context, userID uint, endpoint, key string, body []byte) (rec *domain.IdempotencyKey, replay bool, err error) {
+	key = strings.TrimSpace(key)
+	if key == "" || len(key) > 255 {
+		return nil, false, errors.New("Idempotency-Key debe tener entre 1 y 255 caracteres")
+	}
+
+	hash := requestHash(body)
+
+	existing, err := s.repo.Find(ctx, userID, endpoint, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing == nil {
+		now := time.Now()
+		rec = &domain.IdempotencyKey{
+			UserID:      userID,
+			Endpoint:    endpoint,
+			Key:         key,
+			RequestHash: hash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.ttl),
+		}
+		ok, err := s.repo.Reserve(ctx, rec)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			return rec, false, nil
+		}
+		// lost the race against a concurrent request with the same key
+		if existing, err = s.repo.Find(ctx, userID, endpoint, key); err != nil || existing == nil {
+			return nil, false, fmt.Errorf("%w: la solicitud con esta Idempotency-Key está en proceso", ErrConflict)
+		}
+	}
+
+	if existing.RequestHash != hash {
+		return nil, false, fmt.Errorf("%w: la Idempotency-Key ya se usó con un cuerpo diferente", ErrUnprocessable)
+	}
+
+	if existing.StatusCode == 0 {
+		return nil, false, fmt.Errorf("%w: la solicitud con esta Idempotency-Key está en proceso", ErrConflict)
+	}
+
+	return existing, true, nil
+}
+
+// Complete stores the response so retries replay it. Server errors are not stored:
+// the key is released and the client may retry.
+func (s *IdempotencyService) Complete(ctx context.Context, rec *domain.IdempotencyKey, statusCode int, body []byte) error {
+	if statusCode >= 500 {
+		return s.repo.Release(ctx, rec.ID)
+	}
+
+	rec.StatusCode = statusCode
+	rec.ResponseBody = body
+	return s.repo.Complete(ctx, rec.ID, statusCode, body)
+}
+
+// Release frees the key without storing a response
+func (s *IdempotencyService) Release(ctx context.Context, rec *domain.IdempotencyKey) error {
+	return s.repo.Release(ctx, rec.ID)
+}
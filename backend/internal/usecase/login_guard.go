@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"strings"
+	"time"
+)
+
+type LoginThrottleRepo interface {
+	Find(ctx context.Context, key string) (*domain.LoginThrottle, error)
+	RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginThrottle, error)
+	Lock(ctx context.Context, key string, threshold uint, until time.Time) (bool, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type AuditRepo interface {
+	Create(ctx context.Context, a *domain.AuditLog) error
+}
+
+// ErrTooManyAttempts is returned while an account or client is locked out
+var ErrTooManyAttempts = errors.New("demasiados intentos fallidos, intente más tarde")
+
+// LockedError carries how long the caller must wait before trying again
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string { return ErrTooManyAttempts.Error() }
+
+func (e *LockedError) Unwrap() error { return ErrTooManyAttempts }
+
+type LoginGuardConfig struct {
+	// Failed attempts within Window that lock an account / a client IP
+	MaxAccountFailures uint
+	MaxIPFailures      uint
+	Window             time.Duration
+	// First lockout lasts BaseLockout and each following one twice as long, up to MaxLockout
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+var DefaultLoginGuardConfig = LoginGuardConfig{
+	MaxAccountFailures: 5,
+	MaxIPFailures:      20,
+	Window:             15 * time.Minute,
+	BaseLockout:        time.Minute,
+	MaxLockout:         time.Hour,
+}
+
+type LoginGuardService struct {
+	repo  LoginThrottleRepo
+	audit AuditRepo
+	cfg   LoginGuardConfig
+}
+
+func NewLoginGuardService(r LoginThrottleRepo, audit AuditRepo, cfg LoginGuardConfig) *LoginGuardService {
+	return &LoginGuardService{repo: r, audit: audit, cfg: cfg}
+}
+
+// Keys are derived from the submitted email whether or not the account exists, so lockouts
+// don't reveal which emails are registered
+func accountKey(email string) string { return "email:" + strings.ToLower(strings.TrimSpace(email)) }
+
+func ipKey(ip string) string { return "ip:" + ip }
+
+// Check fails with a *LockedError while the account or the client IP is locked out
+func (s *LoginGuardService) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	var wait time.Duration
+
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		t, err := s.repo.Find(ctx, key)
+		if err != nil {
+			return err
+		}
+		if t != nil && t.LockedUntil != nil && t.LockedUntil.After(now) {
+			if d := t.LockedUntil.Sub(now); d > wait {
+				wait = d
+			}
+		}
+	}
+
+	if wait > 0 {
+		return &LockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (s *LoginGuardService) lockout(lockCount uint) time.Duration {
+	d := s.cfg.BaseLockout
+	for i := uint(0); i < lockCount && d < s.cfg.MaxLockout; i++ {
+		d *= 2
+	}
+	if d > s.cfg.MaxLockout {
+		d = s.cfg.MaxLockout
+	}
+	return d
+}
+
+// Failure counts a failed login for the account and the client IP, locking whichever reached its limit
+func (s *LoginGuardService) Failure(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	for _, k := range []struct {
+		key string
+		max uint
+	}{
+		{accountKey(email), s.cfg.MaxAccountFailures},
+		{ipKey(ip), s.cfg.MaxIPFailures},
+	} {
+		t, err := s.repo.RegisterFailure(ctx, k.key, now, s.cfg.Window)
+		if err != nil {
+			return err
+		}
+		if t.Failures < k.max {
+			continue
+		}
+
+		d := s.lockout(t.LockCount)
+		locked, err := s.repo.Lock(ctx, k.key, k.max, now.Add(d))
+		if err != nil {
+			return err
+		}
+		if !locked {
+			continue
+		}
+
+		if err := s.audit.Create(ctx, &domain.AuditLog{
+			Action:  domain.AuditLoginLockout,
+			Subject: k.key,
+			IP:      ip,
+			Details: fmt.Sprintf("%d intentos fallidos, bloqueado %s hasta %s", t.Failures, d, now.Add(d).Format(time.RFC3339)),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Success clears the account counters; the IP keeps its count so one valid account
+// can't be used to keep guessing others
+func (s *LoginGuardService) Success(ctx context.Context, email string) error {
+	return s.repo.Reset(ctx, accountKey(email))
+}
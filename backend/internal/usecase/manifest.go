@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"strings"
+	"time"
+)
+
+type ManifestRepo interface {
+	CollectItems(ctx context.Context, stationID uint, day time.Time, routeCode string, manifestID uint) ([]domain.ManifestItem, error)
+	Create(ctx context.Context, m *domain.Manifest) error
+	ReplaceItems(ctx context.Context, m *domain.Manifest) error
+	Issue(ctx context.Context, m *domain.Manifest, issuedBy uint) error
+	FindByID(ctx context.Context, id uint) (*domain.Manifest, error)
+	List(ctx context.Context, stationID uint, day *time.Time) ([]domain.Manifest, error)
+}
+
+type ManifestService struct {
+	repo     ManifestRepo
+	stations StationRepo
+}
+
+func NewManifestService(r ManifestRepo, stations StationRepo) *ManifestService {
+	return &ManifestService{repo: r, stations: stations}
+}
+
+type ManifestRequest struct {
+	StationID uint   `json:"station_id"`
+	Date      string `json:"date"`
+	// Optional: limits the manifest to the parcels loaded into this route
+	RouteCode    string `json:"route_code"`
+	DriverName   string `json:"driver_name"`
+	VehiclePlate string `json:"vehicle_plate"`
+}
+
+func generateManifestNumber(stationCode string, day, t time.Time) string {
+	return fmt.Sprintf("MAN-%s-%s-%d", stationCode, day.Format("20060102"), t.UnixNano()%1_000_000)
+}
+
+// fillTotals sums pieces and weights of the manifest items
+func fillTotals(m *domain.Manifest) {
+	m.TotalPieces = 0
+	m.TotalWeightKg = 0
+	for _, it := range m.Items {
+		m.TotalPieces += it.Pieces
+		m.TotalWeightKg += it.WeightKg
+	}
+}
+
+// Create builds a draft manifest with the orders that left the station on the date
+func (s *ManifestService) Create(ctx context.Context, req ManifestRequest, createdBy uint) (*domain.Manifest, error) {
+	if createdBy == 0 {
+		return nil, errors.New("createdBy requerido")
+	}
+
+	if req.StationID == 0 {
+		return nil, errors.New("station_id requerido")
+	}
+
+	day, err := time.Parse("2006-01-02", strings.TrimSpace(req.Date))
+	if err != nil {
+		return nil, errors.New("date debe tener formato YYYY-MM-DD")
+	}
+
+	station, err := s.stations.FindByID(ctx, req.StationID)
+	if err != nil || station == nil || !station.IsActive {
+		return nil, errors.New("estación no encontrada o inactiva")
+	}
+
+	m := &domain.Manifest{
+		StationID:    station.ID,
+		StationCode:  station.Code,
+		RouteCode:    strings.ToUpper(strings.TrimSpace(req.RouteCode)),
+		ManifestDate: day,
+		DriverName:   strings.TrimSpace(req.DriverName),
+		VehiclePlate: strings.ToUpper(strings.TrimSpace(req.VehiclePlate)),
+		Status:       domain.ManifestDraft,
+		CreatedBy:    createdBy,
+	}
+	m.ManifestNumber = generateManifestNumber(station.Code, day, time.Now())
+
+	m.Items, err = s.repo.CollectItems(ctx, m.StationID, m.ManifestDate, m.RouteCode, 0)
+	if err != nil {
+		return nil, err
+	}
+	fillTotals(m)
+
+	if err := s.repo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (s *ManifestService) Get(ctx context.Context, id uint) (*domain.Manifest, error) {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil || m == nil {
+		return nil, ErrNotFound
+	}
+
+	return m, nil
+}
+
+// Refresh re-collects the orders of a draft manifest. Issued manifests are frozen.
+func (s *ManifestService) Refresh(ctx context.Context, id uint) (*domain.Manifest, error) {
+	m, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Status != domain.ManifestDraft {
+		return nil, fmt.Errorf("%w: el manifiesto ya fue emitido", ErrConflict)
+	}
+
+	m.Items, err = s.repo.CollectItems(ctx, m.StationID, m.ManifestDate, m.RouteCode, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	fillTotals(m)
+
+	if err := s.repo.ReplaceItems(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Issue freezes the manifest and records it in the history of its orders
+func (s *ManifestService) Issue(ctx context.Context, id, issuedBy uint) (*domain.Manifest, error) {
+	m, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Status != domain.ManifestDraft {
+		return nil, fmt.Errorf("%w: el manifiesto ya fue emitido", ErrConflict)
+	}
+
+	if len(m.Items) == 0 {
+		return nil, errors.New("el manifiesto no tiene órdenes")
+	}
+
+	if err := s.repo.Issue(ctx, m, issuedBy); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (s *ManifestService) List(ctx context.Context, stationID uint, date string) ([]domain.Manifest, error) {
+	var day *time.Time
+	if date != "" {
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, errors.New("date debe tener formato YYYY-MM-DD")
+		}
+		day = &d
+	}
+
+	return s.repo.List(ctx, stationID, day)
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+type MFARepo interface {
+	FindUser(ctx context.Context, userID uint) (*domain.User, error)
+	SetPendingSecret(ctx context.Context, userID uint, secret string) error
+	Enable(ctx context.Context, userID uint, step int64, hashes []string) error
+	UseStep(ctx context.Context, userID uint, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error
+	Disable(ctx context.Context, userID uint) error
+}
+
+const (
+	totpPeriod        = 30
+	recoveryCodeCount = 10
+)
+
+type MFAConfig struct {
+	// Issuer shown by authenticator apps
+	Issuer string
+	// Roles that must use MFA to log in
+	RequiredRoles []domain.Role
+}
+
+type MFAService struct {
+	repo MFARepo
+	cfg  MFAConfig
+}
+
+func NewMFAService(r MFARepo, cfg MFAConfig) *MFAService {
+	if cfg.Issuer == "" {
+		cfg.Issuer = "Logistics App"
+	}
+	return &MFAService{repo: r, cfg: cfg}
+}
+
+// MFAEnrollment is returned once when enrolling; the URL is what the QR code encodes
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"otpauth_url"`
+}
+
+var errInvalidMFACode = fmt.Errorf("%w: código de verificación inválido", ErrUnauthorized)
+
+// Required reports whether the role must log in with a second factor
+func (s *MFAService) Required(role domain.Role) bool {
+	for _, r := range s.cfg.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MFAService) user(ctx context.Context, userID uint) (*domain.User, error) {
+	u, err := s.repo.FindUser(ctx, userID)
+	if err != nil || u == nil {
+		return nil, ErrNotFound
+	}
+	return u, nil
+}
+
+// Enroll creates a new TOTP secret for a user without MFA; it only takes effect after Confirm
+func (s *MFAService) Enroll(ctx context.Context, userID uint) (*MFAEnrollment, error) {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.MFAEnabled {
+		return nil, fmt.Errorf("%w: la autenticación de dos factores ya está activa", ErrConflict)
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: s.cfg.Issuer, AccountName: u.Email, Period: totpPeriod})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetPendingSecret(ctx, u.ID, key.Secret()); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{Secret: key.Secret(), URL: key.URL()}, nil
+}
+
+// matchStep returns the time step the code belongs to, allowing one step of clock skew
+func matchStep(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if secret == "" || len(code) != 6 {
+		return 0, false
+	}
+
+	step := now.Unix() / totpPeriod
+	for _, candidate := range []int64{step, step - 1, step + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(candidate*totpPeriod, 0), totp.ValidateOpts{
+			Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1,
+		})
+		if err == nil && expected == code {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
+
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	buf := make([]byte, 10)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		var b strings.Builder
+		for j, c := range buf {
+			if j == 5 {
+				b.WriteByte('-')
+			}
+			b.WriteByte(recoveryAlphabet[int(c)%len(recoveryAlphabet)])
+		}
+		codes = append(codes, b.String())
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(b.String())))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// Confirm enables MFA with a code from the enrolled secret and returns the recovery codes,
+// which are shown only this once
+func (s *MFAService) Confirm(ctx context.Context, userID uint, code string) ([]string, error) {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.MFAEnabled {
+		return nil, fmt.Errorf("%w: la autenticación de dos factores ya está activa", ErrConflict)
+	}
+
+	if u.MFASecret == "" {
+		return nil, errors.New("primero debe iniciar la activación de la autenticación de dos factores")
+	}
+
+	step, ok := matchStep(u.MFASecret, code, time.Now())
+	if !ok {
+		return nil, errInvalidMFACode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Enable(ctx, u.ID, step, hashes); err != nil {
+		if errors.Is(err, repository.ErrStaleVersion) {
+			return nil, fmt.Errorf("%w: la autenticación de dos factores ya está activa", ErrConflict)
+		}
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Verify checks a TOTP code or, failing that, consumes a recovery code. Each TOTP code is accepted once.
+func (s *MFAService) Verify(ctx context.Context, userID uint, code string) error {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !u.MFAEnabled {
+		return errInvalidMFACode
+	}
+
+	if step, ok := matchStep(u.MFASecret, code, time.Now()); ok {
+		fresh, err := s.repo.UseStep(ctx, u.ID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return errInvalidMFACode
+		}
+		return nil
+	}
+
+	if normalized := normalizeRecoveryCode(code); len(normalized) == 10 {
+		used, err := s.repo.UseRecoveryCode(ctx, u.ID, hashToken(normalized))
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	}
+
+	return errInvalidMFACode
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a current code
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns MFA off after checking a current code; roles that require MFA can't disable it
+func (s *MFAService) Disable(ctx context.Context, userID uint, role domain.Role, code string) error {
+	if s.Required(role) {
+		return fmt.Errorf("%w: la autenticación de dos factores es obligatoria para el rol %s", ErrForbidden, role)
+	}
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+
+	return s.repo.Disable(ctx, userID)
+}
@@ -1,109 +1,370 @@
 package usecase
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	"fmt"
 	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"strings"
 	"time"
 )
 
 type OrderRepo interface {
-	Create(o *domain.Order) error
-	FindByID(id uint) (*domain.Order, error)
-	FindByCustomer(customerID uint) ([]domain.Order, error)
-	FindAll() ([]domain.Order, error)
-	UpdateStatus(id uint, internalNotes string, status domain.OrderStatus, changedBy uint) error
-	FindJoinedByCustomer(customerID uint) ([]domain.OrderListItem, error)
-	FindJoinedAll() ([]domain.OrderListItem, error)
-	FindDetailByID(id uint) (*domain.OrderDetail, error)
+	Create(ctx context.Context, o *domain.Order) error
+	FindByID(ctx context.Context, id uint) (*domain.Order, error)
+	FindByCustomer(ctx context.Context, scope domain.Scope) ([]domain.Order, error)
+	FindAll(ctx context.Context) ([]domain.Order, error)
+	UpdateStatus(ctx context.Context, id uint, version uint, internalNotes string, status domain.OrderStatus, changedBy uint) (uint, error)
+	FindJoinedByCustomer(ctx context.Context, scope domain.Scope) ([]domain.OrderListItem, error)
+	FindJoinedAll(ctx context.Context) ([]domain.OrderListItem, error)
+	FindDetailByID(ctx context.Context, id uint) (*domain.OrderDetail, error)
+	FindHistory(ctx context.Context, orderID uint) ([]domain.OrderStatusHistory, error)
+	FindReturnOf(ctx context.Context, orderID uint) (*domain.Order, error)
+	FindDeliveryStops(ctx context.Context) ([]domain.DeliveryStop, error)
+	StreamJoined(ctx context.Context, scope *domain.Scope, fn func(domain.OrderExportRow) error) error
 }
 
 type PackageTypeValidator interface {
-	ValidatePackageWeight(packageTypeID uint, weightKg float64) error
+	ValidatePackageWeight(ctx context.Context, packageTypeID uint, weightKg float64) error
 }
 
 type OrderService struct {
 	repo             OrderRepo
+	addresses        AddressRepo
 	packageValidator PackageTypeValidator
 }
 
-func NewOrderService(r OrderRepo, pv PackageTypeValidator) *OrderService {
+func NewOrderService(r OrderRepo, addresses AddressRepo, pv PackageTypeValidator) *OrderService {
 	return &OrderService{
 		repo:             r,
+		addresses:        addresses,
 		packageValidator: pv,
 	}
 }
 
-func (s *OrderService) FindAll() ([]domain.Order, error) {
-	return s.repo.FindAll()
+func (s *OrderService) FindAll(ctx context.Context) ([]domain.Order, error) {
+	return s.repo.FindAll(ctx)
 }
 
-func (s *OrderService) FindByCustomer(customerID uint) ([]domain.Order, error) {
-	return s.repo.FindByCustomer(customerID)
+// FindByCustomer returns the orders of the scope: the user's and, for members, the organization's
+func (s *OrderService) FindByCustomer(ctx context.Context, scope domain.Scope) ([]domain.Order, error) {
+	return s.repo.FindByCustomer(ctx, scope)
 }
 
-func (s *OrderService) ListJoinedAll() ([]domain.OrderListItem, error) {
+func (s *OrderService) ListJoinedAll(ctx context.Context) ([]domain.OrderListItem, error) {
 
-	return s.repo.FindJoinedAll()
+	return s.repo.FindJoinedAll(ctx)
 }
 
-func (s *OrderService) ListJoinedByCustomer(customerID uint) ([]domain.OrderListItem, error) {
-	return s.repo.FindJoinedByCustomer(customerID)
+func (s *OrderService) ListJoinedByCustomer(ctx context.Context, scope domain.Scope) ([]domain.OrderListItem, error) {
+	return s.repo.FindJoinedByCustomer(ctx, scope)
 }
 
-func (s *OrderService) GetDetailByID(id uint) (*domain.OrderDetail, error) {
-	return s.repo.FindDetailByID(id)
+// ExportJoinedAll streams every order to fn, one row at a time
+func (s *OrderService) ExportJoinedAll(ctx context.Context, fn func(domain.OrderExportRow) error) error {
+	return s.repo.StreamJoined(ctx, nil, fn)
 }
 
-func generateOrderNumber(t time.Time) string {
-	return fmt.Sprintf("ORD-%s-%d", t.Format("20060102"), t.UnixNano()%1_000_000)
+// ExportJoinedByCustomer streams the orders of the scope to fn, one row at a time
+func (s *OrderService) ExportJoinedByCustomer(ctx context.Context, scope domain.Scope, fn func(domain.OrderExportRow) error) error {
+	return s.repo.StreamJoined(ctx, &scope, fn)
 }
 
-func (s *OrderService) Create(o *domain.Order) error {
+func (s *OrderService) GetDetailByID(ctx context.Context, id uint) (*domain.OrderDetail, error) {
+	return s.repo.FindDetailByID(ctx, id)
+}
+
+// GetHistory returns the order timeline; only the owner's scope or an admin can read it
+func (s *OrderService) GetHistory(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) ([]domain.OrderStatusHistory, error) {
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Sees(o.CustomerID, o.OrganizationID) {
+		return nil, ErrForbidden
+	}
+
+	return s.repo.FindHistory(ctx, id)
+}
+
+// GetShippingLabel builds the label data of an order from its joined detail; owner or admin only
+func (s *OrderService) GetShippingLabel(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) (*domain.ShippingLabel, error) {
+	d, err := s.repo.FindDetailByID(ctx, id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Sees(d.UserID, d.OrganizationID) {
+		return nil, ErrForbidden
+	}
+
+	join := func(parts ...string) string {
+		out := make([]string, 0, len(parts))
+		for _, p := range parts {
+			if p = strings.TrimSpace(p); p != "" {
+				out = append(out, p)
+			}
+		}
+		return strings.Join(out, " ")
+	}
+
+	recipientName, recipientPhone := d.DeliveryPreferences.RecipientName, d.DeliveryPreferences.RecipientPhone
+	if recipientName == "" {
+		recipientName = d.FullName
+	}
+
+	return &domain.ShippingLabel{
+		OrderNumber:      d.OrderNumber,
+		CreatedAt:        d.CreatedAt,
+		SenderName:       d.FullName,
+		SenderAddress:    []string{join(d.AOStreet, d.AOExterior), d.AONeighborhood, join("CP", d.AOPostal, d.AOCity)},
+		RecipientName:    recipientName,
+		RecipientPhone:   recipientPhone,
+		RecipientAddress: []string{join(d.ADStreet, d.ADExterior), d.ADNeighborhood, join("CP", d.ADPostal, d.ADCity)},
+		SizeCode:         d.SizeCode,
+		WeightKg:         d.ActualWeightKg,
+		Quantity:         d.Quantity,
+		Zone:             domain.ZoneFromPostalCode(d.ADPostal),
+	}, nil
+}
+
+func (s *OrderService) ListDeliveryStops(ctx context.Context) ([]domain.DeliveryStop, error) {
+	return s.repo.FindDeliveryStops(ctx)
+}
+
+func validPhone(v string) bool {
+	digits := 0
+	for i, c := range v {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '+' && i == 0, c == ' ', c == '-':
+		default:
+			return false
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+func validateDeliveryPreferences(p *domain.DeliveryPreferences) error {
+	p.WindowStart = strings.TrimSpace(p.WindowStart)
+	p.WindowEnd = strings.TrimSpace(p.WindowEnd)
+	p.RecipientName = strings.TrimSpace(p.RecipientName)
+	p.RecipientPhone = strings.TrimSpace(p.RecipientPhone)
+
+	if (p.WindowStart == "") != (p.WindowEnd == "") {
+		return errors.New("la ventana de entrega requiere window_start y window_end")
+	}
+
+	if p.WindowStart != "" {
+		if err := parseWindow(p.WindowStart, p.WindowEnd); err != nil {
+			return err
+		}
+	}
+
+	switch p.LeaveWith {
+	case domain.LeaveWithNobody, domain.LeaveWithNeighbor, domain.LeaveWithConcierge:
+	default:
+		return fmt.Errorf("leave_with inválido: %q", p.LeaveWith)
+	}
+
+	if len(p.AccessInstructions) > 500 {
+		return errors.New("access_instructions no puede exceder 500 caracteres")
+	}
+
+	if (p.RecipientName == "") != (p.RecipientPhone == "") {
+		return errors.New("recipient_name y recipient_phone deben indicarse juntos")
+	}
+
+	if p.RecipientPhone != "" && !validPhone(p.RecipientPhone) {
+		return errors.New("recipient_phone inválido")
+	}
+
+	return nil
+}
+
+func preconditionFailed(what string) error {
+	return fmt.Errorf("%w: %s fue modificada por otra operación, vuelva a consultarla", ErrPreconditionFailed, what)
+}
+
+// staleVersion maps a lost optimistic lock to ErrPreconditionFailed
+func staleVersion(err error, what string) error {
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return preconditionFailed(what)
+	}
+	return err
+}
+
+// OrderRequest holds the fields a client sets when creating an order. Owner, number, status, pickup
+// and version are set by the handler and the service, never taken from the body.
+type OrderRequest struct {
+	OriginAddressID      uint    `json:"origin_address_id"`
+	DestinationAddressID uint    `json:"destination_address_id"`
+	PackageTypeID        uint    `json:"package_type_id"`
+	Quantity             uint    `json:"quantity"`
+	ActualWeightKg       float64 `json:"actual_weight_kg"`
+	Observations         string  `json:"observations"`
+	// Only kept for staff that updates order statuses
+	InternalNotes       string                     `json:"internal_notes"`
+	DeliveryPreferences domain.DeliveryPreferences `json:"delivery_preferences"`
+}
+
+// orderNumberAttempts bounds the retries when a generated order number is already taken
+const orderNumberAttempts = 5
+
+// generateOrderNumber returns ORD-YYYYMMDD- followed by 8 random base32 characters (40 bits)
+func generateOrderNumber(t time.Time) (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ORD-%s-%s", t.Format("20060102"), base32.StdEncoding.EncodeToString(buf)), nil
+}
+
+func (s *OrderService) Create(ctx context.Context, o *domain.Order) error {
 	if o.Quantity <= 0 {
-		return errors.New("Quantity es requerido y debe ser mayor a 0")
+		return invalid(errors.New("Quantity es requerido y debe ser mayor a 0"))
 	}
 
 	if o.ActualWeightKg <= 0 {
-		return errors.New("actual_weight_kg es requerido y debe ser mayor a 0")
+		return invalid(errors.New("actual_weight_kg es requerido y debe ser mayor a 0"))
 	}
 
 	if s.packageValidator != nil {
-		if err := s.packageValidator.ValidatePackageWeight(o.PackageTypeID, o.ActualWeightKg); err != nil {
+		if err := s.packageValidator.ValidatePackageWeight(ctx, o.PackageTypeID, o.ActualWeightKg); err != nil {
 			return fmt.Errorf("Validación de peso: %w", err)
 		}
 	}
 
 	if o.OriginAddressID == 0 || o.DestinationAddressID == 0 {
-		return errors.New("origin_address_id y destination_address_id son requeridos")
+		return invalid(errors.New("origin_address_id y destination_address_id son requeridos"))
 	}
 
 	if o.OriginAddressID == o.DestinationAddressID {
-		return errors.New("Origin y destination deben ser diferentes")
+		return invalid(errors.New("Origin y destination deben ser diferentes"))
 	}
 
 	if o.PackageTypeID == 0 {
-		return errors.New("package_type_id es requerido")
+		return invalid(errors.New("package_type_id es requerido"))
 	}
 
 	if o.CustomerID == 0 || o.CreatedBy == 0 {
-		return errors.New("customer_id y created_by son requeridos")
+		return invalid(errors.New("customer_id y created_by son requeridos"))
 	}
 
-	if o.OrderNumber == "" {
-		o.OrderNumber = generateOrderNumber(time.Now())
+	if err := validateDeliveryPreferences(&o.DeliveryPreferences); err != nil {
+		return invalid(err)
 	}
 
+	// Both addresses must be active addresses of the order's owner, personal or of its organization
+	owner := domain.Scope{UserID: o.CustomerID, OrganizationID: o.OrganizationID}
+	for _, a := range []struct {
+		id   uint
+		name string
+	}{{o.OriginAddressID, "origen"}, {o.DestinationAddressID, "destino"}} {
+		addr, err := s.addresses.FindByID(ctx, owner, false, a.id)
+		if err != nil || addr == nil {
+			return invalid(fmt.Errorf("dirección de %s no encontrada", a.name))
+		}
+		if !addr.IsActive {
+			return invalid(fmt.Errorf("la dirección de %s no está activa", a.name))
+		}
+	}
+
+	generated := o.OrderNumber == ""
+
 	if o.Status == "" {
 		o.Status = domain.OrderCreated
 	}
-	return s.repo.Create(o)
+
+	// Delivery attempts are only tracked by the delivery flow
+	o.DeliveryAttempts = 0
+	o.ScheduledDeliveryDate = nil
+	if !generated {
+		err := s.repo.Create(ctx, o)
+		if errors.Is(err, repository.ErrDuplicateOrderNumber) {
+			return fmt.Errorf("%w: el número de orden %s ya existe", ErrConflict, o.OrderNumber)
+		}
+		return err
+	}
+
+	for i := 0; ; i++ {
+		number, err := generateOrderNumber(time.Now())
+		if err != nil {
+			return err
+		}
+		o.OrderNumber = number
+		err = s.repo.Create(ctx, o)
+		if !errors.Is(err, repository.ErrDuplicateOrderNumber) || i == orderNumberAttempts-1 {
+			return err
+		}
+	}
 }
 
-func (s *OrderService) UpdateStatus(id uint, internalNotes string, status domain.OrderStatus, changedBy uint) error {
+// UpdateStatus changes the status if the order is still at version (0 skips the check) and returns the new version
+func (s *OrderService) UpdateStatus(ctx context.Context, id uint, version uint, internalNotes string, status domain.OrderStatus, changedBy uint) (uint, error) {
 	if changedBy == 0 {
-		return errors.New("changedBy requerido")
+		return 0, errors.New("changedBy requerido")
+	}
+
+	newVersion, err := s.repo.UpdateStatus(ctx, id, version, internalNotes, status, changedBy)
+	if err != nil {
+		if errors.Is(err, repository.ErrTerminalStatus) {
+			return 0, fmt.Errorf("%w: la orden ya está cerrada (delivered, cancelled o returned)", ErrConflict)
+		}
+		return 0, staleVersion(err, "la orden")
+	}
+
+	return newVersion, nil
+}
+
+// CreateReturn creates a reverse logistics order for a delivered or rejected order. Origin and
+// destination are swapped from the original, and the return gets its own number and lifecycle.
+func (s *OrderService) CreateReturn(ctx context.Context, scope domain.Scope, isAdmin bool, originalID uint, observations string) (*domain.Order, error) {
+	orig, err := s.repo.FindByID(ctx, originalID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Writable().Sees(orig.CustomerID, orig.OrganizationID) {
+		return nil, ErrForbidden
+	}
+
+	if orig.ReturnOfOrderID != nil {
+		return nil, errors.New("no se puede crear una devolución de otra devolución")
+	}
+
+	if orig.Status != domain.OrderDelivered && orig.Status != domain.OrderReturnToSender {
+		return nil, errors.New("solo se pueden devolver órdenes entregadas o en devolución al remitente")
+	}
+
+	if existing, err := s.repo.FindReturnOf(ctx, orig.ID); err == nil && existing != nil {
+		return nil, fmt.Errorf("la orden ya tiene la devolución %s", existing.OrderNumber)
+	}
+
+	ret := &domain.Order{
+		OrderNumber:          "RET-" + strings.TrimPrefix(orig.OrderNumber, "ORD-"),
+		OriginAddressID:      orig.DestinationAddressID,
+		DestinationAddressID: orig.OriginAddressID,
+		PackageTypeID:        orig.PackageTypeID,
+		Quantity:             orig.Quantity,
+		ActualWeightKg:       orig.ActualWeightKg,
+		Status:               domain.OrderCreated,
+		CustomerID:           orig.CustomerID,
+		OrganizationID:       orig.OrganizationID,
+		CreatedBy:            scope.UserID,
+		UpdatedBy:            &scope.UserID,
+		Observations:         observations,
+		ReturnOfOrderID:      &orig.ID,
+	}
+
+	if err := s.Create(ctx, ret); err != nil {
+		return nil, err
 	}
 
-	return s.repo.UpdateStatus(id, internalNotes, status, changedBy)
+	return ret, nil
 }
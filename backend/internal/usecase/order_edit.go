@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"strconv"
+	"time"
+)
+
+type OrderChangeRepo interface {
+	ApplyEdit(ctx context.Context, orderID uint, version uint, updates map[string]interface{}, changes []domain.OrderChange) error
+	ListByOrder(ctx context.Context, orderID uint) ([]domain.OrderChange, error)
+}
+
+type OrderEditService struct {
+	repo             OrderChangeRepo
+	orders           OrderRepo
+	addresses        AddressRepo
+	packageValidator PackageTypeValidator
+}
+
+func NewOrderEditService(r OrderChangeRepo, orders OrderRepo, addresses AddressRepo, pv PackageTypeValidator) *OrderEditService {
+	return &OrderEditService{repo: r, orders: orders, addresses: addresses, packageValidator: pv}
+}
+
+// OrderEditRequest holds the editable fields; omitted fields keep their current value
+type OrderEditRequest struct {
+	DestinationAddressID *uint    `json:"destination_address_id"`
+	PackageTypeID        *uint    `json:"package_type_id"`
+	ActualWeightKg       *float64 `json:"actual_weight_kg"`
+	Quantity             *uint    `json:"quantity"`
+	Observations         *string  `json:"observations"`
+}
+
+func formatID(v uint) string { return strconv.FormatUint(uint64(v), 10) }
+
+func formatWeight(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+// Edit applies req to an order that is still `created` and at version (0 skips the check). Only the owner
+// or an admin can edit; the result is validated like a new order and each changed field is logged.
+func (s *OrderEditService) Edit(ctx context.Context, scope domain.Scope, isAdmin bool, orderID uint, version uint, req OrderEditRequest) (*domain.Order, []domain.OrderChange, error) {
+	o, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Writable().Sees(o.CustomerID, o.OrganizationID) {
+		return nil, nil, ErrForbidden
+	}
+
+	if version != 0 && o.Version != version {
+		return nil, nil, preconditionFailed("la orden")
+	}
+
+	if o.Status != domain.OrderCreated {
+		return nil, nil, fmt.Errorf("%w: solo se pueden editar órdenes en estado created", ErrConflict)
+	}
+
+	next := *o
+	if req.DestinationAddressID != nil {
+		next.DestinationAddressID = *req.DestinationAddressID
+	}
+	if req.PackageTypeID != nil {
+		next.PackageTypeID = *req.PackageTypeID
+	}
+	if req.ActualWeightKg != nil {
+		next.ActualWeightKg = *req.ActualWeightKg
+	}
+	if req.Quantity != nil {
+		next.Quantity = *req.Quantity
+	}
+	if req.Observations != nil {
+		next.Observations = *req.Observations
+	}
+
+	if next.Quantity == 0 {
+		return nil, nil, errors.New("Quantity debe ser mayor a 0")
+	}
+
+	if next.ActualWeightKg <= 0 {
+		return nil, nil, errors.New("actual_weight_kg debe ser mayor a 0")
+	}
+
+	if next.PackageTypeID == 0 {
+		return nil, nil, errors.New("package_type_id es requerido")
+	}
+
+	if next.DestinationAddressID != o.DestinationAddressID {
+		if next.DestinationAddressID == next.OriginAddressID {
+			return nil, nil, errors.New("Origin y destination deben ser diferentes")
+		}
+
+		// The destination must be an active address of the order's owner, whoever is editing
+		owner := domain.Scope{UserID: o.CustomerID, OrganizationID: o.OrganizationID}
+		addr, err := s.addresses.FindByID(ctx, owner, false, next.DestinationAddressID)
+		if err != nil || addr == nil {
+			return nil, nil, errors.New("dirección de destino no encontrada")
+		}
+
+		if !addr.IsActive {
+			return nil, nil, errors.New("la dirección de destino no está activa")
+		}
+	}
+
+	if s.packageValidator != nil && (next.PackageTypeID != o.PackageTypeID || next.ActualWeightKg != o.ActualWeightKg) {
+		if err := s.packageValidator.ValidatePackageWeight(ctx, next.PackageTypeID, next.ActualWeightKg); err != nil {
+			return nil, nil, fmt.Errorf("Validación de peso: %w", err)
+		}
+	}
+
+	current := o.Version
+	now := time.Now()
+	updates := map[string]interface{}{}
+	var changes []domain.OrderChange
+	track := func(column, oldValue, newValue string, value interface{}) {
+		if oldValue == newValue {
+			return
+		}
+		updates[column] = value
+		changes = append(changes, domain.OrderChange{
+			OrderID:   o.ID,
+			Version:   current + 1,
+			Field:     column,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			ChangedBy: scope.UserID,
+			ChangedAt: now,
+		})
+	}
+	track("destination_address_id", formatID(o.DestinationAddressID), formatID(next.DestinationAddressID), next.DestinationAddressID)
+	track("package_type_id", formatID(o.PackageTypeID), formatID(next.PackageTypeID), next.PackageTypeID)
+	track("actual_weight_kg", formatWeight(o.ActualWeightKg), formatWeight(next.ActualWeightKg), next.ActualWeightKg)
+	track("quantity", formatID(o.Quantity), formatID(next.Quantity), next.Quantity)
+	track("observations", o.Observations, next.Observations, next.Observations)
+
+	// Nothing changed: keep the version so the client's ETag stays valid
+	if len(changes) == 0 {
+		return o, nil, nil
+	}
+
+	updates["updated_by"] = scope.UserID
+	if err := s.repo.ApplyEdit(ctx, o.ID, current, updates, changes); err != nil {
+		return nil, nil, staleVersion(err, "la orden")
+	}
+
+	next.UpdatedBy = &scope.UserID
+	next.UpdatedAt = now
+	next.Version = current + 1
+	return &next, changes, nil
+}
+
+// ListChanges returns the field-level change log of an order; owner or admin only
+func (s *OrderEditService) ListChanges(ctx context.Context, scope domain.Scope, isAdmin bool, orderID uint) ([]domain.OrderChange, error) {
+	o, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Sees(o.CustomerID, o.OrganizationID) {
+		return nil, ErrForbidden
+	}
+
+	return s.repo.ListByOrder(ctx, orderID)
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OrderImportStore gives the import repositories bound to one database transaction.
+// Nested Transaction calls run in a savepoint, so a failing row can be undone on its own.
+type OrderImportStore interface {
+	Transaction(ctx context.Context, fn func(tx OrderImportStore) error) error
+	Orders() OrderRepo
+	Addresses() AddressRepo
+}
+
+type PackageTypeCatalog interface {
+	PackageTypeValidator
+	GetPackageTypes(ctx context.Context) (map[uint]domain.PackageType, error)
+}
+
+// MaxImportRows limits the data rows of one import file
+const MaxImportRows = 1000
+
+type ImportMode string
+
+const (
+	// ImportAtomic commits every row or none
+	ImportAtomic ImportMode = "atomic"
+	// ImportPerRow commits the valid rows and reports the failed ones
+	ImportPerRow ImportMode = "row"
+)
+
+// orderImportColumns are the accepted header names; the required ones must be present
+var orderImportColumns = map[string]bool{
+	"origin_street": true, "origin_exterior_number": false, "origin_interior_number": false, "origin_neighborhood": false,
+	"origin_postal_code": false, "origin_city": true, "origin_state": true, "origin_country": false,
+	"destination_street": true, "destination_exterior_number": false, "destination_interior_number": false, "destination_neighborhood": false,
+	"destination_postal_code": false, "destination_city": true, "destination_state": true, "destination_country": false,
+	"package_size": true, "quantity": true, "weight_kg": true, "observations": false,
+	"recipient_name": false, "recipient_phone": false, "window_start": false, "window_end": false,
+	"leave_with": false, "access_instructions": false,
+}
+
+type OrderImportResult struct {
+	// Line in the file, the header being line 1
+	Line             int    `json:"line"`
+	OrderID          uint   `json:"order_id,omitempty"`
+	OrderNumber      string `json:"order_number,omitempty"`
+	CreatedAddresses int    `json:"created_addresses,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+type OrderImportReport struct {
+	DryRun    bool                `json:"dry_run"`
+	Mode      ImportMode          `json:"mode"`
+	Committed bool                `json:"committed"`
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Rows      []OrderImportResult `json:"rows"`
+}
+
+type OrderImportService struct {
+	store        OrderImportStore
+	packageTypes PackageTypeCatalog
+}
+
+func NewOrderImportService(store OrderImportStore, packageTypes PackageTypeCatalog) *OrderImportService {
+	return &OrderImportService{store: store, packageTypes: packageTypes}
+}
+
+var errImportRollback = errors.New("import rolled back")
+
+// Import creates one order per data row for the owner scope, matching or creating its addresses.
+// Every row runs the AddressService and OrderService.Create rules in its own savepoint. A dry run,
+// or an atomic import with any failed row, is rolled back entirely and only reports.
+func (s *OrderImportService) Import(ctx context.Context, owner domain.Scope, records [][]string, dryRun bool, mode ImportMode) (*OrderImportReport, error) {
+	if owner.UserID == 0 {
+		return nil, errors.New("customerID requerido")
+	}
+	owner = owner.Writable()
+
+	if mode == "" {
+		mode = ImportAtomic
+	}
+	if mode != ImportAtomic && mode != ImportPerRow {
+		return nil, fmt.Errorf("mode inválido: %q (atomic, row)", mode)
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("el archivo no tiene filas de datos")
+	}
+
+	if len(records)-1 > MaxImportRows {
+		return nil, fmt.Errorf("el archivo excede el máximo de %d filas", MaxImportRows)
+	}
+
+	columns, err := importColumns(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OrderImportReport{DryRun: dryRun, Mode: mode, Rows: make([]OrderImportResult, 0, len(records)-1)}
+
+	err = s.store.Transaction(ctx, func(tx OrderImportStore) error {
+		for i, record := range records[1:] {
+			if blankRecord(record) {
+				continue
+			}
+
+			res := OrderImportResult{Line: i + 2}
+			rowErr := tx.Transaction(ctx, func(rtx OrderImportStore) error {
+				return s.importRow(ctx, rtx, owner, columns, record, &res)
+			})
+			if rowErr != nil {
+				res = OrderImportResult{Line: res.Line, Error: rowErr.Error()}
+				report.Failed++
+			} else {
+				report.Succeeded++
+			}
+			report.Rows = append(report.Rows, res)
+		}
+
+		if dryRun || (mode == ImportAtomic && report.Failed > 0) {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return nil, err
+	}
+
+	report.Total = len(report.Rows)
+	report.Committed = err == nil
+	if !report.Committed {
+		// nothing was stored, so the generated ids and numbers mean nothing
+		for i := range report.Rows {
+			report.Rows[i].OrderID = 0
+			report.Rows[i].OrderNumber = ""
+		}
+	}
+
+	return report, nil
+}
+
+func (s *OrderImportService) importRow(ctx context.Context, tx OrderImportStore, owner domain.Scope, columns map[string]int, record []string, res *OrderImportResult) error {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	address := func(prefix string) AddressRequest {
+		return AddressRequest{
+			Street:         get(prefix + "street"),
+			ExteriorNumber: get(prefix + "exterior_number"),
+			InteriorNumber: get(prefix + "interior_number"),
+			Neighborhood:   get(prefix + "neighborhood"),
+			PostalCode:     get(prefix + "postal_code"),
+			City:           get(prefix + "city"),
+			State:          get(prefix + "state"),
+			Country:        get(prefix + "country"),
+		}
+	}
+
+	quantity, err := strconv.ParseUint(get("quantity"), 10, 32)
+	if err != nil {
+		return fmt.Errorf("quantity inválido: %q", get("quantity"))
+	}
+
+	weight, err := strconv.ParseFloat(strings.Replace(get("weight_kg"), ",", ".", 1), 64)
+	if err != nil {
+		return fmt.Errorf("weight_kg inválido: %q", get("weight_kg"))
+	}
+
+	packageTypeID, err := s.packageTypeBySize(ctx, domain.PackageSize(strings.ToUpper(get("package_size"))))
+	if err != nil {
+		return err
+	}
+
+	addresses := NewAddressService(tx.Addresses())
+	origin, created, err := addresses.FindOrCreate(ctx, owner, address("origin_"))
+	if err != nil {
+		return fmt.Errorf("origen: %w", err)
+	}
+	if created {
+		res.CreatedAddresses++
+	}
+
+	destination, created, err := addresses.FindOrCreate(ctx, owner, address("destination_"))
+	if err != nil {
+		return fmt.Errorf("destino: %w", err)
+	}
+	if created {
+		res.CreatedAddresses++
+	}
+
+	o := &domain.Order{
+		OriginAddressID:      origin.ID,
+		DestinationAddressID: destination.ID,
+		PackageTypeID:        packageTypeID,
+		Quantity:             uint(quantity),
+		ActualWeightKg:       weight,
+		CustomerID:           owner.UserID,
+		OrganizationID:       owner.OrganizationID,
+		CreatedBy:            owner.UserID,
+		UpdatedBy:            &owner.UserID,
+		Observations:         get("observations"),
+		DeliveryPreferences: domain.DeliveryPreferences{
+			WindowStart:        get("window_start"),
+			WindowEnd:          get("window_end"),
+			LeaveWith:          domain.LeaveWith(strings.ToLower(get("leave_with"))),
+			AccessInstructions: get("access_instructions"),
+			RecipientName:      get("recipient_name"),
+			RecipientPhone:     get("recipient_phone"),
+		},
+	}
+	if err := NewOrderService(tx.Orders(), tx.Addresses(), s.packageTypes).Create(ctx, o); err != nil {
+		return err
+	}
+
+	res.OrderID = o.ID
+	res.OrderNumber = o.OrderNumber
+	return nil
+}
+
+func (s *OrderImportService) packageTypeBySize(ctx context.Context, size domain.PackageSize) (uint, error) {
+	types, err := s.packageTypes.GetPackageTypes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for id, pt := range types {
+		if pt.SizeCode == size && pt.IsActive {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("package_size inválido: %q", size)
+}
+
+// importColumns maps the header names to their column index
+func importColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if _, ok := orderImportColumns[name]; !ok {
+			return nil, fmt.Errorf("columna desconocida: %q", h)
+		}
+		columns[name] = i
+	}
+
+	var missing []string
+	for name, required := range orderImportColumns {
+		if _, ok := columns[name]; required && !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("faltan columnas requeridas: %s", strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+func blankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"strings"
+	"time"
+)
+
+type OrganizationRepo interface {
+	Create(ctx context.Context, o *domain.Organization) error
+	FindByID(ctx context.Context, id uint) (*domain.Organization, error)
+	CreateInvitation(ctx context.Context, inv *domain.OrganizationInvitation) error
+	FindInvitationByHash(ctx context.Context, hash string) (*domain.OrganizationInvitation, error)
+	AcceptInvitation(ctx context.Context, inv *domain.OrganizationInvitation, userID uint) error
+	UpdateMemberRole(ctx context.Context, orgID, userID uint, role domain.OrgRole) error
+	RemoveMember(ctx context.Context, orgID, userID uint) error
+}
+
+const DefaultInvitationTTL = 7 * 24 * time.Hour
+
+type OrganizationConfig struct {
+	// Base URL of the frontend, links are built as <BaseURL>/accept-invitation?token=...
+	BaseURL       string
+	InvitationTTL time.Duration
+}
+
+type OrganizationService struct {
+	repo   OrganizationRepo
+	users  UserRepo
+	mailer Mailer
+	cfg    OrganizationConfig
+}
+
+func NewOrganizationService(repo OrganizationRepo, users UserRepo, mailer Mailer, cfg OrganizationConfig) *OrganizationService {
+	if cfg.InvitationTTL <= 0 {
+		cfg.InvitationTTL = DefaultInvitationTTL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &OrganizationService{repo: repo, users: users, mailer: mailer, cfg: cfg}
+}
+
+// member maps a user who already belongs to an organization to a conflict
+func member(err error) error {
+	if errors.Is(err, repository.ErrAlreadyMember) {
+		return fmt.Errorf("%w: el usuario ya pertenece a una organización", ErrConflict)
+	}
+	return err
+}
+
+// Create opens an organization with the user as its owner; only clients outside any organization can
+func (s *OrganizationService) Create(ctx context.Context, userID uint, name string) (*domain.Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name es requerido")
+	}
+
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, ErrNotFound
+	}
+
+	if u.Role != domain.RoleClient {
+		return nil, fmt.Errorf("%w: solo los clientes pueden pertenecer a una organización", ErrForbidden)
+	}
+
+	if u.OrganizationID != nil {
+		return nil, fmt.Errorf("%w: el usuario ya pertenece a una organización", ErrConflict)
+	}
+
+	o := &domain.Organization{Name: name, CreatedBy: userID}
+	if err := s.repo.Create(ctx, o); err != nil {
+		return nil, member(err)
+	}
+
+	return s.repo.FindByID(ctx, o.ID)
+}
+
+// Get returns the organization of the scope with its members
+func (s *OrganizationService) Get(ctx context.Context, scope domain.Scope) (*domain.Organization, error) {
+	if scope.OrganizationID == nil {
+		return nil, ErrNotFound
+	}
+
+	o, err := s.repo.FindByID(ctx, *scope.OrganizationID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	return o, nil
+}
+
+// owned returns the organization of the scope when the caller is its owner
+func (s *OrganizationService) owned(ctx context.Context, scope domain.Scope) (*domain.Organization, error) {
+	o, err := s.Get(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if scope.OrgRole != domain.OrgOwner {
+		return nil, fmt.Errorf("%w: solo un owner puede administrar la organización", ErrForbidden)
+	}
+
+	return o, nil
+}
+
+// Invite emails a single-use link to join the owner's organization with role. Inviting the same
+// email again revokes the earlier link.
+func (s *OrganizationService) Invite(ctx context.Context, scope domain.Scope, email string, role domain.OrgRole) (*domain.OrganizationInvitation, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, errors.New("email es requerido")
+	}
+
+	if !role.Valid() {
+		return nil, fmt.Errorf("role inválido: %q (owner, shipper, viewer)", role)
+	}
+
+	o, err := s.owned(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range o.Members {
+		if strings.EqualFold(m.Email, email) {
+			return nil, fmt.Errorf("%w: el usuario ya es miembro de la organización", ErrConflict)
+		}
+	}
+
+	raw, err := NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &domain.OrganizationInvitation{
+		OrganizationID: o.ID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      hashToken(raw),
+		InvitedBy:      scope.UserID,
+		ExpiresAt:      time.Now().Add(s.cfg.InvitationTTL),
+	}
+	if err := s.repo.CreateInvitation(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf("Hola,\n\nTe invitaron a unirte a %s como %s. Para aceptar abre el siguiente enlace (válido por %s):\n\n%s/accept-invitation?token=%s\n\nSi no esperabas esta invitación, ignora este mensaje.\n",
+		o.Name, role, s.cfg.InvitationTTL, s.cfg.BaseURL, raw)
+	if err := s.mailer.Send(email, "Invitación a "+o.Name, body); err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+// Accept adds the user to the organization of a live invitation addressed to their email
+func (s *OrganizationService) Accept(ctx context.Context, userID uint, raw string) (*domain.Organization, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("token requerido")
+	}
+
+	inv, err := s.repo.FindInvitationByHash(ctx, hashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	if inv == nil || inv.AcceptedAt != nil || inv.RevokedAt != nil || !time.Now().Before(inv.ExpiresAt) {
+		return nil, fmt.Errorf("%w: la invitación es inválida, ya fue usada o expiró", ErrUnprocessable)
+	}
+
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil || u == nil {
+		return nil, ErrNotFound
+	}
+
+	if !strings.EqualFold(u.Email, inv.Email) {
+		return nil, fmt.Errorf("%w: la invitación es para otro correo", ErrForbidden)
+	}
+
+	if u.Role != domain.RoleClient {
+		return nil, fmt.Errorf("%w: solo los clientes pueden pertenecer a una organización", ErrForbidden)
+	}
+
+	if u.OrganizationID != nil {
+		return nil, fmt.Errorf("%w: el usuario ya pertenece a una organización", ErrConflict)
+	}
+
+	if err := s.repo.AcceptInvitation(ctx, inv, userID); err != nil {
+		if errors.Is(err, repository.ErrTokenUsed) {
+			return nil, fmt.Errorf("%w: la invitación es inválida, ya fue usada o expiró", ErrUnprocessable)
+		}
+		return nil, member(err)
+	}
+
+	return s.repo.FindByID(ctx, inv.OrganizationID)
+}
+
+// lastOwner reports whether userID is the only owner of o
+func lastOwner(o *domain.Organization, userID uint) bool {
+	owners := 0
+	isOwner := false
+	for _, m := range o.Members {
+		if m.OrgRole == domain.OrgOwner {
+			owners++
+			isOwner = isOwner || m.ID == userID
+		}
+	}
+	return isOwner && owners == 1
+}
+
+func hasMember(o *domain.Organization, userID uint) bool {
+	for _, m := range o.Members {
+		if m.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateMemberRole changes a member's role; the organization always keeps an owner
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, scope domain.Scope, memberID uint, role domain.OrgRole) error {
+	if !role.Valid() {
+		return fmt.Errorf("role inválido: %q (owner, shipper, viewer)", role)
+	}
+
+	o, err := s.owned(ctx, scope)
+	if err != nil {
+		return err
+	}
+
+	if !hasMember(o, memberID) {
+		return ErrNotFound
+	}
+
+	if role != domain.OrgOwner && lastOwner(o, memberID) {
+		return fmt.Errorf("%w: la organización debe conservar al menos un owner", ErrConflict)
+	}
+
+	return s.repo.UpdateMemberRole(ctx, o.ID, memberID, role)
+}
+
+// RemoveMember takes a member out of the organization. Owners remove anyone and members can leave;
+// the rows they created stay with the organization.
+func (s *OrganizationService) RemoveMember(ctx context.Context, scope domain.Scope, memberID uint) error {
+	o, err := s.Get(ctx, scope)
+	if err != nil {
+		return err
+	}
+
+	if scope.OrgRole != domain.OrgOwner && memberID != scope.UserID {
+		return fmt.Errorf("%w: solo un owner puede administrar la organización", ErrForbidden)
+	}
+
+	if !hasMember(o, memberID) {
+		return ErrNotFound
+	}
+
+	if lastOwner(o, memberID) {
+		return fmt.Errorf("%w: la organización debe conservar al menos un owner", ErrConflict)
+	}
+
+	return s.repo.RemoveMember(ctx, o.ID, memberID)
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"logistics-app/backend/internal/domain"
 	"sync"
@@ -8,8 +9,8 @@ import (
 )
 
 type PackageTypeRepo interface {
-	FindAll(includeInactive bool) ([]domain.PackageType, error)
-	SetActive(id uint, active bool) error
+	FindAll(ctx context.Context, includeInactive bool) ([]domain.PackageType, error)
+	SetActive(ctx context.Context, id uint, active bool) error
 }
 
 type PackageTypeService struct {
@@ -28,23 +29,23 @@ func NewPackageTypeService(r PackageTypeRepo) *PackageTypeService {
 	}
 }
 
-func (s *PackageTypeService) List(includeInactive bool) ([]domain.PackageType, error) {
-	return s.repo.FindAll(includeInactive)
+func (s *PackageTypeService) List(ctx context.Context, includeInactive bool) ([]domain.PackageType, error) {
+	return s.repo.FindAll(ctx, includeInactive)
 }
 
-func (s *PackageTypeService) ToggleActive(id uint, active bool) error {
+func (s *PackageTypeService) ToggleActive(ctx context.Context, id uint, active bool) error {
 	if id == 0 {
 		return errors.New("id requerido")
 	}
 
-	if err := s.repo.SetActive(id, active); err == nil {
+	if err := s.repo.SetActive(ctx, id, active); err == nil {
 		s.invalidateCache()
 	}
 
-	return s.repo.SetActive(id, active)
+	return s.repo.SetActive(ctx, id, active)
 }
 
-func (s *PackageTypeService) GetPackageTypes() (map[uint]domain.PackageType, error) {
+func (s *PackageTypeService) GetPackageTypes(ctx context.Context) (map[uint]domain.PackageType, error) {
 	s.mutex.RLock()
 
 	if len(s.cache) > 0 && time.Since(s.lastUpdate) < s.cacheTTL {
@@ -60,7 +61,7 @@ func (s *PackageTypeService) GetPackageTypes() (map[uint]domain.PackageType, err
 		return s.cache, nil
 	}
 
-	types, err := s.repo.FindAll(false)
+	types, err := s.repo.FindAll(ctx, false)
 	if err != nil {
 		return nil, err
 	}
@@ -76,8 +77,8 @@ func (s *PackageTypeService) GetPackageTypes() (map[uint]domain.PackageType, err
 	return s.cache, nil
 }
 
-func (s *PackageTypeService) ValidatePackageWeight(packageTypeID uint, weightKg float64) error {
-	packageTypes, err := s.GetPackageTypes()
+func (s *PackageTypeService) ValidatePackageWeight(ctx context.Context, packageTypeID uint, weightKg float64) error {
+	packageTypes, err := s.GetPackageTypes(ctx)
 	if err != nil {
 		return err
 	}
@@ -86,20 +87,20 @@ func (s *PackageTypeService) ValidatePackageWeight(packageTypeID uint, weightKg
 	packageType, exists := packageTypes[packageTypeID]
 	if !exists {
 		if weightKg > 25 {
-			return errors.New(errMsgExceeds)
+			return invalid(errors.New(errMsgExceeds))
 		}
-		return errors.New("Tipo de paquete no encontrado")
+		return invalid(errors.New("Tipo de paquete no encontrado"))
 	}
 
 	if !packageType.IsActive {
-		return errors.New("Tipo de paquete no está activo")
+		return invalid(errors.New("Tipo de paquete no está activo"))
 	}
 
 	if weightKg > packageType.MaxWeightKg {
 		if weightKg > 25 {
-			return errors.New(errMsgExceeds)
+			return invalid(errors.New(errMsgExceeds))
 		}
-		return errors.New("El peso del paquete excede el límite máximo para este tipo de paquete")
+		return invalid(errors.New("El peso del paquete excede el límite máximo para este tipo de paquete"))
 	}
 
 	return nil
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"time"
+)
+
+type PickupRepo interface {
+	FindSlot(ctx context.Context, zone, start, end string) (*domain.PickupSlot, error)
+	ListSlots(ctx context.Context) ([]domain.PickupSlot, error)
+	SaveSlot(ctx context.Context, s *domain.PickupSlot) error
+	Save(ctx context.Context, p *domain.Pickup, slot *domain.PickupSlot, attach []uint) error
+	FindByID(ctx context.Context, id uint) (*domain.Pickup, error)
+	List(ctx context.Context, scope *domain.Scope, date *time.Time) ([]domain.Pickup, error)
+	Cancel(ctx context.Context, id uint, changedBy uint) error
+}
+
+type PickupService struct {
+	repo      PickupRepo
+	addresses AddressRepo
+}
+
+func NewPickupService(r PickupRepo, addresses AddressRepo) *PickupService {
+	return &PickupService{repo: r, addresses: addresses}
+}
+
+type PickupRequest struct {
+	OriginAddressID uint   `json:"origin_address_id"`
+	Date            string `json:"date"`
+	WindowStart     string `json:"window_start"`
+	WindowEnd       string `json:"window_end"`
+	OrderIDs        []uint `json:"order_ids"`
+	Notes           string `json:"notes"`
+}
+
+func parseWindow(start, end string) error {
+	s, err := time.Parse("15:04", start)
+	if err != nil {
+		return errors.New("window_start debe tener formato HH:MM")
+	}
+
+	e, err := time.Parse("15:04", end)
+	if err != nil {
+		return errors.New("window_end debe tener formato HH:MM")
+	}
+
+	if !s.Before(e) {
+		return errors.New("window_start debe ser anterior a window_end")
+	}
+
+	return nil
+}
+
+// windowEnded reports whether the window ending at end (HH:MM) on date is already over at now;
+// dates and windows are read in the server's time zone, like the past-date check
+func windowEnded(date time.Time, end string, now time.Time) bool {
+	e, err := time.Parse("15:04", end)
+	if err != nil {
+		return false
+	}
+	y, m, d := date.Date()
+	return !time.Date(y, m, d, e.Hour(), e.Minute(), 0, 0, time.Local).After(now)
+}
+
+func parsePickupDate(v string) (time.Time, error) {
+	d, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, errors.New("date debe tener formato YYYY-MM-DD")
+	}
+
+	y, m, day := time.Now().Date()
+	if d.Before(time.Date(y, m, day, 0, 0, 0, 0, time.UTC)) {
+		return time.Time{}, errors.New("la fecha de recolección no puede estar en el pasado")
+	}
+
+	return d, nil
+}
+
+func (s *PickupService) findSlot(ctx context.Context, zone, start, end string) (*domain.PickupSlot, error) {
+	slot, err := s.repo.FindSlot(ctx, zone, start, end)
+	if err != nil || slot == nil {
+		return nil, errors.New("no hay horario de recolección disponible para esa ventana en la zona")
+	}
+	return slot, nil
+}
+
+// Book schedules a pickup at one of the requester's origin addresses, optionally grouping created orders
+func (s *PickupService) Book(ctx context.Context, scope domain.Scope, isAdmin bool, req PickupRequest) (*domain.Pickup, error) {
+	if req.OriginAddressID == 0 {
+		return nil, errors.New("origin_address_id es requerido")
+	}
+
+	date, err := parsePickupDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parseWindow(req.WindowStart, req.WindowEnd); err != nil {
+		return nil, err
+	}
+
+	if windowEnded(date, req.WindowEnd, time.Now()) {
+		return nil, errors.New("la ventana de recolección ya terminó")
+	}
+
+	addr, err := s.addresses.FindByID(ctx, scope.Writable(), isAdmin, req.OriginAddressID)
+	if err != nil || addr == nil {
+		return nil, errors.New("dirección de origen no encontrada")
+	}
+
+	if !addr.IsActive {
+		return nil, errors.New("la dirección de origen no está activa")
+	}
+
+	zone := domain.ZoneFromPostalCode(addr.PostalCode)
+	if zone == "" {
+		return nil, errors.New("la dirección de origen requiere código postal para asignar zona")
+	}
+
+	slot, err := s.findSlot(ctx, zone, req.WindowStart, req.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	// de-duplicate grouped orders
+	seen := make(map[uint]bool, len(req.OrderIDs))
+	attach := make([]uint, 0, len(req.OrderIDs))
+	for _, id := range req.OrderIDs {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			attach = append(attach, id)
+		}
+	}
+
+	p := &domain.Pickup{
+		CustomerID:      addr.CustomerID,
+		OrganizationID:  addr.OrganizationID,
+		OriginAddressID: addr.ID,
+		Zone:            zone,
+		PickupDate:      date,
+		WindowStart:     req.WindowStart,
+		WindowEnd:       req.WindowEnd,
+		Status:          domain.PickupScheduled,
+		Notes:           req.Notes,
+		CreatedBy:       scope.UserID,
+		UpdatedBy:       &scope.UserID,
+	}
+
+	if err := s.repo.Save(ctx, p, slot, attach); err != nil {
+		return nil, err
+	}
+	p.OrderIDs = attach
+
+	return p, nil
+}
+
+func (s *PickupService) getOwned(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) (*domain.Pickup, error) {
+	p, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	if !isAdmin && !scope.Sees(p.CustomerID, p.OrganizationID) {
+		return nil, ErrForbidden
+	}
+
+	return p, nil
+}
+
+func (s *PickupService) Get(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) (*domain.Pickup, error) {
+	return s.getOwned(ctx, scope, isAdmin, id)
+}
+
+// Reschedule moves a scheduled pickup to another date and window, keeping its grouped orders
+func (s *PickupService) Reschedule(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, date, start, end string) (*domain.Pickup, error) {
+	p, err := s.getOwned(ctx, scope.Writable(), isAdmin, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Status != domain.PickupScheduled {
+		return nil, errors.New("solo se pueden reprogramar recolecciones programadas")
+	}
+
+	d, err := parsePickupDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parseWindow(start, end); err != nil {
+		return nil, err
+	}
+
+	if windowEnded(d, end, time.Now()) {
+		return nil, errors.New("la ventana de recolección ya terminó")
+	}
+
+	slot, err := s.findSlot(ctx, p.Zone, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	p.PickupDate = d
+	p.WindowStart = start
+	p.WindowEnd = end
+	p.UpdatedBy = &scope.UserID
+
+	if err := s.repo.Save(ctx, p, slot, nil); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (s *PickupService) Cancel(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) error {
+	p, err := s.getOwned(ctx, scope.Writable(), isAdmin, id)
+	if err != nil {
+		return err
+	}
+
+	if p.Status != domain.PickupScheduled {
+		return errors.New("solo se pueden cancelar recolecciones programadas")
+	}
+
+	return s.repo.Cancel(ctx, id, scope.UserID)
+}
+
+// List returns the pickups of the requester's scope; admins can pass all to see every customer. When date is set
+// only the scheduled pickups due that day are returned, which is what dispatch works from.
+func (s *PickupService) List(ctx context.Context, scope domain.Scope, isAdmin bool, all bool, date string) ([]domain.Pickup, error) {
+	var day *time.Time
+	if date != "" {
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, errors.New("date debe tener formato YYYY-MM-DD")
+		}
+		day = &d
+	}
+
+	if isAdmin && all {
+		return s.repo.List(ctx, nil, day)
+	}
+
+	return s.repo.List(ctx, &scope, day)
+}
+
+func (s *PickupService) ListSlots(ctx context.Context) ([]domain.PickupSlot, error) {
+	return s.repo.ListSlots(ctx)
+}
+
+func (s *PickupService) SaveSlot(ctx context.Context, slot *domain.PickupSlot) error {
+	if err := parseWindow(slot.WindowStart, slot.WindowEnd); err != nil {
+		return err
+	}
+
+	if slot.Capacity == 0 {
+		return errors.New("capacity debe ser mayor a 0")
+	}
+
+	return s.repo.SaveSlot(ctx, slot)
+}
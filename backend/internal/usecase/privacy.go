@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"strings"
+	"time"
+)
+
+type PrivacyRepo interface {
+	PersonalData(ctx context.Context, userID uint) (*domain.PersonalData, error)
+	CreateErasureRequest(ctx context.Context, e *domain.ErasureRequest) error
+	FindErasureRequest(ctx context.Context, id uint) (*domain.ErasureRequest, error)
+	HasPendingErasure(ctx context.Context, userID uint) (bool, error)
+	ListErasureRequests(ctx context.Context, status domain.ErasureStatus) ([]domain.ErasureRequest, error)
+	RejectErasure(ctx context.Context, id, by uint, note string) error
+	Erase(ctx context.Context, e *domain.ErasureRequest, by uint, note string) error
+}
+
+// PrivacyService answers data protection requests (ARCO under the LFPDPPP, and the GDPR): users
+// export their data and ask for its erasure, which an administrator reviews
+type PrivacyService struct {
+	repo  PrivacyRepo
+	users *UserService
+	audit AuditRepo
+}
+
+func NewPrivacyService(repo PrivacyRepo, users *UserService, audit AuditRepo) *PrivacyService {
+	return &PrivacyService{repo: repo, users: users, audit: audit}
+}
+
+func (s *PrivacyService) log(ctx context.Context, action string, actorID uint, subject, ip, details string) error {
+	return s.audit.Create(ctx, &domain.AuditLog{Action: action, ActorID: &actorID, Subject: subject, IP: ip, Details: details})
+}
+
+// Export returns everything kept about the user as a customer, and logs the access
+func (s *PrivacyService) Export(ctx context.Context, userID uint, ip string) (*domain.PersonalData, error) {
+	d, err := s.repo.PersonalData(ctx, userID)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	d.ExportedAt = time.Now()
+
+	if err := s.log(ctx, domain.AuditPrivacyExport, userID, fmt.Sprintf("user:%d", userID), ip,
+		fmt.Sprintf("%d direcciones, %d órdenes", len(d.Addresses), len(d.Orders))); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// RequestErasure files the user's request to erase their personal data; one may be pending at a time
+func (s *PrivacyService) RequestErasure(ctx context.Context, userID uint, ip, reason string) (*domain.ErasureRequest, error) {
+	pending, err := s.repo.HasPendingErasure(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, fmt.Errorf("%w: ya hay una solicitud de eliminación pendiente", ErrConflict)
+	}
+
+	e := &domain.ErasureRequest{UserID: userID, Reason: strings.TrimSpace(reason), Status: domain.ErasurePending}
+	if err := s.repo.CreateErasureRequest(ctx, e); err != nil {
+		return nil, err
+	}
+
+	if err := s.log(ctx, domain.AuditPrivacyErasureRequest, userID, fmt.Sprintf("erasure_request:%d", e.ID), ip, ""); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (s *PrivacyService) ListErasureRequests(ctx context.Context, status domain.ErasureStatus) ([]domain.ErasureRequest, error) {
+	if status != "" && !status.Valid() {
+		return nil, fmt.Errorf("status inválido: %q (pending, completed, rejected)", status)
+	}
+	return s.repo.ListErasureRequests(ctx, status)
+}
+
+// pending returns the request while it waits for review by someone other than its requester
+func (s *PrivacyService) pending(ctx context.Context, actor domain.Principal, id uint) (*domain.ErasureRequest, error) {
+	if !actor.Can(domain.PermPrivacyManage) {
+		return nil, ErrForbidden
+	}
+
+	e, err := s.repo.FindErasureRequest(ctx, id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if e.Status != domain.ErasurePending {
+		return nil, fmt.Errorf("%w: la solicitud ya fue revisada", ErrConflict)
+	}
+	if e.UserID == actor.UserID {
+		return nil, fmt.Errorf("%w: otro administrador debe revisar su solicitud", ErrForbidden)
+	}
+
+	return e, nil
+}
+
+func reviewed(err error) error {
+	if errors.Is(err, repository.ErrTokenUsed) {
+		return fmt.Errorf("%w: la solicitud ya fue revisada", ErrConflict)
+	}
+	return err
+}
+
+// ApproveErasure erases the requester's personal data: the user and their personal addresses are
+// anonymized and the account deleted, while their orders stay for accounting
+func (s *PrivacyService) ApproveErasure(ctx context.Context, actor domain.Principal, ip string, id uint, note string) (*domain.ErasureRequest, error) {
+	e, err := s.pending(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.users.repo.FindByID(ctx, e.UserID)
+	if err != nil || u == nil {
+		return nil, fmt.Errorf("%w: la cuenta ya no existe", ErrConflict)
+	}
+	if err := s.users.deletable(ctx, u); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Erase(ctx, e, actor.UserID, strings.TrimSpace(note)); err != nil {
+		return nil, reviewed(err)
+	}
+
+	if err := s.log(ctx, domain.AuditPrivacyErasureApprove, actor.UserID, fmt.Sprintf("erasure_request:%d", e.ID), ip,
+		fmt.Sprintf("user:%d", e.UserID)); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindErasureRequest(ctx, e.ID)
+}
+
+func (s *PrivacyService) RejectErasure(ctx context.Context, actor domain.Principal, ip string, id uint, note string) (*domain.ErasureRequest, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, errors.New("note es requerido al rechazar")
+	}
+
+	e, err := s.pending(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RejectErasure(ctx, e.ID, actor.UserID, note); err != nil {
+		return nil, reviewed(err)
+	}
+
+	if err := s.log(ctx, domain.AuditPrivacyErasureReject, actor.UserID, fmt.Sprintf("erasure_request:%d", e.ID), ip,
+		fmt.Sprintf("user:%d", e.UserID)); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindErasureRequest(ctx, e.ID)
+}
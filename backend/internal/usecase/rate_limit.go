@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a client exceeded its request rate
+var ErrRateLimited = errors.New("límite de peticiones excedido, intente más tarde")
+
+// RateLimitedError carries how long the client must wait before the next request
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string { return ErrRateLimited.Error() }
+
+func (e *RateLimitedError) Unwrap() error { return ErrRateLimited }
+
+type rateWindow struct {
+	start time.Time
+	count uint
+}
+
+// RateLimiter counts requests per key in fixed windows. Counters live in memory, so with several
+// API instances each one enforces the limit on its own.
+type RateLimiter struct {
+	mu        sync.Mutex
+	window    time.Duration
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+func NewRateLimiter(window time.Duration) *RateLimiter {
+	return &RateLimiter{window: window, windows: make(map[string]*rateWindow)}
+}
+
+// Allow counts a request for key, failing with a *RateLimitedError past limit requests in the window
+func (l *RateLimiter) Allow(key string, limit uint, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.start.Add(l.window)) {
+		l.sweep(now)
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= limit {
+		return &RateLimitedError{RetryAfter: w.start.Add(l.window).Sub(now)}
+	}
+	w.count++
+	return nil
+}
+
+// sweep drops, once per window, the windows that already ended so idle keys don't pile up
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Before(l.lastSweep.Add(l.window)) {
+		return
+	}
+	l.lastSweep = now
+	for k, w := range l.windows {
+		if !now.Before(w.start.Add(l.window)) {
+			delete(l.windows, k)
+		}
+	}
+}
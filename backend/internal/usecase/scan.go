@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"strings"
+	"time"
+)
+
+type ScanRepo interface {
+	FindOrderByNumber(ctx context.Context, orderNumber string) (*domain.Order, error)
+	LastApplied(ctx context.Context, orderID uint) (*domain.Scan, error)
+	Apply(ctx context.Context, s *domain.Scan, stationCode string) error
+	Log(ctx context.Context, s *domain.Scan) error
+	List(ctx context.Context, orderID, stationID uint, date *time.Time) ([]domain.Scan, error)
+}
+
+// scanTransitions holds the status each scan type implies, keyed by the current order status
+var scanTransitions = map[domain.ScanType]map[domain.OrderStatus]domain.OrderStatus{
+	// parcel received into the station inventory
+	domain.ScanInbound: {
+		domain.OrderCollected:      domain.OrderInStation,
+		domain.OrderInRoute:        domain.OrderInStation,
+		domain.OrderDeliveryFailed: domain.OrderInStation,
+	},
+	// parcel unloaded from a vehicle at the station
+	domain.ScanUnload: {
+		domain.OrderCollected:      domain.OrderInStation,
+		domain.OrderInRoute:        domain.OrderInStation,
+		domain.OrderDeliveryFailed: domain.OrderInStation,
+	},
+	// parcel loaded into a vehicle: at pickup or for the delivery route
+	domain.ScanLoad: {
+		domain.OrderCreated:        domain.OrderCollected,
+		domain.OrderInStation:      domain.OrderInRoute,
+		domain.OrderDeliveryFailed: domain.OrderInRoute,
+	},
+	// parcel dispatched out of the station
+	domain.ScanOutbound: {
+		domain.OrderInStation: domain.OrderInRoute,
+	},
+}
+
+type ScanService struct {
+	repo     ScanRepo
+	stations StationRepo
+}
+
+func NewScanService(r ScanRepo, stations StationRepo) *ScanService {
+	return &ScanService{repo: r, stations: stations}
+}
+
+type ScanRequest struct {
+	Barcode   string          `json:"barcode"`
+	ScanType  domain.ScanType `json:"scan_type"`
+	StationID uint            `json:"station_id"`
+	DeviceID  string          `json:"device_id"`
+	// Vehicle route the parcel is loaded into, used by route manifests
+	RouteCode string `json:"route_code"`
+}
+
+// Record resolves the order by its number and applies the status implied by the scan.
+// Repeating the last applied scan (same type and station) returns that scan with duplicate=true
+// and changes nothing. Scans that don't fit the order status are logged and rejected with ErrConflict.
+func (s *ScanService) Record(ctx context.Context, req ScanRequest, scannedBy uint) (scan *domain.Scan, duplicate bool, err error) {
+	if scannedBy == 0 {
+		return nil, false, errors.New("scannedBy requerido")
+	}
+
+	req.Barcode = strings.TrimSpace(req.Barcode)
+	req.DeviceID = strings.TrimSpace(req.DeviceID)
+	if req.Barcode == "" || req.DeviceID == "" || req.StationID == 0 {
+		return nil, false, errors.New("barcode, station_id y device_id son requeridos")
+	}
+
+	transitions, ok := scanTransitions[req.ScanType]
+	if !ok {
+		return nil, false, fmt.Errorf("scan_type inválido: %q", req.ScanType)
+	}
+
+	station, err := s.stations.FindByID(ctx, req.StationID)
+	if err != nil || station == nil || !station.IsActive {
+		return nil, false, errors.New("estación no encontrada o inactiva")
+	}
+
+	scan = &domain.Scan{
+		Barcode:   req.Barcode,
+		ScanType:  req.ScanType,
+		StationID: station.ID,
+		DeviceID:  req.DeviceID,
+		RouteCode: strings.ToUpper(strings.TrimSpace(req.RouteCode)),
+		ScannedBy: scannedBy,
+		ScannedAt: time.Now(),
+	}
+
+	o, err := s.repo.FindOrderByNumber(ctx, req.Barcode)
+	if err != nil || o == nil {
+		scan.Result = domain.ScanRejected
+		scan.Reason = "orden no encontrada"
+		_ = s.repo.Log(ctx, scan)
+		return nil, false, ErrNotFound
+	}
+
+	last, err := s.repo.LastApplied(ctx, o.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if last != nil && last.ScanType == req.ScanType && last.StationID == station.ID &&
+		last.NewStatus != nil && *last.NewStatus == o.Status {
+		return last, true, nil
+	}
+
+	prev := o.Status
+	scan.OrderID = &o.ID
+	scan.PreviousStatus = &prev
+
+	next, ok := transitions[o.Status]
+	if !ok {
+		scan.Result = domain.ScanRejected
+		scan.Reason = fmt.Sprintf("escaneo %s no permitido en estado %s", req.ScanType, o.Status)
+		_ = s.repo.Log(ctx, scan)
+		return nil, false, fmt.Errorf("%w: %s", ErrConflict, scan.Reason)
+	}
+
+	scan.Result = domain.ScanApplied
+	scan.NewStatus = &next
+
+	if err := s.repo.Apply(ctx, scan, station.Code); err != nil {
+		return nil, false, err
+	}
+
+	return scan, false, nil
+}
+
+func (s *ScanService) List(ctx context.Context, orderID, stationID uint, date string) ([]domain.Scan, error) {
+	var day *time.Time
+	if date != "" {
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, errors.New("date debe tener formato YYYY-MM-DD")
+		}
+		day = &d
+	}
+
+	return s.repo.List(ctx, orderID, stationID, day)
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"strings"
+	"time"
+)
+
+type SessionRepo interface {
+	CreateRefresh(ctx context.Context, t *domain.RefreshToken) error
+	FindRefreshByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	RotateRefresh(ctx context.Context, id uint, next *domain.RefreshToken) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
+	DenyAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenDenied(ctx context.Context, jti string) (bool, error)
+}
+
+// DefaultRefreshTTL is how long a refresh token can be used since it was issued
+const DefaultRefreshTTL = 30 * 24 * time.Hour
+
+type SessionService struct {
+	repo SessionRepo
+	ttl  time.Duration
+}
+
+func NewSessionService(r SessionRepo, ttl time.Duration) *SessionService {
+	if ttl <= 0 {
+		ttl = DefaultRefreshTTL
+	}
+	return &SessionService{repo: r, ttl: ttl}
+}
+
+// NewTokenID returns a random URL-safe identifier, used for refresh tokens, sessions and JWT ids
+func NewTokenID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *SessionService) newRefresh(userID uint, familyID string) (*domain.RefreshToken, string, error) {
+	raw, err := NewTokenID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(s.ttl),
+	}, raw, nil
+}
+
+// Start opens a session for the user and returns its id and first refresh token
+func (s *SessionService) Start(ctx context.Context, userID uint) (familyID, refresh string, err error) {
+	familyID, err = NewTokenID()
+	if err != nil {
+		return "", "", err
+	}
+
+	t, raw, err := s.newRefresh(userID, familyID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := s.repo.CreateRefresh(ctx, t); err != nil {
+		return "", "", err
+	}
+
+	return familyID, raw, nil
+}
+
+// Rotate consumes the refresh token and returns a new one for the same session. Presenting a token
+// that was already used means it leaked: the whole session is revoked and ErrUnauthorized returned.
+func (s *SessionService) Rotate(ctx context.Context, raw string) (userID uint, familyID, refresh string, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, "", "", fmt.Errorf("%w: refresh_token requerido", ErrUnauthorized)
+	}
+
+	t, err := s.repo.FindRefreshByHash(ctx, hashToken(raw))
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	if t == nil || t.RevokedAt != nil || !time.Now().Before(t.ExpiresAt) {
+		return 0, "", "", fmt.Errorf("%w: refresh token inválido o expirado", ErrUnauthorized)
+	}
+
+	if t.UsedAt != nil {
+		if err := s.repo.RevokeFamily(ctx, t.FamilyID); err != nil {
+			return 0, "", "", err
+		}
+		return 0, "", "", fmt.Errorf("%w: refresh token reutilizado, la sesión fue revocada", ErrUnauthorized)
+	}
+
+	next, nextRaw, err := s.newRefresh(t.UserID, t.FamilyID)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	rotated, err := s.repo.RotateRefresh(ctx, t.ID, next)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	// Another request consumed the token first: treat it as reuse
+	if !rotated {
+		if err := s.repo.RevokeFamily(ctx, t.FamilyID); err != nil {
+			return 0, "", "", err
+		}
+		return 0, "", "", fmt.Errorf("%w: refresh token reutilizado, la sesión fue revocada", ErrUnauthorized)
+	}
+
+	return t.UserID, t.FamilyID, nextRaw, nil
+}
+
+// Logout revokes the session's refresh tokens and deny-lists the access token until it expires
+func (s *SessionService) Logout(ctx context.Context, familyID, jti string, accessExpiresAt time.Time) error {
+	if familyID != "" {
+		if err := s.repo.RevokeFamily(ctx, familyID); err != nil {
+			return err
+		}
+	}
+
+	if jti == "" || !time.Now().Before(accessExpiresAt) {
+		return nil
+	}
+
+	return s.repo.DenyAccessToken(ctx, jti, accessExpiresAt)
+}
+
+// IsRevoked reports whether the access token id was deny-listed; lookup errors count as revoked
+func (s *SessionService) IsRevoked(ctx context.Context, jti string) bool {
+	if jti == "" {
+		return false
+	}
+
+	denied, err := s.repo.IsAccessTokenDenied(ctx, jti)
+	return err != nil || denied
+}
+
+// IsSessionRevoked reports whether the session (refresh family) was revoked by a logout or a
+// reused refresh token, so its access tokens stop working too; lookup errors count as revoked
+func (s *SessionService) IsSessionRevoked(ctx context.Context, familyID string) bool {
+	if familyID == "" {
+		return false
+	}
+
+	revoked, err := s.repo.IsFamilyRevoked(ctx, familyID)
+	return err != nil || revoked
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"strings"
+)
+
+type StationRepo interface {
+	Create(ctx context.Context, s *domain.Station) error
+	FindByID(ctx context.Context, id uint) (*domain.Station, error)
+	FindAll(ctx context.Context, includeInactive bool) ([]domain.Station, error)
+	SetActive(ctx context.Context, id uint, active bool) error
+}
+
+type StationService struct{ repo StationRepo }
+
+func NewStationService(r StationRepo) *StationService {
+	return &StationService{repo: r}
+}
+
+func (s *StationService) Create(ctx context.Context, st *domain.Station) error {
+	st.Code = strings.ToUpper(strings.TrimSpace(st.Code))
+	st.Name = strings.TrimSpace(st.Name)
+
+	if st.Code == "" || st.Name == "" {
+		return errors.New("code y name son requeridos")
+	}
+
+	if len(st.Code) > 10 {
+		return errors.New("code no puede exceder 10 caracteres")
+	}
+
+	st.ID = 0
+	st.IsActive = true
+	return s.repo.Create(ctx, st)
+}
+
+func (s *StationService) Get(ctx context.Context, id uint) (*domain.Station, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *StationService) List(ctx context.Context, includeInactive bool) ([]domain.Station, error) {
+	return s.repo.FindAll(ctx, includeInactive)
+}
+
+func (s *StationService) ToggleActive(ctx context.Context, id uint, active bool) error {
+	if id == 0 {
+		return errors.New("id requerido")
+	}
+	return s.repo.SetActive(ctx, id, active)
+}
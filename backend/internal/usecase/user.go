@@ -1,34 +1,105 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"strings"
+	"sync"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserRepo interface {
-	Create(u *domain.User) error
-	FindByEmail(email string) (*domain.User, error)
-	FindByID(id uint) (*domain.User, error)
-	DeleteByID(id uint) error
+	Create(ctx context.Context, u *domain.User) error
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	FindByID(ctx context.Context, id uint) (*domain.User, error)
+	List(ctx context.Context, f repository.UserFilter) ([]domain.User, int64, error)
+	CountActiveByRole(ctx context.Context, role domain.Role) (int64, error)
+	Update(ctx context.Context, id uint, updates map[string]interface{}) error
+	UpdatePassword(ctx context.Context, id uint, passwordHash, keepFamily string) error
+	RevokeSessions(ctx context.Context, id uint) error
+	CountOrganizationMembers(ctx context.Context, orgID uint) (members, owners int64, err error)
+	SoftDelete(ctx context.Context, id, by uint) error
+}
+
+const (
+	DefaultUserPageSize = 20
+	MaxUserPageSize     = 100
+)
+
+// Staff accounts get a stricter password floor than the self-service registration
+const MinPrivilegedPasswordLength = 12
+
+// validatePassword applies the password policy of the role wherever a password is set: creation,
+// change and reset
+func validatePassword(role domain.Role, password string) error {
+	if role != domain.RoleClient && len(password) < MinPrivilegedPasswordLength {
+		return fmt.Errorf("el password debe tener al menos %d caracteres", MinPrivilegedPasswordLength)
+	}
+	return nil
 }
 
 type UserService struct {
-	repo UserRepo
+	repo  UserRepo
+	audit AuditRepo
 }
 
-func NewUserService(r UserRepo) *UserService {
-	return &UserService{repo: r}
+func NewUserService(r UserRepo, audit AuditRepo) *UserService {
+	return &UserService{repo: r, audit: audit}
 }
 
-func (s *UserService) Register(email, password, fullName, phone string, role domain.Role) (*domain.User, error) {
-	if email == "" || password == "" || fullName == "" {
-		return nil, errors.New("email, password y full_name requeridos")
+// Register creates a client account; it is the public sign-up, so it never grants another role
+func (s *UserService) Register(ctx context.Context, email, password, fullName, phone string) (*domain.User, error) {
+	return s.create(ctx, email, password, fullName, phone, domain.RoleClient)
+}
+
+// NewUserRequest describes an account created by an administrator
+type NewUserRequest struct {
+	Email    string      `json:"email"`
+	Password string      `json:"password"`
+	FullName string      `json:"full_name"`
+	Phone    string      `json:"phone"`
+	Role     domain.Role `json:"role"`
+}
+
+// CreatePrivileged creates an account with any role on behalf of an administrator holding
+// users.manage, and records who did it in the audit log
+func (s *UserService) CreatePrivileged(ctx context.Context, actor domain.Principal, ip string, req NewUserRequest) (*domain.User, error) {
+	if !actor.Can(domain.PermUsersManage) {
+		return nil, ErrForbidden
+	}
+	if !req.Role.Valid() {
+		return nil, fmt.Errorf("rol desconocido: %q", req.Role)
+	}
+	if err := validatePassword(req.Role, req.Password); err != nil {
+		return nil, err
+	}
+
+	u, err := s.create(ctx, req.Email, req.Password, req.FullName, req.Phone, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	actorID := actor.UserID
+	if err := s.audit.Create(ctx, &domain.AuditLog{
+		Action:  domain.AuditUserCreate,
+		ActorID: &actorID,
+		Subject: fmt.Sprintf("user:%d", u.ID),
+		IP:      ip,
+		Details: fmt.Sprintf("rol %s, email %s", u.Role, u.Email),
+	}); err != nil {
+		return nil, err
 	}
 
-	if role != domain.RoleClient && role != domain.RoleAdmin {
-		role = domain.RoleClient
+	return u, nil
+}
+
+func (s *UserService) create(ctx context.Context, email, password, fullName, phone string, role domain.Role) (*domain.User, error) {
+	if email == "" || password == "" || fullName == "" {
+		return nil, errors.New("email, password y full_name requeridos")
 	}
 
 	// Hash password before storing
@@ -39,34 +110,294 @@ func (s *UserService) Register(email, password, fullName, phone string, role dom
 	}
 	u := &domain.User{Email: email, Password: string(hash), FullName: fullName, Phone: phone, Role: role, IsActive: true}
 
-	if err := s.repo.Create(u); err != nil {
+	if err := s.repo.Create(ctx, u); err != nil {
 		return nil, err
 	}
 
 	return u, nil
 }
 
-func (s *UserService) Delete(id uint) error {
-	return s.repo.DeleteByID(id)
+// UserQuery filters and paginates a user listing; Page starts at 1
+type UserQuery struct {
+	Query    string
+	Role     domain.Role
+	Active   *bool
+	Page     int
+	PageSize int
 }
 
-func (s *UserService) Authenticate(email, password string) (*domain.User, error) {
+type UserPage struct {
+	Items    []domain.User `json:"items"`
+	Total    int64         `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+}
+
+func (s *UserService) List(ctx context.Context, q UserQuery) (*UserPage, error) {
+	if q.Role != "" && !q.Role.Valid() {
+		return nil, fmt.Errorf("rol desconocido: %q", q.Role)
+	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = DefaultUserPageSize
+	}
+	if q.PageSize > MaxUserPageSize {
+		q.PageSize = MaxUserPageSize
+	}
+
+	items, total, err := s.repo.List(ctx, repository.UserFilter{
+		Query:  q.Query,
+		Role:   q.Role,
+		Active: q.Active,
+		Offset: (q.Page - 1) * q.PageSize,
+		Limit:  q.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []domain.User{}
+	}
+
+	return &UserPage{Items: items, Total: total, Page: q.Page, PageSize: q.PageSize}, nil
+}
+
+// UserUpdate lists the fields to change; nil fields are left as they are
+type UserUpdate struct {
+	FullName *string      `json:"full_name"`
+	Phone    *string      `json:"phone"`
+	Role     *domain.Role `json:"role"`
+	IsActive *bool        `json:"is_active"`
+}
+
+// keepsAdmin fails with ErrConflict when taking u out of the admins would leave no active admin
+func (s *UserService) keepsAdmin(ctx context.Context, u *domain.User) error {
+	if u.Role != domain.RoleAdmin || !u.IsActive {
+		return nil
+	}
+	n, err := s.repo.CountActiveByRole(ctx, domain.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if n <= 1 {
+		return fmt.Errorf("%w: debe quedar al menos un administrador activo", ErrConflict)
+	}
+	return nil
+}
+
+// Update changes a user's profile. Users may edit their own name and phone; role and active flag
+// need users.manage, and nobody changes their own role or deactivates themself.
+func (s *UserService) Update(ctx context.Context, actor domain.Principal, ip string, id uint, req UserUpdate) (*domain.User, error) {
+	manage := actor.Can(domain.PermUsersManage)
+	self := actor.UserID == id
+	if !manage && !self {
+		return nil, ErrForbidden
+	}
+
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil || u == nil {
+		return nil, ErrNotFound
+	}
+
+	updates := map[string]interface{}{}
+	if req.FullName != nil {
+		name := strings.TrimSpace(*req.FullName)
+		if name == "" {
+			return nil, errors.New("full_name no puede estar vacío")
+		}
+		updates["full_name"] = name
+	}
+	if req.Phone != nil {
+		updates["phone"] = strings.TrimSpace(*req.Phone)
+	}
+
+	demoted := false
+	if req.Role != nil && *req.Role != u.Role {
+		if !manage || self {
+			return nil, fmt.Errorf("%w: no puede cambiar su propio rol", ErrForbidden)
+		}
+		if !req.Role.Valid() {
+			return nil, fmt.Errorf("rol desconocido: %q", *req.Role)
+		}
+		updates["role"] = *req.Role
+		demoted = true
+	}
+	if req.IsActive != nil && *req.IsActive != u.IsActive {
+		if !manage || self {
+			return nil, fmt.Errorf("%w: no puede cambiar su propio estado", ErrForbidden)
+		}
+		updates["is_active"] = *req.IsActive
+		demoted = demoted || !*req.IsActive
+	}
+	if demoted {
+		if err := s.keepsAdmin(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(updates) == 0 {
+		return u, nil
+	}
+	updates["updated_by"] = actor.UserID
+
+	if err := s.repo.Update(ctx, id, updates); err != nil {
+		return nil, err
+	}
+	if role, ok := updates["role"]; ok {
+		actorID := actor.UserID
+		if err := s.audit.Create(ctx, &domain.AuditLog{
+			Action:  domain.AuditUserRoleChange,
+			ActorID: &actorID,
+			Subject: fmt.Sprintf("user:%d", id),
+			IP:      ip,
+			Details: fmt.Sprintf("rol %s => %s", u.Role, role),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if active, ok := updates["is_active"]; ok && active == false {
+		if err := s.repo.RevokeSessions(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.FindByID(ctx, id)
+}
+
+// ChangePassword sets a new password once the current one is confirmed. The user's other
+// sessions are ended; sessionID, the one making the change, stays signed in.
+func (s *UserService) ChangePassword(ctx context.Context, userID uint, sessionID, current, password string) error {
+	if current == "" || password == "" {
+		return errors.New("current_password y new_password requeridos")
+	}
+
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil || u == nil {
+		return ErrNotFound
+	}
+	// FindByID leaves the hash out
+	full, err := s.repo.FindByEmail(ctx, u.Email)
+	if err != nil || full == nil {
+		return ErrNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(full.Password), []byte(current)); err != nil {
+		return fmt.Errorf("%w: la contraseña actual no es correcta", ErrForbidden)
+	}
+	if err := validatePassword(full.Role, password); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("no se pudo encriptar el password")
+	}
+
+	return s.repo.UpdatePassword(ctx, userID, string(hash), sessionID)
+}
+
+// ForceLogout ends every session of the user: refresh tokens stop working and access tokens
+// already issued are rejected
+func (s *UserService) ForceLogout(ctx context.Context, actor domain.Principal, id uint) error {
+	if !actor.Can(domain.PermUsersManage) && actor.UserID != id {
+		return ErrForbidden
+	}
+	if u, err := s.repo.FindByID(ctx, id); err != nil || u == nil {
+		return ErrNotFound
+	}
+	return s.repo.RevokeSessions(ctx, id)
+}
+
+// Delete removes an account: the user and their personal addresses are anonymized and the user is
+// soft deleted, keeping the rows that orders and history refer to. It needs users.delete; users who
+// want their own account gone file an erasure request that an administrator approves. The last
+// active admin and the last owner of an organization with other members stay.
+func (s *UserService) Delete(ctx context.Context, actor domain.Principal, ip string, id uint) error {
+	if !actor.Can(domain.PermUsersDelete) {
+		return ErrForbidden
+	}
+
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil || u == nil {
+		return ErrNotFound
+	}
+	if err := s.deletable(ctx, u); err != nil {
+		return err
+	}
+
+	if err := s.repo.SoftDelete(ctx, id, actor.UserID); err != nil {
+		return err
+	}
+
+	actorID := actor.UserID
+	return s.audit.Create(ctx, &domain.AuditLog{
+		Action:  domain.AuditUserDelete,
+		ActorID: &actorID,
+		Subject: fmt.Sprintf("user:%d", id),
+		IP:      ip,
+		Details: fmt.Sprintf("rol %s", u.Role),
+	})
+}
+
+// deletable fails with ErrConflict when removing u would leave no active admin, or an organization
+// with members but no owner
+func (s *UserService) deletable(ctx context.Context, u *domain.User) error {
+	if err := s.keepsAdmin(ctx, u); err != nil {
+		return err
+	}
+	if u.OrganizationID != nil && u.OrgRole == domain.OrgOwner {
+		members, owners, err := s.repo.CountOrganizationMembers(ctx, *u.OrganizationID)
+		if err != nil {
+			return err
+		}
+		if owners <= 1 && members > 1 {
+			return fmt.Errorf("%w: transfiera la organización a otro owner antes de eliminar la cuenta", ErrConflict)
+		}
+	}
+	return nil
+}
+
+// ErrInvalidCredentials is the only error Authenticate returns for a failed login, whatever the cause
+var ErrInvalidCredentials = errors.New("credenciales inválidas")
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// compareDummy spends the same bcrypt time as a real check, so unknown emails can't be told
+// apart by response time
+func compareDummy(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+func (s *UserService) Authenticate(ctx context.Context, email, password string) (*domain.User, error) {
 	if email == "" || password == "" {
-		return nil, errors.New("email and password required")
+		return nil, ErrInvalidCredentials
 	}
 
-	u, err := s.repo.FindByEmail(email)
+	u, err := s.repo.FindByEmail(ctx, email)
 	if err != nil || u == nil {
-		return nil, errors.New("invalid credentials")
+		compareDummy(password)
+		return nil, ErrInvalidCredentials
 	}
 
 	// Compare provided password with stored bcrypt hash
 	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
-		return nil, errors.New("invalid password")
+		return nil, ErrInvalidCredentials
+	}
+	// Deactivated accounts get the same answer as a wrong password, so the login counts it as a failure
+	if !u.IsActive {
+		return nil, ErrInvalidCredentials
 	}
 	return u, nil
 }
 
-func (s *UserService) GetByID(id uint) (*domain.User, error) {
-	return s.repo.FindByID(id)
+func (s *UserService) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	return s.repo.FindByID(ctx, id)
 }
@@ -0,0 +1,321 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"logistics-app/backend/internal/usecase"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type mockUserRepo struct {
+	users []domain.User
+}
+
+func (m *mockUserRepo) Create(ctx context.Context, u *domain.User) error {
+	u.ID = uint(len(m.users) + 1)
+	m.users = append(m.users, *u)
+	return nil
+}
+
+func (m *mockUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for i := range m.users {
+		if m.users[i].Email == email && !m.users[i].DeletedAt.Valid {
+			return &m.users[i], nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (m *mockUserRepo) FindByID(ctx context.Context, id uint) (*domain.User, error) {
+	for i := range m.users {
+		if m.users[i].ID == id && !m.users[i].DeletedAt.Valid {
+			return &m.users[i], nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (m *mockUserRepo) List(ctx context.Context, f repository.UserFilter) ([]domain.User, int64, error) {
+	var matched []domain.User
+	for _, u := range m.users {
+		if f.Query != "" && !strings.Contains(strings.ToLower(u.Email+" "+u.FullName), strings.ToLower(f.Query)) {
+			continue
+		}
+		if (f.Role != "" && u.Role != f.Role) || (f.Active != nil && u.IsActive != *f.Active) {
+			continue
+		}
+		matched = append(matched, u)
+	}
+	total := int64(len(matched))
+	if f.Offset >= len(matched) {
+		return nil, total, nil
+	}
+	matched = matched[f.Offset:]
+	if len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	return matched, total, nil
+}
+
+func (m *mockUserRepo) CountActiveByRole(ctx context.Context, role domain.Role) (int64, error) {
+	var n int64
+	for _, u := range m.users {
+		if u.Role == role && u.IsActive {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *mockUserRepo) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	u, err := m.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	for k, v := range updates {
+		switch k {
+		case "full_name":
+			u.FullName = v.(string)
+		case "phone":
+			u.Phone = v.(string)
+		case "role":
+			u.Role = v.(domain.Role)
+		case "is_active":
+			u.IsActive = v.(bool)
+		}
+	}
+	return nil
+}
+
+func (m *mockUserRepo) UpdatePassword(ctx context.Context, id uint, passwordHash, keepFamily string) error {
+	u, err := m.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.Password = passwordHash
+	return nil
+}
+
+func (m *mockUserRepo) RevokeSessions(ctx context.Context, id uint) error {
+	u, err := m.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	u.SessionsRevokedAt = &now
+	return nil
+}
+
+func (m *mockUserRepo) CountOrganizationMembers(ctx context.Context, orgID uint) (members, owners int64, err error) {
+	for _, u := range m.users {
+		if u.OrganizationID != nil && *u.OrganizationID == orgID && !u.DeletedAt.Valid {
+			members++
+			if u.OrgRole == domain.OrgOwner {
+				owners++
+			}
+		}
+	}
+	return members, owners, nil
+}
+
+func (m *mockUserRepo) SoftDelete(ctx context.Context, id, by uint) error {
+	if err := m.RevokeSessions(ctx, id); err != nil {
+		return err
+	}
+	u, _ := m.FindByID(ctx, id)
+	u.Email = domain.DeletedUserEmail(id)
+	u.FullName = domain.DeletedUserName
+	u.Phone, u.Password = "", ""
+	u.OrganizationID, u.OrgRole = nil, ""
+	u.IsActive = false
+	u.UpdatedBy = &by
+	u.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return nil
+}
+
+type mockUserTokenRepo struct {
+	users  *mockUserRepo
+	tokens []domain.UserToken
+}
+
+func (m *mockUserTokenRepo) Create(ctx context.Context, t *domain.UserToken) error {
+	t.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, *t)
+	return nil
+}
+
+func (m *mockUserTokenRepo) FindByHash(ctx context.Context, hash string) (*domain.UserToken, error) {
+	for i := range m.tokens {
+		if m.tokens[i].TokenHash == hash {
+			t := m.tokens[i]
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockUserTokenRepo) use(tokenID uint) {
+	now := time.Now()
+	m.tokens[tokenID-1].UsedAt = &now
+}
+
+func (m *mockUserTokenRepo) ResetPassword(ctx context.Context, tokenID, userID uint, passwordHash string) error {
+	m.use(tokenID)
+	u, _ := m.users.FindByID(ctx, userID)
+	u.Password = passwordHash
+	return nil
+}
+
+func (m *mockUserTokenRepo) VerifyEmail(ctx context.Context, tokenID, userID uint) error {
+	m.use(tokenID)
+	u, _ := m.users.FindByID(ctx, userID)
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	return nil
+}
+
+type mockMailer struct {
+	sent []string
+}
+
+func (m *mockMailer) Send(to, subject, body string) error {
+	m.sent = append(m.sent, body)
+	return nil
+}
+
+var mailToken = regexp.MustCompile(`token=(\S+)`)
+
+func lastMailToken(t *testing.T, m *mockMailer) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("Expected an email to be sent")
+	}
+	match := mailToken.FindStringSubmatch(m.sent[len(m.sent)-1])
+	if match == nil {
+		t.Fatal("Expected the email to contain a token link")
+	}
+	return match[1]
+}
+
+func TestAccountService_ResetPassword_SingleUse(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{{ID: 1, Email: "ana@example.com", FullName: "Ana", Role: domain.RoleClient, IsActive: true, Password: "old"}}}
+	mailer := &mockMailer{}
+	service := usecase.NewAccountService(users, &mockUserTokenRepo{users: users}, mailer, usecase.AccountConfig{BaseURL: "http://app.test/"})
+	if err := service.RequestPasswordReset(context.Background(), "ana@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	token := lastMailToken(t, mailer)
+
+	// Act
+	err := service.ResetPassword(context.Background(), token, "nueva-clave")
+	reuseErr := service.ResetPassword(context.Background(), token, "otra-clave")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if users.users[0].Password == "old" || users.users[0].Password == "nueva-clave" {
+		t.Error("Expected the new password to be stored hashed")
+	}
+
+	if !errors.Is(reuseErr, usecase.ErrUnprocessable) {
+		t.Errorf("Expected ErrUnprocessable on reuse, got %v", reuseErr)
+	}
+}
+
+func TestAccountService_ResetPassword_PrivilegedFloor(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{{ID: 1, Email: "ana@example.com", FullName: "Ana", Role: domain.RoleDispatcher, IsActive: true, Password: "old"}}}
+	mailer := &mockMailer{}
+	service := usecase.NewAccountService(users, &mockUserTokenRepo{users: users}, mailer, usecase.AccountConfig{BaseURL: "http://app.test/"})
+	if err := service.RequestPasswordReset(context.Background(), "ana@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	token := lastMailToken(t, mailer)
+
+	// Act
+	short := service.ResetPassword(context.Background(), token, "corta")
+	err := service.ResetPassword(context.Background(), token, "una-clave-larga")
+
+	// Assert
+	if short == nil || !strings.Contains(short.Error(), "12 caracteres") {
+		t.Errorf("Expected the privileged floor to apply on reset, got %v", short)
+	}
+
+	if err != nil {
+		t.Errorf("Expected the link to still work after a rejected password, got %v", err)
+	}
+}
+
+func TestAccountService_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{{ID: 1, Email: "ana@example.com", FullName: "Ana", Role: domain.RoleClient, IsActive: true, Password: "old"}}}
+	mailer := &mockMailer{}
+	service := usecase.NewAccountService(users, &mockUserTokenRepo{users: users}, mailer, usecase.AccountConfig{BaseURL: "http://app.test/"})
+
+	// Act
+	err := service.RequestPasswordReset(context.Background(), "nadie@example.com")
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error for unknown email, got %v", err)
+	}
+
+	if len(mailer.sent) != 0 {
+		t.Error("Expected no email to be sent")
+	}
+}
+
+func TestAccountService_VerifyEmail_UnlocksLogin(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{{ID: 1, Email: "ana@example.com", FullName: "Ana", Role: domain.RoleClient, IsActive: true, Password: "old"}}}
+	mailer := &mockMailer{}
+	service := usecase.NewAccountService(users, &mockUserTokenRepo{users: users}, mailer, usecase.AccountConfig{
+		BaseURL:              "http://app.test/",
+		RequireVerifiedEmail: true,
+	})
+	u := &users.users[0]
+	if err := service.CanLogin(u); !errors.Is(err, usecase.ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden before verification, got %v", err)
+	}
+	if err := service.SendVerification(context.Background(), u); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Act
+	err := service.VerifyEmail(context.Background(), lastMailToken(t, mailer))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := service.CanLogin(u); err != nil {
+		t.Errorf("Expected login to be allowed after verification, got %v", err)
+	}
+}
+
+func TestAccountService_VerifyEmail_RejectsResetToken(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{{ID: 1, Email: "ana@example.com", FullName: "Ana", Role: domain.RoleClient, IsActive: true, Password: "old"}}}
+	mailer := &mockMailer{}
+	service := usecase.NewAccountService(users, &mockUserTokenRepo{users: users}, mailer, usecase.AccountConfig{BaseURL: "http://app.test/"})
+	_ = service.RequestPasswordReset(context.Background(), "ana@example.com")
+
+	// Act
+	err := service.VerifyEmail(context.Background(), lastMailToken(t, mailer))
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUnprocessable) {
+		t.Errorf("Expected ErrUnprocessable, got %v", err)
+	}
+}
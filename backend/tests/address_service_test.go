@@ -1,10 +1,12 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"logistics-app/backend/internal/domain"
 	"logistics-app/backend/internal/repository"
 	"logistics-app/backend/internal/usecase"
+	"strings"
 	"testing"
 	"time"
 )
@@ -18,7 +20,7 @@ type mockAddressRepo struct {
 	lastCoordID uint
 }
 
-func (m *mockAddressRepo) CreateWithCoordinates(customerID uint, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
+func (m *mockAddressRepo) CreateWithCoordinates(ctx context.Context, owner domain.Scope, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
 	if m.shouldFail {
 		return nil, nil, m.failError
 	}
@@ -27,10 +29,12 @@ func (m *mockAddressRepo) CreateWithCoordinates(customerID uint, payload reposit
 	m.lastAddrID++
 	addr := payload.Address
 	addr.ID = m.lastAddrID
-	addr.CustomerID = customerID
+	addr.CustomerID = owner.UserID
+	addr.OrganizationID = owner.OrganizationID
 	addr.CreatedAt = time.Now()
 	addr.UpdatedAt = time.Now()
 	addr.IsActive = true
+	addr.Version = 1
 
 	var coords *domain.Coordinates
 	if payload.Coordinates != nil {
@@ -47,36 +51,54 @@ func (m *mockAddressRepo) CreateWithCoordinates(customerID uint, payload reposit
 	return &addr, coords, nil
 }
 
-func (m *mockAddressRepo) UpdateWithCoordinates(requesterID uint, isAdmin bool, id uint, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
+func (m *mockAddressRepo) UpdateWithCoordinates(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, payload repository.AddressWithCoords) (*domain.Address, *domain.Coordinates, error) {
 	// Mock implementation for completeness
 	return nil, nil, errors.New("not implemented in mock")
 }
 
-func (m *mockAddressRepo) FindByID(requesterID uint, isAdmin bool, id uint) (*domain.Address, error) {
-	// Mock implementation for completeness
-	return nil, errors.New("not implemented in mock")
+func (m *mockAddressRepo) FindByID(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) (*domain.Address, error) {
+	for i, addr := range m.addresses {
+		if addr.ID == id && (isAdmin || scope.Sees(addr.CustomerID, addr.OrganizationID)) {
+			return &m.addresses[i], nil
+		}
+	}
+	return nil, errors.New("address not found")
 }
 
-func (m *mockAddressRepo) List(requesterID uint, isAdmin bool, includeInactive bool) ([]domain.Address, error) {
+func (m *mockAddressRepo) List(ctx context.Context, scope domain.Scope, isAdmin bool, includeInactive bool) ([]domain.Address, error) {
 	// Mock implementation for completeness
 	return nil, errors.New("not implemented in mock")
 }
 
-func (m *mockAddressRepo) ToggleActive(requesterID uint, isAdmin bool, id uint, active bool) error {
+func (m *mockAddressRepo) ToggleActive(ctx context.Context, scope domain.Scope, isAdmin bool, id uint, version uint, active bool) error {
 	for i, addr := range m.addresses {
-		if addr.ID == id && (isAdmin || addr.CustomerID == requesterID) {
+		if addr.ID == id && (isAdmin || scope.Sees(addr.CustomerID, addr.OrganizationID)) {
+			if version != 0 && addr.Version != version {
+				return repository.ErrStaleVersion
+			}
 			m.addresses[i].IsActive = active
+			m.addresses[i].Version++
 			return nil
 		}
 	}
 	return errors.New("address not found")
 }
 
-func (m *mockAddressRepo) Delete(requesterID uint, isAdmin bool, id uint) error {
+func (m *mockAddressRepo) Delete(ctx context.Context, scope domain.Scope, isAdmin bool, id uint) error {
 	// Mock implementation for completeness
 	return errors.New("not implemented in mock")
 }
 
+func (m *mockAddressRepo) FindMatch(ctx context.Context, scope domain.Scope, a domain.Address) (*domain.Address, error) {
+	for i, addr := range m.addresses {
+		if scope.Sees(addr.CustomerID, addr.OrganizationID) && addr.IsActive && strings.EqualFold(addr.Street, strings.TrimSpace(a.Street)) &&
+			addr.ExteriorNumber == a.ExteriorNumber && addr.PostalCode == a.PostalCode {
+			return &m.addresses[i], nil
+		}
+	}
+	return nil, nil
+}
+
 func TestAddressService_Create_Success(t *testing.T) {
 	// Arrange
 	mockRepo := &mockAddressRepo{}
@@ -102,7 +124,7 @@ func TestAddressService_Create_Success(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err != nil {
@@ -160,7 +182,7 @@ func TestAddressService_Create_WithoutCoordinates(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err != nil {
@@ -196,7 +218,7 @@ func TestAddressService_Create_MissingCustomerID(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(0, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: 0}, req)
 
 	// Assert
 	if err == nil {
@@ -229,7 +251,7 @@ func TestAddressService_Create_MissingStreet(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err == nil {
@@ -258,7 +280,7 @@ func TestAddressService_Create_MissingCity(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err == nil {
@@ -287,7 +309,7 @@ func TestAddressService_Create_MissingState(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err == nil {
@@ -324,7 +346,7 @@ func TestAddressService_Create_InvalidLatitudeMin(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err == nil {
@@ -361,7 +383,7 @@ func TestAddressService_Create_InvalidLatitudeMax(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err == nil {
@@ -398,7 +420,7 @@ func TestAddressService_Create_InvalidLongitudeMin(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err == nil {
@@ -435,7 +457,7 @@ func TestAddressService_Create_InvalidLongitudeMax(t *testing.T) {
 	}
 
 	// Act
-	addr, coords, err := service.Create(customerID, req)
+	addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 	// Assert
 	if err == nil {
@@ -486,7 +508,7 @@ func TestAddressService_Create_ValidCoordinatesAtBoundaries(t *testing.T) {
 			}
 
 			// Act
-			addr, coords, err := service.Create(customerID, req)
+			addr, coords, err := service.Create(context.Background(), domain.Scope{UserID: customerID}, req)
 
 			// Assert
 			if err != nil {
@@ -511,3 +533,35 @@ func TestAddressService_Create_ValidCoordinatesAtBoundaries(t *testing.T) {
 		})
 	}
 }
+
+func TestAddressService_ToggleActive_StaleVersion(t *testing.T) {
+	// Arrange
+	mockRepo := &mockAddressRepo{}
+	service := usecase.NewAddressService(mockRepo)
+	addr, _, err := service.Create(context.Background(), domain.Scope{UserID: 1}, usecase.AddressRequest{Street: "Calle 5", City: "Puebla", State: "Puebla", Country: "México"})
+	if err != nil {
+		t.Fatalf("Expected no error creating address, got %v", err)
+	}
+	current := addr.Version
+
+	// Act
+	version, err := service.ToggleActive(context.Background(), domain.Scope{UserID: 1}, false, addr.ID, current, false)
+	_, staleErr := service.ToggleActive(context.Background(), domain.Scope{UserID: 1}, false, addr.ID, current, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if version != current+1 {
+		t.Errorf("Expected version %d, got %d", current+1, version)
+	}
+
+	if !errors.Is(staleErr, usecase.ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed, got %v", staleErr)
+	}
+
+	if mockRepo.addresses[0].IsActive {
+		t.Error("Expected stale update to leave the address inactive")
+	}
+}
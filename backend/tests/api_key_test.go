@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpdelivery "logistics-app/backend/internal/delivery/http"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/signing"
+	"logistics-app/backend/internal/repository"
+	"logistics-app/backend/internal/usecase"
+)
+
+type mockAPIKeyRepo struct {
+	keys []domain.APIKey
+}
+
+func (m *mockAPIKeyRepo) Create(ctx context.Context, k *domain.APIKey) error {
+	k.ID = uint(len(m.keys) + 1)
+	m.keys = append(m.keys, *k)
+	return nil
+}
+
+func (m *mockAPIKeyRepo) ListByOrganization(ctx context.Context, orgID uint) ([]domain.APIKey, error) {
+	var list []domain.APIKey
+	for _, k := range m.keys {
+		if k.OrganizationID == orgID {
+			list = append(list, k)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockAPIKeyRepo) FindByID(ctx context.Context, id uint) (*domain.APIKey, error) {
+	if id == 0 || int(id) > len(m.keys) {
+		return nil, errors.New("record not found")
+	}
+	k := m.keys[id-1]
+	return &k, nil
+}
+
+func (m *mockAPIKeyRepo) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	for i := range m.keys {
+		if m.keys[i].KeyHash == hash {
+			k := m.keys[i]
+			return &k, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAPIKeyRepo) Rotate(ctx context.Context, old *domain.APIKey, oldUntil *time.Time, next *domain.APIKey) error {
+	stored := &m.keys[old.ID-1]
+	if stored.RevokedAt != nil {
+		return repository.ErrTokenUsed
+	}
+	for _, k := range m.keys {
+		if k.RotatedFromID != nil && *k.RotatedFromID == old.ID {
+			return repository.ErrTokenUsed
+		}
+	}
+	if oldUntil != nil {
+		stored.ExpiresAt = oldUntil
+	} else {
+		now := time.Now()
+		stored.RevokedAt = &now
+	}
+	return m.Create(ctx, next)
+}
+
+func (m *mockAPIKeyRepo) Revoke(ctx context.Context, id uint) error {
+	now := time.Now()
+	m.keys[id-1].RevokedAt = &now
+	return nil
+}
+
+func (m *mockAPIKeyRepo) Touch(ctx context.Context, id uint, now time.Time) error {
+	m.keys[id-1].LastUsedAt = &now
+	return nil
+}
+
+var keyOrg = uint(5)
+
+func ownerScope() domain.Scope {
+	return domain.Scope{UserID: 1, OrganizationID: &keyOrg, OrgRole: domain.OrgOwner}
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	// Arrange
+	repo := &mockAPIKeyRepo{}
+	service := usecase.NewAPIKeyService(repo, 0)
+	shipper := domain.Scope{UserID: 2, OrganizationID: &keyOrg, OrgRole: domain.OrgShipper}
+
+	// Act
+	k, raw, err := service.Create(context.Background(), ownerScope(), usecase.APIKeyRequest{Name: "ERP", Permissions: []domain.Permission{domain.PermOrdersCreate}})
+	_, _, staffPerm := service.Create(context.Background(), ownerScope(), usecase.APIKeyRequest{Name: "ERP", Permissions: []domain.Permission{domain.PermOrdersReadAll}})
+	_, _, notOwner := service.Create(context.Background(), shipper, usecase.APIKeyRequest{Name: "ERP"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(raw) < 40 || raw[:3] != domain.APIKeyPrefix || k.Prefix != raw[:11] {
+		t.Errorf("Unexpected key %q with prefix %q", raw, k.Prefix)
+	}
+
+	if repo.keys[0].KeyHash == raw || repo.keys[0].KeyHash == "" {
+		t.Error("Expected the key to be stored hashed")
+	}
+
+	if staffPerm == nil {
+		t.Error("Expected staff permissions to be rejected")
+	}
+
+	if !errors.Is(notOwner, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a shipper, got %v", notOwner)
+	}
+}
+
+func TestAPIKeyService_RotateAndRevoke(t *testing.T) {
+	// Arrange
+	repo := &mockAPIKeyRepo{}
+	service := usecase.NewAPIKeyService(repo, 0)
+	old, oldRaw, _ := service.Create(context.Background(), ownerScope(), usecase.APIKeyRequest{Name: "ERP"})
+
+	// Act
+	next, nextRaw, err := service.Rotate(context.Background(), ownerScope(), old.ID, time.Hour)
+	_, oldErr := service.Authenticate(context.Background(), oldRaw)
+	_, _, again := service.Rotate(context.Background(), ownerScope(), old.ID, 0)
+	revokeErr := service.Revoke(context.Background(), ownerScope(), next.ID)
+	_, nextErr := service.Authenticate(context.Background(), nextRaw)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if next.RotatedFromID == nil || *next.RotatedFromID != old.ID || nextRaw == oldRaw {
+		t.Errorf("Expected a new key replacing %d, got %+v", old.ID, next)
+	}
+
+	if oldErr != nil {
+		t.Errorf("Expected the old key to work during the grace period, got %v", oldErr)
+	}
+
+	if again == nil {
+		t.Error("Expected a second rotation of the same key to fail")
+	}
+
+	if revokeErr != nil || !errors.Is(nextErr, usecase.ErrUnauthorized) {
+		t.Errorf("Expected a revoked key to be rejected, got %v / %v", revokeErr, nextErr)
+	}
+}
+
+func TestAPIKeyService_RateLimit(t *testing.T) {
+	// Arrange
+	repo := &mockAPIKeyRepo{}
+	service := usecase.NewAPIKeyService(repo, 0)
+	_, raw, _ := service.Create(context.Background(), ownerScope(), usecase.APIKeyRequest{Name: "ERP", RateLimit: 2})
+
+	// Act
+	var errs []error
+	for i := 0; i < 3; i++ {
+		_, err := service.Authenticate(context.Background(), raw)
+		errs = append(errs, err)
+	}
+
+	// Assert
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("Expected the first requests to pass, got %v", errs)
+	}
+
+	var limited *usecase.RateLimitedError
+	if !errors.As(errs[2], &limited) || limited.RetryAfter <= 0 {
+		t.Errorf("Expected a RateLimitedError, got %v", errs[2])
+	}
+
+	if repo.keys[0].LastUsedAt == nil {
+		t.Error("Expected the last use to be recorded")
+	}
+}
+
+func TestRequireAuth_APIKey(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+	users.users = append(users.users, domain.User{ID: 3, Email: "owner@example.com", Role: domain.RoleClient, IsActive: true, OrganizationID: &keyOrg, OrgRole: domain.OrgOwner})
+	h.APIKeys = usecase.NewAPIKeyService(&mockAPIKeyRepo{}, 0)
+	owner := domain.Scope{UserID: 3, OrganizationID: &keyOrg, OrgRole: domain.OrgOwner}
+	_, raw, err := h.APIKeys.Create(context.Background(), owner, usecase.APIKeyRequest{Name: "ERP", Permissions: []domain.Permission{domain.PermOrdersCreate}, RateLimit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, p := serve(h, "Bearer "+raw)
+	if code != http.StatusOK || p == nil {
+		t.Fatalf("expected principal, got %d", code)
+	}
+	if p.UserID != 3 || p.APIKeyID == 0 || !p.Can(domain.PermOrdersCreate) || p.Can(domain.PermAddressesCreate) {
+		t.Fatalf("unexpected principal %+v", p)
+	}
+	if s := p.Scope(); !s.OrganizationOnly || s.Sees(3, nil) || !s.Writable().Sees(4, &keyOrg) {
+		t.Fatalf("expected the key to reach only the organization's rows, got %+v", s)
+	}
+
+	// account routes need the user's session
+	req := httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+	req.Header.Set("X-API-Key", raw)
+	rec := httptest.NewRecorder()
+	h.RequireAuth(h.RequireSession(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))).ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 on a session route, got %d", rec.Code)
+	}
+
+	if code, _ := serve(h, "Bearer "+raw); code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 past the rate limit, got %d", code)
+	}
+	if code, _ := serve(h, "Bearer "+domain.APIKeyPrefix+"unknown"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unknown key, got %d", code)
+	}
+}
+
+func TestAPIKey_PermissionsScopeHandlers(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+	users.users = append(users.users, domain.User{ID: 3, Email: "owner@example.com", Role: domain.RoleClient, IsActive: true, OrganizationID: &keyOrg, OrgRole: domain.OrgOwner})
+	h.APIKeys = usecase.NewAPIKeyService(&mockAPIKeyRepo{}, 0)
+	owner := domain.Scope{UserID: 3, OrganizationID: &keyOrg, OrgRole: domain.OrgOwner}
+	// the owner's role holds orders.create, the key doesn't
+	_, raw, err := h.APIKeys.Create(context.Background(), owner, usecase.APIKeyRequest{Name: "ERP", Permissions: []domain.Permission{domain.PermAddressesCreate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := map[string]struct {
+		method, path string
+		handler      http.HandlerFunc
+	}{
+		"edit order":  {http.MethodPatch, "/api/orders/1", h.EditOrder},
+		"book pickup": {http.MethodPost, "/api/pickups", h.BookPickup},
+	}
+	for name, route := range routes {
+		req := httptest.NewRequest(route.method, route.path, strings.NewReader(`{}`))
+		req.Header.Set("X-API-Key", raw)
+		rec := httptest.NewRecorder()
+		h.RequireAuth(route.handler).ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403 for a key without orders.create, got %d", name, rec.Code)
+		}
+	}
+}
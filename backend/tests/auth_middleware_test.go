@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpdelivery "logistics-app/backend/internal/delivery/http"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/signing"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func bearer(t *testing.T, keys *signing.KeySet, c jwt.MapClaims) string {
+	t.Helper()
+	if _, ok := c["exp"]; !ok {
+		c["exp"] = time.Now().Add(time.Minute).Unix()
+	}
+	tok, err := keys.Sign(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + tok
+}
+
+// serve runs a request through RequireAuth and returns the status and the principal the handler saw
+func serve(h *httpdelivery.Handler, authorization string) (int, *domain.Principal) {
+	var seen *domain.Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = domain.PrincipalFrom(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	h.RequireAuth(next).ServeHTTP(rec, req)
+	return rec.Code, seen
+}
+
+func TestRequireAuth_StoresPrincipal(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+
+	// the role comes from the user, not from the token
+	code, p := serve(h, bearer(t, h.Keys, jwt.MapClaims{"uid": 1, "role": "admin", "jti": "abc", "sid": "s1"}))
+	if code != http.StatusOK || p == nil {
+		t.Fatalf("expected principal, got %d", code)
+	}
+	if p.UserID != 1 || p.Role != domain.RoleDispatcher || p.TokenID != "abc" || p.SessionID != "s1" {
+		t.Fatalf("unexpected principal %+v", p)
+	}
+	if !p.Can(domain.PermOrdersStatusUpdate) || p.Can(domain.PermUsersDelete) {
+		t.Fatal("permissions should follow the dispatcher role")
+	}
+}
+
+func TestRequireAuth_Rejects(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+	other := signing.NewHMAC([]byte("another-secret-that-is-long-enough-xx"))
+
+	cases := map[string]string{
+		"missing":       "",
+		"not bearer":    "Basic abc",
+		"inactive user": bearer(t, h.Keys, jwt.MapClaims{"uid": 2, "jti": "x"}),
+		"unknown user":  bearer(t, h.Keys, jwt.MapClaims{"uid": 9, "jti": "x"}),
+		"mfa challenge": bearer(t, h.Keys, jwt.MapClaims{"uid": 1, "jti": "x", "purpose": "mfa"}),
+		"expired":       bearer(t, h.Keys, jwt.MapClaims{"uid": 1, "jti": "x", "exp": time.Now().Add(-time.Minute).Unix()}),
+		"foreign key":   bearer(t, other, jwt.MapClaims{"uid": 1, "jti": "x"}),
+	}
+	for name, header := range cases {
+		if code, p := serve(h, header); code != http.StatusUnauthorized || p != nil {
+			t.Errorf("%s: expected 401, got %d", name, code)
+		}
+	}
+}
+
+func TestRequireAuth_RejectsLoggedOutToken(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+	header := bearer(t, h.Keys, jwt.MapClaims{"uid": 1, "jti": "gone"})
+
+	if err := h.Sessions.Logout(context.Background(), "", "gone", time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := serve(h, header); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after logout, got %d", code)
+	}
+}
+
+func TestRequireAuth_RejectsTokenOfRevokedSession(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+	family, refresh, err := h.Sessions.Start(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a token issued before the refresh token was reused, with another jti than the one logged out
+	header := bearer(t, h.Keys, jwt.MapClaims{"uid": 1, "jti": "still-valid", "sid": family})
+	if code, _ := serve(h, header); code != http.StatusOK {
+		t.Fatalf("expected 200 before the session is revoked, got %d", code)
+	}
+
+	if _, _, _, err := h.Sessions.Rotate(context.Background(), refresh); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := h.Sessions.Rotate(context.Background(), refresh); err == nil {
+		t.Fatal("expected the reused refresh token to be rejected")
+	}
+	if code, _ := serve(h, header); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 once the session is revoked, got %d", code)
+	}
+}
+
+func TestLogin_ThrottlesByTrustedForwardedHop(t *testing.T) {
+	t.Setenv("TRUST_PROXY_HEADERS", "true")
+	cases := map[string]struct {
+		proxies string
+		want    string
+	}{
+		"one proxy":   {"", "203.0.113.9"},
+		"two proxies": {"2", "198.51.100.4"},
+	}
+	for name, c := range cases {
+		t.Setenv("TRUSTED_PROXY_COUNT", c.proxies)
+		users := &mockUserRepo{users: []domain.User{
+			{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+			{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+		}}
+		h := &httpdelivery.Handler{
+			Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+			Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+			Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+		}
+		throttle := &mockLoginThrottleRepo{}
+		cfg := usecase.LoginGuardConfig{MaxAccountFailures: 3, MaxIPFailures: 10, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: 5 * time.Minute}
+		guard := usecase.NewLoginGuardService(throttle, &mockAuditRepo{}, cfg)
+		h.LoginGuard = guard
+
+		// the client forges the first hop; every proxy appends the address it saw
+		req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"email":"ana@example.com","password":"wrong"}`))
+		req.Header.Set("X-Forwarded-For", "1.2.3.4, 198.51.100.4")
+		req.Header.Add("X-Forwarded-For", "203.0.113.9")
+		rec := httptest.NewRecorder()
+		h.Login(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d", name, rec.Code)
+		}
+		if _, ok := throttle.rows["ip:"+c.want]; !ok {
+			t.Errorf("%s: expected the failure counted for %s, got %v", name, c.want, throttle.rows)
+		}
+		if _, ok := throttle.rows["ip:1.2.3.4"]; ok {
+			t.Errorf("%s: the forged hop must not be used", name)
+		}
+	}
+}
+
+func TestLogin_InactiveUserCountsAsFailure(t *testing.T) {
+	// Arrange
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secreto"), bcrypt.MinCost)
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 2, Email: "luis@example.com", Password: string(hash), Role: domain.RoleClient, IsActive: false},
+	}}
+	throttle := &mockLoginThrottleRepo{}
+	h := &httpdelivery.Handler{
+		Users: usecase.NewUserService(users, &mockAuditRepo{}),
+		LoginGuard: usecase.NewLoginGuardService(throttle, &mockAuditRepo{}, usecase.LoginGuardConfig{
+			MaxAccountFailures: 3, MaxIPFailures: 10, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: 5 * time.Minute,
+		}),
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/login", strings.NewReader(`{"email":"luis@example.com","password":"secreto"}`))
+	rec := httptest.NewRecorder()
+
+	// Act
+	h.Login(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusUnauthorized || strings.TrimSpace(rec.Body.String()) != "Invalid credentials" {
+		t.Fatalf("Expected the generic 401 for a deactivated account, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if row, ok := throttle.rows["email:luis@example.com"]; !ok || row.Failures != 1 {
+		t.Errorf("Expected the attempt counted as a failure, got %v", throttle.rows)
+	}
+}
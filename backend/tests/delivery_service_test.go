@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+)
+
+type mockDeliveryAttemptRepo struct {
+	orders      *mockOrderRepo
+	attempts    []domain.DeliveryAttempt
+	rescheduled map[uint]time.Time
+}
+
+func (m *mockDeliveryAttemptRepo) RecordAttempt(ctx context.Context, a *domain.DeliveryAttempt, next domain.OrderStatus) error {
+	o, err := m.orders.FindByID(ctx, a.OrderID)
+	if err != nil {
+		return err
+	}
+	o.DeliveryAttempts = a.AttemptNumber
+	o.Status = next
+	a.ID = uint(len(m.attempts) + 1)
+	m.attempts = append(m.attempts, *a)
+	return nil
+}
+
+func (m *mockDeliveryAttemptRepo) ListByOrder(ctx context.Context, orderID uint) ([]domain.DeliveryAttempt, error) {
+	var list []domain.DeliveryAttempt
+	for _, a := range m.attempts {
+		if a.OrderID == orderID {
+			list = append(list, a)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockDeliveryAttemptRepo) Reschedule(ctx context.Context, orderID uint, version uint, date time.Time, changedBy uint) error {
+	o, err := m.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if o.Version != version {
+		return repository.ErrStaleVersion
+	}
+	if m.rescheduled == nil {
+		m.rescheduled = map[uint]time.Time{}
+	}
+	m.rescheduled[orderID] = date
+	o.Version++
+	return nil
+}
+
+func TestDeliveryService_RecordFailedAttempt_MovesToDeliveryFailed(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderInRoute, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 3)
+
+	// Act
+	a, err := service.RecordFailedAttempt(context.Background(), 1, domain.FailureRecipientAbsent, "Nadie en casa", 99)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if a.AttemptNumber != 1 {
+		t.Errorf("Expected attempt number 1, got %d", a.AttemptNumber)
+	}
+
+	if orders.orders[0].Status != domain.OrderDeliveryFailed {
+		t.Errorf("Expected status %v, got %v", domain.OrderDeliveryFailed, orders.orders[0].Status)
+	}
+
+	if len(repo.attempts) != 1 {
+		t.Errorf("Expected 1 attempt recorded, got %d", len(repo.attempts))
+	}
+}
+
+func TestDeliveryService_RecordFailedAttempt_MaxAttemptsReturnsToSender(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderInRoute, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 2)
+	orders.orders[0].DeliveryAttempts = 1
+
+	// Act
+	a, err := service.RecordFailedAttempt(context.Background(), 1, domain.FailureRefused, "", 99)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if a.AttemptNumber != 2 {
+		t.Errorf("Expected attempt number 2, got %d", a.AttemptNumber)
+	}
+
+	if orders.orders[0].Status != domain.OrderReturnToSender {
+		t.Errorf("Expected status %v, got %v", domain.OrderReturnToSender, orders.orders[0].Status)
+	}
+}
+
+func TestDeliveryService_RecordFailedAttempt_InvalidReason(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderInRoute, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 3)
+
+	// Act
+	_, err := service.RecordFailedAttempt(context.Background(), 1, "lost", "", 99)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for invalid reason, got nil")
+	}
+
+	if len(repo.attempts) != 0 {
+		t.Errorf("Expected no attempts recorded, got %d", len(repo.attempts))
+	}
+}
+
+func TestDeliveryService_RecordFailedAttempt_OrderNotInRoute(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderInStation, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 3)
+
+	// Act
+	_, err := service.RecordFailedAttempt(context.Background(), 1, domain.FailureWrongAddress, "", 99)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for order not in route, got nil")
+	}
+
+	expectedError := "solo se pueden registrar intentos de entrega para órdenes en ruta"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error message '%s', got '%s'", expectedError, err.Error())
+	}
+}
+
+func TestDeliveryService_Reschedule_Success(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderDeliveryFailed, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 3)
+	date := time.Now().AddDate(0, 0, 2)
+
+	// Act
+	version, err := service.Reschedule(context.Background(), domain.Scope{UserID: 10}, false, 1, 1, date)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if version != 2 {
+		t.Errorf("Expected version 2, got %d", version)
+	}
+
+	if _, ok := repo.rescheduled[1]; !ok {
+		t.Error("Expected order to be rescheduled")
+	}
+}
+
+func TestDeliveryService_Reschedule_NotOwner(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderDeliveryFailed, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 3)
+
+	// Act
+	_, err := service.Reschedule(context.Background(), domain.Scope{UserID: 11}, false, 1, 1, time.Now().AddDate(0, 0, 2))
+
+	// Assert
+	if !errors.Is(err, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestDeliveryService_Reschedule_PastDate(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderDeliveryFailed, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 3)
+
+	// Act
+	_, err := service.Reschedule(context.Background(), domain.Scope{UserID: 10}, false, 1, 1, time.Now())
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for non-future date, got nil")
+	}
+}
+
+func TestDeliveryService_Reschedule_StaleVersion(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 10, Status: domain.OrderDeliveryFailed, Version: 1}}}
+	repo := &mockDeliveryAttemptRepo{orders: orders}
+	service := usecase.NewDeliveryService(repo, orders, 3)
+
+	// Act
+	_, err := service.Reschedule(context.Background(), domain.Scope{UserID: 10}, false, 1, 3, time.Now().AddDate(0, 0, 2))
+
+	// Assert
+	if !errors.Is(err, usecase.ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed, got %v", err)
+	}
+
+	if len(repo.rescheduled) != 0 {
+		t.Error("Expected stale request not to reschedule the order")
+	}
+}
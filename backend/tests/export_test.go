@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/export"
+	"logistics-app/backend/internal/infra/sheet"
+	"testing"
+)
+
+func sampleExportRows() []domain.OrderExportRow {
+	return []domain.OrderExportRow{
+		{
+			OrderListItem: domain.OrderListItem{ID: 1, OrderNumber: "ORD-1", CreatedAt: "19/10/2026", FullName: "María López", Quantity: 2, ActualWeightKg: 3.5, SizeCode: domain.PackageS, Status: domain.OrderCreated},
+			Observations:  "Frágil, \"cuidado\"",
+		},
+		{
+			OrderListItem:       domain.OrderListItem{ID: 2, OrderNumber: "ORD-2", CreatedAt: "19/10/2026", FullName: "José Pérez", Quantity: 1, ActualWeightKg: 10, SizeCode: domain.PackageM, Status: domain.OrderDelivered},
+			DeliveryPreferences: domain.DeliveryPreferences{LeaveWith: domain.LeaveWithConcierge},
+		},
+	}
+}
+
+func writeExport(t *testing.T, format export.Format, detail bool) ([]byte, string) {
+	var buf bytes.Buffer
+	ow, contentType, err := export.NewOrderWriter(format, &buf, detail)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, r := range sampleExportRows() {
+		if err := ow.Write(r); err != nil {
+			t.Fatalf("Expected no error writing row, got %v", err)
+		}
+	}
+	if err := ow.Close(); err != nil {
+		t.Fatalf("Expected no error closing, got %v", err)
+	}
+	return buf.Bytes(), contentType
+}
+
+func TestExport_CSV_DetailColumns(t *testing.T) {
+	// Act
+	b, _ := writeExport(t, export.FormatCSV, true)
+	rows, err := sheet.Read(b)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected readable CSV, got %v", err)
+	}
+
+	if len(rows) != 3 || len(rows[0]) != 20 {
+		t.Fatalf("Expected header and 2 rows of 20 columns, got %d rows", len(rows))
+	}
+
+	if rows[1][7] != "3.50" || rows[1][10] != "Frágil, \"cuidado\"" || rows[2][16] != "concierge" {
+		t.Errorf("Unexpected values: %q / %q", rows[1], rows[2])
+	}
+}
+
+func TestExport_XLSX_RoundTrip(t *testing.T) {
+	// Act
+	b, contentType := writeExport(t, export.FormatXLSX, false)
+	rows, err := sheet.Read(b)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected readable XLSX (%s), got %v", contentType, err)
+	}
+
+	if len(rows) != 3 || rows[0][1] != "order_number" || rows[2][1] != "ORD-2" || rows[2][9] != "delivered" {
+		t.Errorf("Unexpected rows: %q", rows)
+	}
+}
+
+func TestExport_EscapesFormulas(t *testing.T) {
+	// Arrange
+	row := domain.OrderExportRow{
+		OrderListItem: domain.OrderListItem{ID: 1, OrderNumber: "ORD-1", FullName: "@SUM(A1)", ActualWeightKg: 2},
+		DeliveryPreferences: domain.DeliveryPreferences{
+			RecipientName:  `=HYPERLINK("http://evil.example","clic")`,
+			RecipientPhone: "+52 55 1234 5678",
+		},
+	}
+
+	for _, format := range []export.Format{export.FormatCSV, export.FormatXLSX} {
+		var buf bytes.Buffer
+		ow, _, err := export.NewOrderWriter(format, &buf, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Act
+		if err := ow.Write(row); err != nil {
+			t.Fatalf("Expected no error writing row, got %v", err)
+		}
+		if err := ow.Close(); err != nil {
+			t.Fatalf("Expected no error closing, got %v", err)
+		}
+		rows, err := sheet.Read(buf.Bytes())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected readable %s, got %v", format, err)
+		}
+		if rows[1][18] != `'=HYPERLINK("http://evil.example","clic")` || rows[1][19] != "'+52 55 1234 5678" || rows[1][3] != "'@SUM(A1)" {
+			t.Errorf("Expected %s formulas to be escaped, got %q", format, rows[1])
+		}
+		if rows[1][1] != "ORD-1" {
+			t.Errorf("Expected plain values untouched in %s, got %q", format, rows[1])
+		}
+	}
+}
+
+func TestExport_PDF_Summary(t *testing.T) {
+	// Act
+	b, contentType := writeExport(t, export.FormatPDF, false)
+
+	// Assert
+	if contentType != "application/pdf" || !bytes.HasPrefix(b, []byte("%PDF-")) {
+		t.Errorf("Expected PDF document, got %s", contentType)
+	}
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	// Act
+	_, _, err := export.NewOrderWriter("json", &bytes.Buffer{}, false)
+
+	// Assert
+	if !errors.Is(err, export.ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestSheet_Read_SemicolonCSV(t *testing.T) {
+	// Act
+	rows, err := sheet.Read([]byte("\xef\xbb\xbfa;b\n1;2,5\n"))
+
+	// Assert
+	if err != nil || len(rows) != 2 || rows[0][0] != "a" || rows[1][1] != "2,5" {
+		t.Errorf("Expected BOM stripped and semicolon separator, got %q (%v)", rows, err)
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+)
+
+type mockIdempotencyRepo struct {
+	records map[uint]*domain.IdempotencyKey
+	lastID  uint
+}
+
+func (m *mockIdempotencyRepo) Find(ctx context.Context, userID uint, endpoint, key string) (*domain.IdempotencyKey, error) {
+	for _, k := range m.records {
+		if k.UserID == userID && k.Endpoint == endpoint && k.Key == key && k.ExpiresAt.After(time.Now()) {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockIdempotencyRepo) Reserve(ctx context.Context, k *domain.IdempotencyKey) (bool, error) {
+	if m.records == nil {
+		m.records = map[uint]*domain.IdempotencyKey{}
+	}
+	for id, r := range m.records {
+		if !r.ExpiresAt.After(time.Now()) {
+			delete(m.records, id)
+		} else if r.UserID == k.UserID && r.Endpoint == k.Endpoint && r.Key == k.Key {
+			return false, nil
+		}
+	}
+	m.lastID++
+	k.ID = m.lastID
+	c := *k
+	m.records[k.ID] = &c
+	return true, nil
+}
+
+// Complete and Release fail on a cancelled context like a database query would
+func (m *mockIdempotencyRepo) Complete(ctx context.Context, id uint, statusCode int, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.records[id].StatusCode = statusCode
+	m.records[id].ResponseBody = body
+	return nil
+}
+
+func (m *mockIdempotencyRepo) Release(ctx context.Context, id uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	delete(m.records, id)
+	return nil
+}
+
+const orderEndpoint = "POST /api/orders"
+
+func TestIdempotencyService_ReplaysSameBody(t *testing.T) {
+	// Arrange
+	svc := usecase.NewIdempotencyService(&mockIdempotencyRepo{}, usecase.DefaultIdempotencyTTL)
+	rec, replay, err := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1,"observations":"x"}`))
+	if err != nil || replay {
+		t.Fatalf("Expected first request to run, got replay=%v err=%v", replay, err)
+	}
+	_ = svc.Complete(context.Background(), rec, 201, []byte(`{"id":10}`))
+
+	// Act
+	again, replay, err := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte("{ \"observations\": \"x\", \"quantity\": 1 }"))
+
+	// Assert
+	if err != nil || !replay {
+		t.Fatalf("Expected replay, got replay=%v err=%v", replay, err)
+	}
+
+	if again.StatusCode != 201 || string(again.ResponseBody) != `{"id":10}` {
+		t.Errorf("Expected stored 201 response, got %d %s", again.StatusCode, again.ResponseBody)
+	}
+}
+
+func TestIdempotencyService_DifferentBody(t *testing.T) {
+	// Arrange
+	svc := usecase.NewIdempotencyService(&mockIdempotencyRepo{}, usecase.DefaultIdempotencyTTL)
+	rec, _, _ := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1}`))
+	_ = svc.Complete(context.Background(), rec, 201, []byte(`{"id":10}`))
+
+	// Act
+	_, _, err := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":2}`))
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUnprocessable) {
+		t.Errorf("Expected ErrUnprocessable, got %v", err)
+	}
+}
+
+func TestIdempotencyService_KeysArePerUser(t *testing.T) {
+	// Arrange
+	svc := usecase.NewIdempotencyService(&mockIdempotencyRepo{}, usecase.DefaultIdempotencyTTL)
+	rec, _, _ := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1}`))
+	_ = svc.Complete(context.Background(), rec, 201, []byte(`{"id":10}`))
+
+	// Act
+	_, replay, err := svc.Begin(context.Background(), 2, orderEndpoint, "k1", []byte(`{"quantity":2}`))
+
+	// Assert
+	if err != nil || replay {
+		t.Errorf("Expected another user's key to run, got replay=%v err=%v", replay, err)
+	}
+}
+
+func TestIdempotencyService_InProgress(t *testing.T) {
+	// Arrange
+	svc := usecase.NewIdempotencyService(&mockIdempotencyRepo{}, usecase.DefaultIdempotencyTTL)
+	_, _, _ = svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1}`))
+
+	// Act
+	_, _, err := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1}`))
+
+	// Assert
+	if !errors.Is(err, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict while the first request runs, got %v", err)
+	}
+}
+
+func TestIdempotencyService_ServerErrorReleasesKey(t *testing.T) {
+	// Arrange
+	svc := usecase.NewIdempotencyService(&mockIdempotencyRepo{}, usecase.DefaultIdempotencyTTL)
+	rec, _, _ := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1}`))
+	_ = svc.Complete(context.Background(), rec, 500, []byte("db down"))
+
+	// Act
+	_, replay, err := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1}`))
+
+	// Assert
+	if err != nil || replay {
+		t.Errorf("Expected retry to run again after a server error, got replay=%v err=%v", replay, err)
+	}
+}
+
+func TestIdempotencyService_Expired(t *testing.T) {
+	// Arrange
+	svc := usecase.NewIdempotencyService(&mockIdempotencyRepo{}, time.Nanosecond)
+	rec, _, _ := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":1}`))
+	_ = svc.Complete(context.Background(), rec, 201, []byte(`{"id":10}`))
+	time.Sleep(time.Millisecond)
+
+	// Act
+	_, replay, err := svc.Begin(context.Background(), 1, orderEndpoint, "k1", []byte(`{"quantity":2}`))
+
+	// Assert
+	if err != nil || replay {
+		t.Errorf("Expected expired key to run again, got replay=%v err=%v", replay, err)
+	}
+}
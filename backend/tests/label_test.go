@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/label"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleLabel() domain.ShippingLabel {
+	return domain.ShippingLabel{
+		OrderNumber:      "ORD-20261019-123456",
+		CreatedAt:        time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		SenderName:       "Armin Cetina",
+		SenderAddress:    []string{"Calle 60 123", "Centro", "CP 97000 Mérida"},
+		RecipientName:    "María López",
+		RecipientPhone:   "+52 999 123 4567",
+		RecipientAddress: []string{"Av. Reforma 222", "Juárez", "CP 06600 Ciudad de México"},
+		SizeCode:         domain.PackageM,
+		WeightKg:         7.5,
+		Quantity:         2,
+		Zone:             "066",
+		TrackingURL:      "http://localhost:3000/tracking/ORD-20261019-123456",
+	}
+}
+
+func TestLabel_Render_PDF(t *testing.T) {
+	// Act
+	b, contentType, err := label.Render(label.FormatPDF, sampleLabel())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if contentType != "application/pdf" || !bytes.HasPrefix(b, []byte("%PDF-")) {
+		t.Errorf("Expected PDF document, got %s", contentType)
+	}
+}
+
+func TestLabel_Render_PNG(t *testing.T) {
+	// Act
+	b, contentType, err := label.Render(label.FormatPNG, sampleLabel())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if contentType != "image/png" || !bytes.HasPrefix(b, []byte("\x89PNG")) {
+		t.Errorf("Expected PNG image, got %s", contentType)
+	}
+}
+
+func TestLabel_Render_ZPL(t *testing.T) {
+	// Act
+	b, _, err := label.Render(label.FormatZPL, sampleLabel())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	zpl := string(b)
+	if !strings.HasPrefix(zpl, "^XA") || !strings.HasSuffix(zpl, "^XZ\n") {
+		t.Error("Expected ZPL to start with ^XA and end with ^XZ")
+	}
+
+	if !strings.Contains(zpl, "^BCN") || !strings.Contains(zpl, "^FDORD-20261019-123456^FS") {
+		t.Error("Expected Code128 barcode with the order number")
+	}
+
+	if !strings.Contains(zpl, "^BQN") {
+		t.Error("Expected QR code")
+	}
+}
+
+func TestLabel_Render_UnsupportedFormat(t *testing.T) {
+	// Act
+	_, _, err := label.Render("docx", sampleLabel())
+
+	// Assert
+	if !errors.Is(err, label.ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+}
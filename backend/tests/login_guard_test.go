@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+)
+
+type mockLoginThrottleRepo struct {
+	rows map[string]*domain.LoginThrottle
+}
+
+func (m *mockLoginThrottleRepo) Find(ctx context.Context, key string) (*domain.LoginThrottle, error) {
+	if t, ok := m.rows[key]; ok {
+		c := *t
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (m *mockLoginThrottleRepo) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginThrottle, error) {
+	if m.rows == nil {
+		m.rows = map[string]*domain.LoginThrottle{}
+	}
+	t, ok := m.rows[key]
+	if !ok {
+		t = &domain.LoginThrottle{Key: key}
+		m.rows[key] = t
+	}
+	if t.LastFailureAt.Before(now.Add(-window)) {
+		t.Failures = 0
+	}
+	t.Failures++
+	t.LastFailureAt = now
+	c := *t
+	return &c, nil
+}
+
+func (m *mockLoginThrottleRepo) Lock(ctx context.Context, key string, threshold uint, until time.Time) (bool, error) {
+	t := m.rows[key]
+	if t.Failures < threshold {
+		return false, nil
+	}
+	t.Failures = 0
+	t.LockCount++
+	t.LockedUntil = &until
+	return true, nil
+}
+
+func (m *mockLoginThrottleRepo) Reset(ctx context.Context, key string) error {
+	delete(m.rows, key)
+	return nil
+}
+
+type mockAuditRepo struct {
+	logs []domain.AuditLog
+}
+
+func (m *mockAuditRepo) Create(ctx context.Context, a *domain.AuditLog) error {
+	m.logs = append(m.logs, *a)
+	return nil
+}
+
+func TestLoginGuardService_LocksAccountAfterFailures(t *testing.T) {
+	// Arrange
+	repo := &mockLoginThrottleRepo{}
+	audit := &mockAuditRepo{}
+	cfg := usecase.LoginGuardConfig{MaxAccountFailures: 3, MaxIPFailures: 10, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: 5 * time.Minute}
+	service := usecase.NewLoginGuardService(repo, audit, cfg)
+
+	// Act
+	for i := 0; i < 3; i++ {
+		if err := service.Failure(context.Background(), "Ana@Example.com", "10.0.0.1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	err := service.Check(context.Background(), "ana@example.com", "10.0.0.2")
+
+	// Assert
+	var locked *usecase.LockedError
+	if !errors.As(err, &locked) || !errors.Is(err, usecase.ErrTooManyAttempts) {
+		t.Fatalf("Expected LockedError, got %v", err)
+	}
+
+	if locked.RetryAfter <= 0 || locked.RetryAfter > time.Minute {
+		t.Errorf("Expected first lockout of up to 1 minute, got %s", locked.RetryAfter)
+	}
+
+	if len(audit.logs) != 1 || audit.logs[0].Action != domain.AuditLoginLockout || audit.logs[0].Subject != "email:ana@example.com" {
+		t.Errorf("Expected one lockout audit record, got %+v", audit.logs)
+	}
+}
+
+func TestLoginGuardService_BackoffDoubles(t *testing.T) {
+	// Arrange
+	repo := &mockLoginThrottleRepo{}
+	cfg := usecase.LoginGuardConfig{MaxAccountFailures: 3, MaxIPFailures: 10, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: 5 * time.Minute}
+	service := usecase.NewLoginGuardService(repo, &mockAuditRepo{}, cfg)
+	for i := 0; i < 3; i++ {
+		_ = service.Failure(context.Background(), "ana@example.com", "10.0.0.1")
+	}
+	first := *repo.rows["email:ana@example.com"].LockedUntil
+
+	// Act
+	for i := 0; i < 3; i++ {
+		_ = service.Failure(context.Background(), "ana@example.com", "10.0.0.1")
+	}
+	second := *repo.rows["email:ana@example.com"].LockedUntil
+
+	// Assert
+	if d := second.Sub(first); d < 50*time.Second {
+		t.Errorf("Expected the second lockout to last about twice as long, got %s more", d)
+	}
+}
+
+func TestLoginGuardService_LocksIP(t *testing.T) {
+	// Arrange
+	repo := &mockLoginThrottleRepo{}
+	cfg := usecase.LoginGuardConfig{MaxAccountFailures: 3, MaxIPFailures: 10, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: 5 * time.Minute}
+	service := usecase.NewLoginGuardService(repo, &mockAuditRepo{}, cfg)
+
+	// Act: one attempt per email so no account reaches its limit
+	for i := 0; i < 10; i++ {
+		_ = service.Failure(context.Background(), string(rune('a'+i))+"@example.com", "10.0.0.9")
+	}
+
+	// Assert
+	if err := service.Check(context.Background(), "nuevo@example.com", "10.0.0.9"); !errors.Is(err, usecase.ErrTooManyAttempts) {
+		t.Errorf("Expected the IP to be locked, got %v", err)
+	}
+
+	if err := service.Check(context.Background(), "nuevo@example.com", "10.0.0.10"); err != nil {
+		t.Errorf("Expected other IPs to be allowed, got %v", err)
+	}
+}
+
+func TestLoginGuardService_SuccessResetsAccount(t *testing.T) {
+	// Arrange
+	repo := &mockLoginThrottleRepo{}
+	cfg := usecase.LoginGuardConfig{MaxAccountFailures: 3, MaxIPFailures: 10, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: 5 * time.Minute}
+	service := usecase.NewLoginGuardService(repo, &mockAuditRepo{}, cfg)
+	_ = service.Failure(context.Background(), "ana@example.com", "10.0.0.1")
+	_ = service.Failure(context.Background(), "ana@example.com", "10.0.0.1")
+
+	// Act
+	err := service.Success(context.Background(), "ana@example.com")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := repo.rows["email:ana@example.com"]; ok {
+		t.Error("Expected account counters to be cleared")
+	}
+
+	if repo.rows["ip:10.0.0.1"].Failures != 2 {
+		t.Error("Expected IP counters to be kept")
+	}
+}
+
+func TestUserService_Authenticate_UniformError(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{}
+	service := usecase.NewUserService(repo, &mockAuditRepo{})
+	if _, err := service.Register(context.Background(), "ana@example.com", "secreto", "Ana", ""); err != nil {
+		t.Fatalf("Expected no error registering, got %v", err)
+	}
+
+	if _, err := service.Register(context.Background(), "luis@example.com", "secreto", "Luis", ""); err != nil {
+		t.Fatalf("Expected no error registering, got %v", err)
+	}
+	repo.users[1].IsActive = false
+
+	// Act
+	_, unknownErr := service.Authenticate(context.Background(), "nadie@example.com", "secreto")
+	_, wrongErr := service.Authenticate(context.Background(), "ana@example.com", "incorrecto")
+	_, inactiveErr := service.Authenticate(context.Background(), "luis@example.com", "secreto")
+
+	// Assert
+	if !errors.Is(unknownErr, usecase.ErrInvalidCredentials) || !errors.Is(wrongErr, usecase.ErrInvalidCredentials) || !errors.Is(inactiveErr, usecase.ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for all, got %v, %v and %v", unknownErr, wrongErr, inactiveErr)
+	}
+}
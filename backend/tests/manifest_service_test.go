@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/manifest"
+	"logistics-app/backend/internal/usecase"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockManifestRepo struct {
+	collected []domain.ManifestItem
+	manifests []domain.Manifest
+	issued    map[uint]uint
+}
+
+func (m *mockManifestRepo) CollectItems(ctx context.Context, stationID uint, day time.Time, routeCode string, manifestID uint) ([]domain.ManifestItem, error) {
+	return append([]domain.ManifestItem(nil), m.collected...), nil
+}
+
+func (m *mockManifestRepo) Create(ctx context.Context, man *domain.Manifest) error {
+	man.ID = uint(len(m.manifests) + 1)
+	m.manifests = append(m.manifests, *man)
+	return nil
+}
+
+func (m *mockManifestRepo) ReplaceItems(ctx context.Context, man *domain.Manifest) error {
+	m.manifests[man.ID-1] = *man
+	return nil
+}
+
+func (m *mockManifestRepo) Issue(ctx context.Context, man *domain.Manifest, issuedBy uint) error {
+	man.Status = domain.ManifestIssued
+	man.IssuedBy = &issuedBy
+	m.manifests[man.ID-1] = *man
+	if m.issued == nil {
+		m.issued = map[uint]uint{}
+	}
+	for _, it := range man.Items {
+		m.issued[it.OrderID] = man.ID
+	}
+	return nil
+}
+
+func (m *mockManifestRepo) FindByID(ctx context.Context, id uint) (*domain.Manifest, error) {
+	if id == 0 || int(id) > len(m.manifests) {
+		return nil, errors.New("record not found")
+	}
+	man := m.manifests[id-1]
+	return &man, nil
+}
+
+func (m *mockManifestRepo) List(ctx context.Context, stationID uint, day *time.Time) ([]domain.Manifest, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func TestManifestService_Create_SumsTotals(t *testing.T) {
+	// Arrange
+	repo := &mockManifestRepo{collected: []domain.ManifestItem{
+		{OrderID: 1, OrderNumber: "ORD-1", Pieces: 2, WeightKg: 3.5, DestinationFullAddress: "Calle 1", Zone: "970"},
+		{OrderID: 2, OrderNumber: "ORD-2", Pieces: 1, WeightKg: 10, DestinationFullAddress: "Calle 2", Zone: "971"},
+	}}
+	stations := &mockStationRepo{stations: []domain.Station{{ID: 1, Code: "MID", Name: "Mérida", IsActive: true}}}
+	svc := usecase.NewManifestService(repo, stations)
+
+	// Act
+	m, err := svc.Create(context.Background(), usecase.ManifestRequest{StationID: 1, Date: "2026-10-19", RouteCode: " r1 "}, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if m.Status != domain.ManifestDraft || m.RouteCode != "R1" {
+		t.Errorf("Expected draft manifest for route R1, got %s %s", m.Status, m.RouteCode)
+	}
+
+	if m.TotalPieces != 3 || m.TotalWeightKg != 13.5 {
+		t.Errorf("Expected 3 pieces and 13.5 kg, got %d and %v", m.TotalPieces, m.TotalWeightKg)
+	}
+
+	if !strings.HasPrefix(m.ManifestNumber, "MAN-MID-20261019-") {
+		t.Errorf("Expected manifest number for MID on 2026-10-19, got %s", m.ManifestNumber)
+	}
+}
+
+func TestManifestService_Create_InvalidDate(t *testing.T) {
+	// Arrange
+	repo := &mockManifestRepo{collected: []domain.ManifestItem{
+		{OrderID: 1, OrderNumber: "ORD-1", Pieces: 2, WeightKg: 3.5, DestinationFullAddress: "Calle 1", Zone: "970"},
+		{OrderID: 2, OrderNumber: "ORD-2", Pieces: 1, WeightKg: 10, DestinationFullAddress: "Calle 2", Zone: "971"},
+	}}
+	stations := &mockStationRepo{stations: []domain.Station{{ID: 1, Code: "MID", Name: "Mérida", IsActive: true}}}
+	svc := usecase.NewManifestService(repo, stations)
+
+	// Act
+	_, err := svc.Create(context.Background(), usecase.ManifestRequest{StationID: 1, Date: "19/10/2026"}, 1)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected error for invalid date, got nil")
+	}
+}
+
+func TestManifestService_Issue_FreezesManifest(t *testing.T) {
+	// Arrange
+	repo := &mockManifestRepo{collected: []domain.ManifestItem{
+		{OrderID: 1, OrderNumber: "ORD-1", Pieces: 2, WeightKg: 3.5, DestinationFullAddress: "Calle 1", Zone: "970"},
+		{OrderID: 2, OrderNumber: "ORD-2", Pieces: 1, WeightKg: 10, DestinationFullAddress: "Calle 2", Zone: "971"},
+	}}
+	stations := &mockStationRepo{stations: []domain.Station{{ID: 1, Code: "MID", Name: "Mérida", IsActive: true}}}
+	svc := usecase.NewManifestService(repo, stations)
+	m, _ := svc.Create(context.Background(), usecase.ManifestRequest{StationID: 1, Date: "2026-10-19"}, 1)
+
+	// Act
+	issued, err := svc.Issue(context.Background(), m.ID, 1)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if issued.Status != domain.ManifestIssued {
+		t.Errorf("Expected issued manifest, got %s", issued.Status)
+	}
+
+	if repo.issued[1] != m.ID || repo.issued[2] != m.ID {
+		t.Errorf("Expected manifest ID recorded for every order, got %v", repo.issued)
+	}
+
+	if _, err := svc.Refresh(context.Background(), m.ID); !errors.Is(err, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict refreshing an issued manifest, got %v", err)
+	}
+
+	if _, err := svc.Issue(context.Background(), m.ID, 1); !errors.Is(err, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict issuing twice, got %v", err)
+	}
+}
+
+func TestManifestService_Issue_Empty(t *testing.T) {
+	// Arrange
+	repo := &mockManifestRepo{collected: []domain.ManifestItem{
+		{OrderID: 1, OrderNumber: "ORD-1", Pieces: 2, WeightKg: 3.5, DestinationFullAddress: "Calle 1", Zone: "970"},
+		{OrderID: 2, OrderNumber: "ORD-2", Pieces: 1, WeightKg: 10, DestinationFullAddress: "Calle 2", Zone: "971"},
+	}}
+	stations := &mockStationRepo{stations: []domain.Station{{ID: 1, Code: "MID", Name: "Mérida", IsActive: true}}}
+	svc := usecase.NewManifestService(repo, stations)
+	repo.collected = nil
+	m, _ := svc.Create(context.Background(), usecase.ManifestRequest{StationID: 1, Date: "2026-10-19"}, 1)
+
+	// Act
+	_, err := svc.Issue(context.Background(), m.ID, 1)
+
+	// Assert
+	if err == nil {
+		t.Error("Expected error issuing an empty manifest, got nil")
+	}
+}
+
+func TestManifest_Render(t *testing.T) {
+	// Arrange
+	repo := &mockManifestRepo{collected: []domain.ManifestItem{
+		{OrderID: 1, OrderNumber: "ORD-1", Pieces: 2, WeightKg: 3.5, DestinationFullAddress: "Calle 1", Zone: "970"},
+		{OrderID: 2, OrderNumber: "ORD-2", Pieces: 1, WeightKg: 10, DestinationFullAddress: "Calle 2", Zone: "971"},
+	}}
+	stations := &mockStationRepo{stations: []domain.Station{{ID: 1, Code: "MID", Name: "Mérida", IsActive: true}}}
+	svc := usecase.NewManifestService(repo, stations)
+	m, _ := svc.Create(context.Background(), usecase.ManifestRequest{StationID: 1, Date: "2026-10-19", DriverName: "José Pérez"}, 1)
+
+	// Act
+	pdf, pdfType, pdfErr := manifest.Render(manifest.FormatPDF, *m)
+	csv, csvType, csvErr := manifest.Render(manifest.FormatCSV, *m)
+	_, _, badErr := manifest.Render("xlsx", *m)
+
+	// Assert
+	if pdfErr != nil || pdfType != "application/pdf" || !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("Expected PDF document, got %s (%v)", pdfType, pdfErr)
+	}
+
+	if csvErr != nil || !strings.HasPrefix(csvType, "text/csv") {
+		t.Fatalf("Expected CSV document, got %s (%v)", csvType, csvErr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[3], "TOTAL,3,13.50") {
+		t.Errorf("Expected header, 2 orders and totals row, got %q", lines)
+	}
+
+	if !errors.Is(badErr, manifest.ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", badErr)
+	}
+}
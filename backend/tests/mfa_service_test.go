@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+type mockMFARepo struct {
+	user  domain.User
+	codes map[string]bool
+}
+
+func (m *mockMFARepo) FindUser(ctx context.Context, userID uint) (*domain.User, error) {
+	if userID != m.user.ID {
+		return nil, errors.New("user not found")
+	}
+	u := m.user
+	return &u, nil
+}
+
+func (m *mockMFARepo) SetPendingSecret(ctx context.Context, userID uint, secret string) error {
+	m.user.MFASecret = secret
+	return nil
+}
+
+func (m *mockMFARepo) Enable(ctx context.Context, userID uint, step int64, hashes []string) error {
+	m.user.MFAEnabled = true
+	m.user.MFALastStep = step
+	return m.ReplaceRecoveryCodes(ctx, userID, hashes)
+}
+
+func (m *mockMFARepo) UseStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	if step <= m.user.MFALastStep {
+		return false, nil
+	}
+	m.user.MFALastStep = step
+	return true, nil
+}
+
+func (m *mockMFARepo) UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error) {
+	if unused, ok := m.codes[hash]; ok && unused {
+		m.codes[hash] = false
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *mockMFARepo) ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error {
+	m.codes = map[string]bool{}
+	for _, h := range hashes {
+		m.codes[h] = true
+	}
+	return nil
+}
+
+func (m *mockMFARepo) Disable(ctx context.Context, userID uint) error {
+	m.user.MFAEnabled = false
+	m.user.MFASecret = ""
+	m.codes = nil
+	return nil
+}
+
+func enrollAndConfirm(t *testing.T, service *usecase.MFAService) (string, []string) {
+	t.Helper()
+	e, err := service.Enroll(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error enrolling, got %v", err)
+	}
+	// a code from the previous period, so the test can also log in with the current one
+	code, _ := totp.GenerateCode(e.Secret, time.Now().Add(-30*time.Second))
+	codes, err := service.Confirm(context.Background(), 1, code)
+	if err != nil {
+		t.Fatalf("Expected no error confirming, got %v", err)
+	}
+	return e.Secret, codes
+}
+
+func TestMFAService_Confirm_ReturnsRecoveryCodes(t *testing.T) {
+	// Arrange
+	repo := &mockMFARepo{user: domain.User{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin}}
+	service := usecase.NewMFAService(repo, usecase.MFAConfig{RequiredRoles: []domain.Role{domain.RoleAdmin}})
+
+	// Act
+	_, codes := enrollAndConfirm(t, service)
+
+	// Assert
+	if !repo.user.MFAEnabled {
+		t.Error("Expected MFA to be enabled")
+	}
+
+	if len(codes) != 10 || len(repo.codes) != 10 {
+		t.Errorf("Expected 10 recovery codes, got %d", len(codes))
+	}
+
+	if _, err := service.Enroll(context.Background(), 1); !errors.Is(err, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict enrolling again, got %v", err)
+	}
+}
+
+func TestMFAService_Confirm_WrongCode(t *testing.T) {
+	// Arrange
+	repo := &mockMFARepo{user: domain.User{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin}}
+	service := usecase.NewMFAService(repo, usecase.MFAConfig{RequiredRoles: []domain.Role{domain.RoleAdmin}})
+	if _, err := service.Enroll(context.Background(), 1); err != nil {
+		t.Fatalf("Expected no error enrolling, got %v", err)
+	}
+
+	// Act
+	_, err := service.Confirm(context.Background(), 1, "000000")
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+
+	if repo.user.MFAEnabled {
+		t.Error("Expected MFA to stay disabled")
+	}
+}
+
+func TestMFAService_Verify_RejectsReplayedCode(t *testing.T) {
+	// Arrange
+	repo := &mockMFARepo{user: domain.User{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin}}
+	service := usecase.NewMFAService(repo, usecase.MFAConfig{RequiredRoles: []domain.Role{domain.RoleAdmin}})
+	secret, _ := enrollAndConfirm(t, service)
+	code, _ := totp.GenerateCode(secret, time.Now())
+
+	// Act
+	first := service.Verify(context.Background(), 1, code)
+	replay := service.Verify(context.Background(), 1, code)
+
+	// Assert
+	if first != nil {
+		t.Fatalf("Expected no error, got %v", first)
+	}
+
+	if !errors.Is(replay, usecase.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized on replay, got %v", replay)
+	}
+}
+
+func TestMFAService_Verify_RecoveryCodeSingleUse(t *testing.T) {
+	// Arrange
+	repo := &mockMFARepo{user: domain.User{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin}}
+	service := usecase.NewMFAService(repo, usecase.MFAConfig{RequiredRoles: []domain.Role{domain.RoleAdmin}})
+	_, codes := enrollAndConfirm(t, service)
+
+	// Act
+	first := service.Verify(context.Background(), 1, codes[0])
+	second := service.Verify(context.Background(), 1, codes[0])
+
+	// Assert
+	if first != nil {
+		t.Fatalf("Expected no error, got %v", first)
+	}
+
+	if !errors.Is(second, usecase.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized reusing the recovery code, got %v", second)
+	}
+}
+
+func TestMFAService_Disable_MandatoryRole(t *testing.T) {
+	// Arrange
+	repo := &mockMFARepo{user: domain.User{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin}}
+	service := usecase.NewMFAService(repo, usecase.MFAConfig{RequiredRoles: []domain.Role{domain.RoleAdmin}})
+	_, codes := enrollAndConfirm(t, service)
+
+	// Act
+	err := service.Disable(context.Background(), 1, domain.RoleAdmin, codes[0])
+
+	// Assert
+	if !errors.Is(err, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+
+	if !service.Required(domain.RoleAdmin) || service.Required(domain.RoleClient) {
+		t.Error("Expected MFA to be required only for admins")
+	}
+}
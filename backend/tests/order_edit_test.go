@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+)
+
+type mockOrderChangeRepo struct {
+	orders  *mockOrderRepo
+	changes []domain.OrderChange
+}
+
+func (m *mockOrderChangeRepo) ApplyEdit(ctx context.Context, orderID uint, version uint, updates map[string]interface{}, changes []domain.OrderChange) error {
+	o, err := m.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if o.Version != version || o.Status != domain.OrderCreated {
+		return repository.ErrStaleVersion
+	}
+	o.Version++
+	m.changes = append(m.changes, changes...)
+	return nil
+}
+
+func (m *mockOrderChangeRepo) ListByOrder(ctx context.Context, orderID uint) ([]domain.OrderChange, error) {
+	var list []domain.OrderChange
+	for _, c := range m.changes {
+		if c.OrderID == orderID {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+
+func TestOrderEditService_Edit_LogsChangedFields(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{
+		ID: 1, CustomerID: 10, Status: domain.OrderCreated, Version: 1,
+		OriginAddressID: 1, DestinationAddressID: 2, PackageTypeID: 1, Quantity: 1, ActualWeightKg: 3,
+	}}}
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, IsActive: true},
+		{ID: 2, CustomerID: 10, IsActive: true},
+		{ID: 3, CustomerID: 10, IsActive: true},
+		{ID: 4, CustomerID: 11, IsActive: true},
+		{ID: 5, CustomerID: 10, IsActive: false},
+	}}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	repo := &mockOrderChangeRepo{orders: orders}
+	service := usecase.NewOrderEditService(repo, orders, addresses, validator)
+	dest, pt, weight, notes := uint(3), uint(2), 12.0, "Frágil"
+
+	// Act
+	o, changes, err := service.Edit(context.Background(), domain.Scope{UserID: 10}, false, 1, 1, usecase.OrderEditRequest{
+		DestinationAddressID: &dest, PackageTypeID: &pt, ActualWeightKg: &weight, Observations: &notes,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if o.Version != 2 || o.DestinationAddressID != 3 || o.PackageTypeID != 2 {
+		t.Errorf("Expected edited order at version 2, got %+v", o)
+	}
+
+	if len(changes) != 4 || len(repo.changes) != 4 {
+		t.Fatalf("Expected 4 logged changes, got %d", len(changes))
+	}
+
+	if c := changes[2]; c.Field != "actual_weight_kg" || c.OldValue != "3.00" || c.NewValue != "12.00" || c.Version != 2 {
+		t.Errorf("Unexpected weight change %+v", c)
+	}
+}
+
+func TestOrderEditService_Edit_NoChanges(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{
+		ID: 1, CustomerID: 10, Status: domain.OrderCreated, Version: 1,
+		OriginAddressID: 1, DestinationAddressID: 2, PackageTypeID: 1, Quantity: 1, ActualWeightKg: 3,
+	}}}
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, IsActive: true},
+		{ID: 2, CustomerID: 10, IsActive: true},
+		{ID: 3, CustomerID: 10, IsActive: true},
+		{ID: 4, CustomerID: 11, IsActive: true},
+		{ID: 5, CustomerID: 10, IsActive: false},
+	}}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	repo := &mockOrderChangeRepo{orders: orders}
+	service := usecase.NewOrderEditService(repo, orders, addresses, validator)
+	qty := uint(1)
+
+	// Act
+	o, changes, err := service.Edit(context.Background(), domain.Scope{UserID: 10}, false, 1, 1, usecase.OrderEditRequest{Quantity: &qty})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if o.Version != 1 || len(changes) != 0 || len(repo.changes) != 0 {
+		t.Errorf("Expected no change logged and version kept, got version %d and %d changes", o.Version, len(changes))
+	}
+}
+
+func TestOrderEditService_Edit_NotCreated(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{
+		ID: 1, CustomerID: 10, Status: domain.OrderCollected, Version: 1,
+		OriginAddressID: 1, DestinationAddressID: 2, PackageTypeID: 1, Quantity: 1, ActualWeightKg: 3,
+	}}}
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, IsActive: true},
+		{ID: 2, CustomerID: 10, IsActive: true},
+		{ID: 3, CustomerID: 10, IsActive: true},
+		{ID: 4, CustomerID: 11, IsActive: true},
+		{ID: 5, CustomerID: 10, IsActive: false},
+	}}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	repo := &mockOrderChangeRepo{orders: orders}
+	service := usecase.NewOrderEditService(repo, orders, addresses, validator)
+	qty := uint(2)
+
+	// Act
+	_, _, err := service.Edit(context.Background(), domain.Scope{UserID: 10}, false, 1, 1, usecase.OrderEditRequest{Quantity: &qty})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
+
+func TestOrderEditService_Edit_NotOwner(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{
+		ID: 1, CustomerID: 10, Status: domain.OrderCreated, Version: 1,
+		OriginAddressID: 1, DestinationAddressID: 2, PackageTypeID: 1, Quantity: 1, ActualWeightKg: 3,
+	}}}
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, IsActive: true},
+		{ID: 2, CustomerID: 10, IsActive: true},
+		{ID: 3, CustomerID: 10, IsActive: true},
+		{ID: 4, CustomerID: 11, IsActive: true},
+		{ID: 5, CustomerID: 10, IsActive: false},
+	}}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	repo := &mockOrderChangeRepo{orders: orders}
+	service := usecase.NewOrderEditService(repo, orders, addresses, validator)
+	qty := uint(2)
+
+	// Act
+	_, _, err := service.Edit(context.Background(), domain.Scope{UserID: 11}, false, 1, 1, usecase.OrderEditRequest{Quantity: &qty})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestOrderEditService_Edit_StaleVersion(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{
+		ID: 1, CustomerID: 10, Status: domain.OrderCreated, Version: 1,
+		OriginAddressID: 1, DestinationAddressID: 2, PackageTypeID: 1, Quantity: 1, ActualWeightKg: 3,
+	}}}
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, IsActive: true},
+		{ID: 2, CustomerID: 10, IsActive: true},
+		{ID: 3, CustomerID: 10, IsActive: true},
+		{ID: 4, CustomerID: 11, IsActive: true},
+		{ID: 5, CustomerID: 10, IsActive: false},
+	}}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	repo := &mockOrderChangeRepo{orders: orders}
+	service := usecase.NewOrderEditService(repo, orders, addresses, validator)
+	qty := uint(2)
+
+	// Act
+	_, _, err := service.Edit(context.Background(), domain.Scope{UserID: 10}, false, 1, 7, usecase.OrderEditRequest{Quantity: &qty})
+
+	// Assert
+	if !errors.Is(err, usecase.ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestOrderEditService_Edit_RejectsInvalidDestination(t *testing.T) {
+	cases := map[string]uint{
+		"other customer": 4,
+		"inactive":       5,
+		"same as origin": 1,
+	}
+
+	for name, dest := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			orders := &mockOrderRepo{orders: []domain.Order{{
+				ID: 1, CustomerID: 10, Status: domain.OrderCreated, Version: 1,
+				OriginAddressID: 1, DestinationAddressID: 2, PackageTypeID: 1, Quantity: 1, ActualWeightKg: 3,
+			}}}
+			addresses := &mockAddressRepo{addresses: []domain.Address{
+				{ID: 1, CustomerID: 10, IsActive: true},
+				{ID: 2, CustomerID: 10, IsActive: true},
+				{ID: 3, CustomerID: 10, IsActive: true},
+				{ID: 4, CustomerID: 11, IsActive: true},
+				{ID: 5, CustomerID: 10, IsActive: false},
+			}}
+			validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+				1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+				2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+			}}
+			repo := &mockOrderChangeRepo{orders: orders}
+			service := usecase.NewOrderEditService(repo, orders, addresses, validator)
+			dest := dest
+
+			// Act: admins are bound to the customer's addresses too
+			_, _, err := service.Edit(context.Background(), domain.Scope{UserID: 99}, true, 1, 1, usecase.OrderEditRequest{DestinationAddressID: &dest})
+
+			// Assert
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			if len(repo.changes) != 0 {
+				t.Error("Expected no change to be logged")
+			}
+		})
+	}
+}
+
+func TestOrderEditService_Edit_RevalidatesWeight(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{orders: []domain.Order{{
+		ID: 1, CustomerID: 10, Status: domain.OrderCreated, Version: 1,
+		OriginAddressID: 1, DestinationAddressID: 2, PackageTypeID: 1, Quantity: 1, ActualWeightKg: 3,
+	}}}
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, IsActive: true},
+		{ID: 2, CustomerID: 10, IsActive: true},
+		{ID: 3, CustomerID: 10, IsActive: true},
+		{ID: 4, CustomerID: 11, IsActive: true},
+		{ID: 5, CustomerID: 10, IsActive: false},
+	}}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	repo := &mockOrderChangeRepo{orders: orders}
+	service := usecase.NewOrderEditService(repo, orders, addresses, validator)
+	weight := 8.0
+
+	// Act
+	_, _, err := service.Edit(context.Background(), domain.Scope{UserID: 10}, false, 1, 1, usecase.OrderEditRequest{ActualWeightKg: &weight})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected weight validation error for package S, got nil")
+	}
+}
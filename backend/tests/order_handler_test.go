@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpdelivery "logistics-app/backend/internal/delivery/http"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+)
+
+const orderBody = `{"origin_address_id":1,"destination_address_id":2,"package_type_id":1,"quantity":1,"actual_weight_kg":2.5}`
+
+// postOrder calls CreateOrder as the given principal with an Idempotency-Key
+func postOrder(h *httpdelivery.Handler, ctx context.Context, p *domain.Principal, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", "k1")
+	req = req.WithContext(domain.WithPrincipal(ctx, p))
+	rec := httptest.NewRecorder()
+	h.CreateOrder(rec, req)
+	return rec
+}
+
+func clientPrincipal() *domain.Principal {
+	return &domain.Principal{UserID: 7, Role: domain.RoleClient, Permissions: domain.RoleClient.Permissions()}
+}
+
+func TestCreateOrder_ValidationErrorIsCached(t *testing.T) {
+	// Arrange
+	keys := &mockIdempotencyRepo{}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5, IsActive: true},
+	}}
+	h := &httpdelivery.Handler{
+		Orders:      usecase.NewOrderService(&mockOrderRepo{}, customerAddresses(7), validator),
+		Idempotency: usecase.NewIdempotencyService(keys, usecase.DefaultIdempotencyTTL),
+	}
+	body := `{"origin_address_id":1,"destination_address_id":1,"package_type_id":1,"quantity":1,"actual_weight_kg":2.5}`
+
+	// Act
+	rec := postOrder(h, context.Background(), clientPrincipal(), body)
+
+	// Assert
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if len(keys.records) != 1 {
+		t.Errorf("Expected the 400 to be stored for the key, got %d records", len(keys.records))
+	}
+}
+
+func TestCreateOrder_RepositoryErrorIsNotCached(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{shouldFail: true, failError: errors.New("connection reset by peer")}
+	keys := &mockIdempotencyRepo{}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5, IsActive: true},
+	}}
+	h := &httpdelivery.Handler{
+		Orders:      usecase.NewOrderService(orders, customerAddresses(7), validator),
+		Idempotency: usecase.NewIdempotencyService(keys, usecase.DefaultIdempotencyTTL),
+	}
+
+	// Act
+	first := postOrder(h, context.Background(), clientPrincipal(), orderBody)
+	orders.shouldFail = false
+	retry := postOrder(h, context.Background(), clientPrincipal(), orderBody)
+
+	// Assert
+	if first.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500 on a repository error, got %d", first.Code)
+	}
+
+	if retry.Code != http.StatusCreated || retry.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("Expected the retry to run again and create the order, got %d", retry.Code)
+	}
+
+	if len(orders.orders) != 1 || len(keys.records) != 1 {
+		t.Errorf("Expected 1 order and 1 stored key, got %d and %d", len(orders.orders), len(keys.records))
+	}
+}
+
+func TestCreateOrder_ClientDisconnectStillSettlesKey(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{}
+	keys := &mockIdempotencyRepo{}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5, IsActive: true},
+	}}
+	h := &httpdelivery.Handler{
+		Orders:      usecase.NewOrderService(orders, customerAddresses(7), validator),
+		Idempotency: usecase.NewIdempotencyService(keys, usecase.DefaultIdempotencyTTL),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	failing := &mockOrderRepo{shouldFail: true, failError: errors.New("connection reset by peer")}
+	failingKeys := &mockIdempotencyRepo{}
+	hFailing := &httpdelivery.Handler{
+		Orders:      usecase.NewOrderService(failing, customerAddresses(7), validator),
+		Idempotency: usecase.NewIdempotencyService(failingKeys, usecase.DefaultIdempotencyTTL),
+	}
+
+	// Act: the client goes away while the order is being created
+	cancel()
+	created := postOrder(h, ctx, clientPrincipal(), orderBody)
+	failed := postOrder(hFailing, ctx, clientPrincipal(), orderBody)
+
+	// Assert
+	if created.Code != http.StatusCreated || len(orders.orders) != 1 {
+		t.Fatalf("Expected the order to be created, got %d", created.Code)
+	}
+
+	for _, k := range keys.records {
+		if k.StatusCode != http.StatusCreated {
+			t.Errorf("Expected the 201 to be stored for the key, got %d", k.StatusCode)
+		}
+	}
+
+	if failed.Code != http.StatusInternalServerError || len(failingKeys.records) != 0 {
+		t.Errorf("Expected the key released after a 500, got %d and %d records", failed.Code, len(failingKeys.records))
+	}
+}
+
+func TestCreateOrder_IgnoresServerSetFields(t *testing.T) {
+	// Arrange
+	orders := &mockOrderRepo{}
+	validator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5, IsActive: true},
+	}}
+	h := &httpdelivery.Handler{
+		Orders:      usecase.NewOrderService(orders, customerAddresses(7), validator),
+		Idempotency: usecase.NewIdempotencyService(&mockIdempotencyRepo{}, usecase.DefaultIdempotencyTTL),
+	}
+	body := `{"origin_address_id":1,"destination_address_id":2,"package_type_id":1,"quantity":1,"actual_weight_kg":2.5,` +
+		`"pickup_id":9,"status":"delivered","version":7,"return_of_order_id":3,"order_number":"ORD-X","customer_id":99,"internal_notes":"x"}`
+
+	// Act
+	rec := postOrder(h, context.Background(), clientPrincipal(), body)
+
+	// Assert
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	o := orders.orders[0]
+	if o.PickupID != nil || o.ReturnOfOrderID != nil || o.Version != 0 {
+		t.Errorf("Expected pickup, return and version to be ignored, got %+v", o)
+	}
+
+	if o.Status != domain.OrderCreated || o.OrderNumber == "ORD-X" || o.CustomerID != 7 || o.InternalNotes != "" {
+		t.Errorf("Expected server-set status, number, owner and no internal notes, got %+v", o)
+	}
+}
@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+	"strings"
+	"testing"
+)
+
+func (m *mockPackageTypeValidator) GetPackageTypes(ctx context.Context) (map[uint]domain.PackageType, error) {
+	return m.packageTypes, nil
+}
+
+// mockImportStore restores the repositories when a transaction returns an error
+type mockImportStore struct {
+	orders    *mockOrderRepo
+	addresses *mockAddressRepo
+}
+
+func (m *mockImportStore) Transaction(ctx context.Context, fn func(tx usecase.OrderImportStore) error) error {
+	orders := append([]domain.Order(nil), m.orders.orders...)
+	addresses := append([]domain.Address(nil), m.addresses.addresses...)
+	lastAddrID := m.addresses.lastAddrID
+
+	if err := fn(m); err != nil {
+		m.orders.orders = orders
+		m.addresses.addresses = addresses
+		m.addresses.lastAddrID = lastAddrID
+		return err
+	}
+	return nil
+}
+
+func (m *mockImportStore) Orders() usecase.OrderRepo { return m.orders }
+
+func (m *mockImportStore) Addresses() usecase.AddressRepo { return m.addresses }
+
+func importRecords(rows ...string) [][]string {
+	records := [][]string{strings.Split("origin_street,origin_exterior_number,origin_postal_code,origin_city,origin_state,destination_street,destination_postal_code,destination_city,destination_state,package_size,quantity,weight_kg", ",")}
+	for _, r := range rows {
+		records = append(records, strings.Split(r, ","))
+	}
+	return records
+}
+
+func TestOrderImportService_Import_ReusesAddresses(t *testing.T) {
+	// Arrange
+	store := &mockImportStore{orders: &mockOrderRepo{}, addresses: &mockAddressRepo{}}
+	packageTypes := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	svc := usecase.NewOrderImportService(store, packageTypes)
+	records := importRecords(
+		"Calle 60,123,97000,Mérida,Yucatán,Av. Reforma,06600,CDMX,CDMX,s,1,2.5",
+		"calle 60 ,123,97000,Mérida,Yucatán,Insurgentes,03100,CDMX,CDMX,M,2,10",
+	)
+
+	// Act
+	report, err := svc.Import(context.Background(), domain.Scope{UserID: 7}, records, false, usecase.ImportAtomic)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.Committed || report.Succeeded != 2 || report.Failed != 0 {
+		t.Errorf("Expected 2 committed rows, got %+v", report)
+	}
+
+	if len(store.orders.orders) != 2 {
+		t.Errorf("Expected 2 orders, got %d", len(store.orders.orders))
+	}
+
+	if len(store.addresses.addresses) != 3 {
+		t.Errorf("Expected origin reused and 3 addresses, got %d", len(store.addresses.addresses))
+	}
+
+	if report.Rows[1].Line != 3 || report.Rows[1].OrderNumber == "" {
+		t.Errorf("Expected line 3 with an order number, got %+v", report.Rows[1])
+	}
+}
+
+func TestOrderImportService_Import_AtomicRollsBack(t *testing.T) {
+	// Arrange
+	store := &mockImportStore{orders: &mockOrderRepo{}, addresses: &mockAddressRepo{}}
+	packageTypes := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	svc := usecase.NewOrderImportService(store, packageTypes)
+	records := importRecords(
+		"Calle 60,123,97000,Mérida,Yucatán,Av. Reforma,06600,CDMX,CDMX,S,1,2.5",
+		"Calle 60,123,97000,Mérida,Yucatán,Insurgentes,03100,CDMX,CDMX,S,1,9",
+	)
+
+	// Act
+	report, err := svc.Import(context.Background(), domain.Scope{UserID: 7}, records, false, usecase.ImportAtomic)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Committed || report.Failed != 1 || report.Rows[1].Error == "" {
+		t.Errorf("Expected rollback with line 3 failing, got %+v", report)
+	}
+
+	if len(store.orders.orders) != 0 || len(store.addresses.addresses) != 0 {
+		t.Errorf("Expected nothing stored, got %d orders and %d addresses", len(store.orders.orders), len(store.addresses.addresses))
+	}
+
+	if report.Rows[0].OrderNumber != "" {
+		t.Errorf("Expected no order number on rolled back rows, got %s", report.Rows[0].OrderNumber)
+	}
+}
+
+func TestOrderImportService_Import_PerRowKeepsValidRows(t *testing.T) {
+	// Arrange
+	store := &mockImportStore{orders: &mockOrderRepo{}, addresses: &mockAddressRepo{}}
+	packageTypes := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	svc := usecase.NewOrderImportService(store, packageTypes)
+	records := importRecords(
+		"Calle 60,123,97000,Mérida,Yucatán,Av. Reforma,06600,CDMX,CDMX,S,1,2.5",
+		"Calle 60,123,97000,Mérida,Yucatán,Insurgentes,03100,,CDMX,S,1,2",
+		"Calle 60,123,97000,Mérida,Yucatán,Insurgentes,03100,CDMX,CDMX,XXL,1,2",
+	)
+
+	// Act
+	report, err := svc.Import(context.Background(), domain.Scope{UserID: 7}, records, false, usecase.ImportPerRow)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.Committed || report.Succeeded != 1 || report.Failed != 2 {
+		t.Errorf("Expected 1 stored and 2 failed rows, got %+v", report)
+	}
+
+	if len(store.orders.orders) != 1 || len(store.addresses.addresses) != 2 {
+		t.Errorf("Expected 1 order and 2 addresses, got %d and %d", len(store.orders.orders), len(store.addresses.addresses))
+	}
+
+	if !strings.HasPrefix(report.Rows[1].Error, "destino:") || !strings.Contains(report.Rows[2].Error, "package_size") {
+		t.Errorf("Expected destination and package size errors, got %q and %q", report.Rows[1].Error, report.Rows[2].Error)
+	}
+}
+
+func TestOrderImportService_Import_DryRun(t *testing.T) {
+	// Arrange
+	store := &mockImportStore{orders: &mockOrderRepo{}, addresses: &mockAddressRepo{}}
+	packageTypes := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	svc := usecase.NewOrderImportService(store, packageTypes)
+	records := importRecords("Calle 60,123,97000,Mérida,Yucatán,Av. Reforma,06600,CDMX,CDMX,S,1,2.5")
+
+	// Act
+	report, err := svc.Import(context.Background(), domain.Scope{UserID: 7}, records, true, usecase.ImportPerRow)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Committed || report.Succeeded != 1 || report.Rows[0].CreatedAddresses != 2 {
+		t.Errorf("Expected valid uncommitted row creating 2 addresses, got %+v", report)
+	}
+
+	if len(store.orders.orders) != 0 || len(store.addresses.addresses) != 0 {
+		t.Errorf("Expected nothing stored, got %d orders and %d addresses", len(store.orders.orders), len(store.addresses.addresses))
+	}
+}
+
+func TestOrderImportService_Import_MissingColumns(t *testing.T) {
+	// Arrange
+	store := &mockImportStore{orders: &mockOrderRepo{}, addresses: &mockAddressRepo{}}
+	packageTypes := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	svc := usecase.NewOrderImportService(store, packageTypes)
+	records := [][]string{{"origin_street", "quantity"}, {"Calle 60", "1"}}
+
+	// Act
+	_, err := svc.Import(context.Background(), domain.Scope{UserID: 7}, records, false, usecase.ImportAtomic)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "destination_street") {
+		t.Errorf("Expected missing columns error, got %v", err)
+	}
+}
+
+func TestOrderImportService_Import_ManyRowsGetUniqueNumbers(t *testing.T) {
+	// Arrange
+	store := &mockImportStore{orders: &mockOrderRepo{}, addresses: &mockAddressRepo{}}
+	packageTypes := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageS, MaxWeightKg: 5, IsActive: true},
+		2: {ID: 2, SizeCode: domain.PackageM, MaxWeightKg: 15, IsActive: true},
+	}}
+	svc := usecase.NewOrderImportService(store, packageTypes)
+	rows := make([]string, usecase.MaxImportRows)
+	for i := range rows {
+		rows[i] = fmt.Sprintf("Calle 60,123,97000,Mérida,Yucatán,Calle %d,06600,CDMX,CDMX,S,1,2.5", i)
+	}
+
+	// Act
+	report, err := svc.Import(context.Background(), domain.Scope{UserID: 7}, importRecords(rows...), false, usecase.ImportAtomic)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.Committed || report.Succeeded != usecase.MaxImportRows {
+		t.Fatalf("Expected %d committed rows, got %d succeeded and %d failed", usecase.MaxImportRows, report.Succeeded, report.Failed)
+	}
+
+	seen := make(map[string]bool, len(store.orders.orders))
+	for _, o := range store.orders.orders {
+		if seen[o.OrderNumber] {
+			t.Fatalf("Expected unique order numbers, %s repeated", o.OrderNumber)
+		}
+		seen[o.OrderNumber] = true
+	}
+}
@@ -1,9 +1,12 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
 	"logistics-app/backend/internal/usecase"
+	"strings"
 	"testing"
 	"time"
 )
@@ -12,6 +15,8 @@ type mockOrderRepo struct {
 	orders     []domain.Order
 	shouldFail bool
 	failError  error
+	// duplicates makes the next Create calls fail as if the order number were taken
+	duplicates int
 }
 
 type mockPackageTypeValidator struct {
@@ -20,7 +25,7 @@ type mockPackageTypeValidator struct {
 	packageTypes map[uint]domain.PackageType
 }
 
-func (m *mockPackageTypeValidator) ValidatePackageWeight(packageTypeID uint, weightKg float64) error {
+func (m *mockPackageTypeValidator) ValidatePackageWeight(ctx context.Context, packageTypeID uint, weightKg float64) error {
 	if m.shouldFail {
 		return m.failError
 	}
@@ -47,46 +52,93 @@ func (m *mockPackageTypeValidator) ValidatePackageWeight(packageTypeID uint, wei
 	return nil
 }
 
-func (m *mockOrderRepo) FindByID(id uint) (*domain.Order, error) {
+func (m *mockOrderRepo) FindByID(ctx context.Context, id uint) (*domain.Order, error) {
+	for i := range m.orders {
+		if m.orders[i].ID == id {
+			return &m.orders[i], nil
+		}
+	}
+	return nil, errors.New("order not found")
+}
+
+func (m *mockOrderRepo) FindByCustomer(ctx context.Context, scope domain.Scope) ([]domain.Order, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *mockOrderRepo) FindByCustomer(customerID uint) ([]domain.Order, error) {
+func (m *mockOrderRepo) FindAll(ctx context.Context) ([]domain.Order, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *mockOrderRepo) FindAll() ([]domain.Order, error) {
+func (m *mockOrderRepo) UpdateStatus(ctx context.Context, id uint, version uint, internalNotes string, status domain.OrderStatus, changedBy uint) (uint, error) {
+	o, err := m.FindByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if o.Status.Terminal() {
+		return 0, repository.ErrTerminalStatus
+	}
+	o.Status = status
+	o.Version++
+	return o.Version, nil
+}
+
+func (m *mockOrderRepo) FindJoinedByCustomer(ctx context.Context, scope domain.Scope) ([]domain.OrderListItem, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *mockOrderRepo) UpdateStatus(id uint, status domain.OrderStatus, changedBy uint) error {
+func (m *mockOrderRepo) FindJoinedAll(ctx context.Context) ([]domain.OrderListItem, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *mockOrderRepo) FindJoinedByCustomer(customerID uint) ([]domain.OrderListItem, error) {
+func (m *mockOrderRepo) FindDetailByID(ctx context.Context, id uint) (*domain.OrderDetail, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *mockOrderRepo) FindJoinedAll() ([]domain.OrderListItem, error) {
+func (m *mockOrderRepo) FindHistory(ctx context.Context, orderID uint) ([]domain.OrderStatusHistory, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *mockOrderRepo) FindDetailByID(id uint) (*domain.OrderDetail, error) {
+func (m *mockOrderRepo) FindReturnOf(ctx context.Context, orderID uint) (*domain.Order, error) {
+	for i := range m.orders {
+		if r := m.orders[i].ReturnOfOrderID; r != nil && *r == orderID {
+			return &m.orders[i], nil
+		}
+	}
+	return nil, errors.New("order not found")
+}
+
+func (m *mockOrderRepo) FindDeliveryStops(ctx context.Context) ([]domain.DeliveryStop, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m *mockOrderRepo) Create(o *domain.Order) error {
+func (m *mockOrderRepo) StreamJoined(ctx context.Context, scope *domain.Scope, fn func(domain.OrderExportRow) error) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *mockOrderRepo) Create(ctx context.Context, o *domain.Order) error {
 	if m.shouldFail {
 		return m.failError
 	}
 
+	if m.duplicates > 0 {
+		m.duplicates--
+		return repository.ErrDuplicateOrderNumber
+	}
+
+	for _, existing := range m.orders {
+		if existing.OrderNumber == o.OrderNumber {
+			return repository.ErrDuplicateOrderNumber
+		}
+	}
+
 	o.ID = uint(len(m.orders) + 1)
 	o.CreatedAt = time.Now()
 	o.UpdatedAt = time.Now()
@@ -95,6 +147,14 @@ func (m *mockOrderRepo) Create(o *domain.Order) error {
 	return nil
 }
 
+// customerAddresses returns an address repo holding active addresses 1 and 2 of the customer
+func customerAddresses(customerID uint) *mockAddressRepo {
+	return &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: customerID, IsActive: true},
+		{ID: 2, CustomerID: customerID, IsActive: true},
+	}}
+}
+
 func TestOrderService_Create_Success(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
@@ -108,7 +168,7 @@ func TestOrderService_Create_Success(t *testing.T) {
 			},
 		},
 	}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -122,7 +182,7 @@ func TestOrderService_Create_Success(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err != nil {
@@ -146,7 +206,7 @@ func TestOrderService_Create_MissingWeight(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -159,7 +219,7 @@ func TestOrderService_Create_MissingWeight(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -176,7 +236,7 @@ func TestOrderService_Create_NegativeWeight(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -189,7 +249,7 @@ func TestOrderService_Create_NegativeWeight(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -209,7 +269,7 @@ func TestOrderService_Create_PackageTypeNotFound(t *testing.T) {
 		shouldFail: true,
 		failError:  errors.New("tipo de paquete no encontrado"),
 	}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -222,7 +282,7 @@ func TestOrderService_Create_PackageTypeNotFound(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -248,7 +308,7 @@ func TestOrderService_Create_WeightExceedsPackageLimit(t *testing.T) {
 			},
 		},
 	}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -261,7 +321,7 @@ func TestOrderService_Create_WeightExceedsPackageLimit(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -287,7 +347,7 @@ func TestOrderService_Create_WeightExceedsStandardLimit(t *testing.T) {
 			},
 		},
 	}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -300,7 +360,7 @@ func TestOrderService_Create_WeightExceedsStandardLimit(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -317,7 +377,7 @@ func TestOrderService_Create_MissingOriginAddress(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		DestinationAddressID: 2,
@@ -329,7 +389,7 @@ func TestOrderService_Create_MissingOriginAddress(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -346,7 +406,7 @@ func TestOrderService_Create_MissingDestinationAddress(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID: 1,
@@ -358,7 +418,7 @@ func TestOrderService_Create_MissingDestinationAddress(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -375,7 +435,7 @@ func TestOrderService_Create_SameOriginAndDestination(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -388,7 +448,7 @@ func TestOrderService_Create_SameOriginAndDestination(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -401,11 +461,78 @@ func TestOrderService_Create_SameOriginAndDestination(t *testing.T) {
 	}
 }
 
+func TestOrderService_Create_ForeignAddress(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{}
+	mockValidator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5, IsActive: true},
+	}}
+	addresses := customerAddresses(1)
+	addresses.addresses = append(addresses.addresses, domain.Address{ID: 3, CustomerID: 2, IsActive: true})
+	service := usecase.NewOrderService(mockRepo, addresses, mockValidator)
+
+	order := &domain.Order{
+		OriginAddressID:      3,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalid) {
+		t.Fatalf("Expected ErrInvalid for another customer's address, got %v", err)
+	}
+	if err.Error() != "dirección de origen no encontrada" {
+		t.Errorf("Unexpected error message '%s'", err.Error())
+	}
+	if len(mockRepo.orders) != 0 {
+		t.Errorf("Expected no order to be created, got %d", len(mockRepo.orders))
+	}
+}
+
+func TestOrderService_Create_InactiveAddress(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{}
+	mockValidator := &mockPackageTypeValidator{packageTypes: map[uint]domain.PackageType{
+		1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5, IsActive: true},
+	}}
+	addresses := customerAddresses(1)
+	addresses.addresses[1].IsActive = false
+	service := usecase.NewOrderService(mockRepo, addresses, mockValidator)
+
+	order := &domain.Order{
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrInvalid) {
+		t.Fatalf("Expected ErrInvalid for an inactive address, got %v", err)
+	}
+	if err.Error() != "la dirección de destino no está activa" {
+		t.Errorf("Unexpected error message '%s'", err.Error())
+	}
+}
+
 func TestOrderService_Create_MissingPackageType(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -417,7 +544,7 @@ func TestOrderService_Create_MissingPackageType(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -434,7 +561,7 @@ func TestOrderService_Create_MissingCustomerID(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	order := &domain.Order{
 		OriginAddressID:      1,
@@ -446,7 +573,7 @@ func TestOrderService_Create_MissingCustomerID(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err == nil {
@@ -463,7 +590,7 @@ func TestOrderService_Create_WithCustomOrderNumber(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	customOrderNumber := "CUSTOM-12345"
 	order := &domain.Order{
@@ -478,7 +605,7 @@ func TestOrderService_Create_WithCustomOrderNumber(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err != nil {
@@ -490,11 +617,74 @@ func TestOrderService_Create_WithCustomOrderNumber(t *testing.T) {
 	}
 }
 
+func TestOrderService_Create_RetriesTakenOrderNumber(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{duplicates: 2}
+	mockValidator := &mockPackageTypeValidator{
+		packageTypes: map[uint]domain.PackageType{
+			1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5.0, IsActive: true},
+		},
+	}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
+
+	order := &domain.Order{
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected the order number to be regenerated, got %v", err)
+	}
+
+	if len(mockRepo.orders) != 1 || !strings.HasPrefix(order.OrderNumber, "ORD-") {
+		t.Errorf("Expected 1 order with an ORD- number, got %d orders and %q", len(mockRepo.orders), order.OrderNumber)
+	}
+}
+
+func TestOrderService_Create_CustomOrderNumberTaken(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{orders: []domain.Order{{ID: 1, OrderNumber: "CUSTOM-001"}}}
+	mockValidator := &mockPackageTypeValidator{
+		packageTypes: map[uint]domain.PackageType{
+			1: {ID: 1, SizeCode: domain.PackageM, MaxWeightKg: 5.0, IsActive: true},
+		},
+	}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
+
+	order := &domain.Order{
+		OrderNumber:          "CUSTOM-001",
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict for a supplied number, got %v", err)
+	}
+}
+
 func TestOrderService_Create_WithCustomStatus(t *testing.T) {
 	// Arrange
 	mockRepo := &mockOrderRepo{}
 	mockValidator := &mockPackageTypeValidator{}
-	service := usecase.NewOrderService(mockRepo, mockValidator)
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), mockValidator)
 
 	customStatus := domain.OrderCollected
 	order := &domain.Order{
@@ -509,7 +699,7 @@ func TestOrderService_Create_WithCustomStatus(t *testing.T) {
 	}
 
 	// Act
-	err := service.Create(order)
+	err := service.Create(context.Background(), order)
 
 	// Assert
 	if err != nil {
@@ -520,3 +710,213 @@ func TestOrderService_Create_WithCustomStatus(t *testing.T) {
 		t.Errorf("Expected custom status '%s', got '%s'", customStatus, order.Status)
 	}
 }
+
+func TestOrderService_CreateReturn_SwapsAddresses(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{orders: []domain.Order{{
+		ID:                   1,
+		OrderNumber:          "ORD-20261019-123",
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             2,
+		ActualWeightKg:       3.5,
+		Status:               domain.OrderDelivered,
+	}}}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	// Act
+	ret, err := service.CreateReturn(context.Background(), domain.Scope{UserID: 1}, false, 1, "Producto dañado")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ret.OriginAddressID != 2 || ret.DestinationAddressID != 1 {
+		t.Errorf("Expected swapped addresses 2->1, got %d->%d", ret.OriginAddressID, ret.DestinationAddressID)
+	}
+
+	if ret.ReturnOfOrderID == nil || *ret.ReturnOfOrderID != 1 {
+		t.Errorf("Expected return linked to order 1, got %v", ret.ReturnOfOrderID)
+	}
+
+	if ret.OrderNumber != "RET-20261019-123" {
+		t.Errorf("Expected order number RET-20261019-123, got %s", ret.OrderNumber)
+	}
+
+	if ret.Status != domain.OrderCreated {
+		t.Errorf("Expected status %v, got %v", domain.OrderCreated, ret.Status)
+	}
+}
+
+func TestOrderService_CreateReturn_Duplicate(t *testing.T) {
+	// Arrange
+	origID := uint(1)
+	mockRepo := &mockOrderRepo{orders: []domain.Order{
+		{ID: 1, OrderNumber: "ORD-1", CustomerID: 1, Status: domain.OrderDelivered},
+		{ID: 2, OrderNumber: "RET-1", CustomerID: 1, Status: domain.OrderCreated, ReturnOfOrderID: &origID},
+	}}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	// Act
+	_, err := service.CreateReturn(context.Background(), domain.Scope{UserID: 1}, false, 1, "")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for duplicate return, got nil")
+	}
+}
+
+func TestOrderService_CreateReturn_NotDelivered(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{orders: []domain.Order{{ID: 1, CustomerID: 1, Status: domain.OrderInRoute}}}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	// Act
+	_, err := service.CreateReturn(context.Background(), domain.Scope{UserID: 1}, false, 1, "")
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for order not delivered, got nil")
+	}
+}
+
+func TestOrderService_Create_WithDeliveryPreferences(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	order := &domain.Order{
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+		DeliveryPreferences: domain.DeliveryPreferences{
+			WindowStart:        "09:00",
+			WindowEnd:          "12:00",
+			LeaveWith:          domain.LeaveWithConcierge,
+			AccessInstructions: "Tocar el timbre 3",
+			RecipientName:      "María López",
+			RecipientPhone:     "+52 999 123 4567",
+		},
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestOrderService_Create_InvalidDeliveryWindow(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	order := &domain.Order{
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+		DeliveryPreferences:  domain.DeliveryPreferences{WindowStart: "14:00", WindowEnd: "10:00"},
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for inverted delivery window, got nil")
+	}
+
+	if len(mockRepo.orders) != 0 {
+		t.Errorf("Expected no orders in repository, got %d", len(mockRepo.orders))
+	}
+}
+
+func TestOrderService_Create_RecipientWithoutPhone(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	order := &domain.Order{
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+		DeliveryPreferences:  domain.DeliveryPreferences{RecipientName: "María López"},
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	expectedError := "recipient_name y recipient_phone deben indicarse juntos"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error message '%s', got '%v'", expectedError, err)
+	}
+}
+
+func TestOrderService_Create_InvalidLeaveWith(t *testing.T) {
+	// Arrange
+	mockRepo := &mockOrderRepo{}
+	service := usecase.NewOrderService(mockRepo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	order := &domain.Order{
+		OriginAddressID:      1,
+		DestinationAddressID: 2,
+		PackageTypeID:        1,
+		CustomerID:           1,
+		CreatedBy:            1,
+		Quantity:             1,
+		ActualWeightKg:       2.5,
+		DeliveryPreferences:  domain.DeliveryPreferences{LeaveWith: "mailbox"},
+	}
+
+	// Act
+	err := service.Create(context.Background(), order)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for invalid leave_with, got nil")
+	}
+}
+
+func TestOrderService_UpdateStatus_TerminalStatuses(t *testing.T) {
+	// Arrange
+	repo := &mockOrderRepo{orders: []domain.Order{
+		{ID: 1, Status: domain.OrderInRoute, Version: 1},
+		{ID: 2, Status: domain.OrderReturned, Version: 1},
+		{ID: 3, Status: domain.OrderDelivered, Version: 1},
+		{ID: 4, Status: domain.OrderCancelled, Version: 1},
+	}}
+	service := usecase.NewOrderService(repo, customerAddresses(1), &mockPackageTypeValidator{})
+
+	// Act
+	_, err := service.UpdateStatus(context.Background(), 1, 1, "", domain.OrderDelivered, 9)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, id := range []uint{2, 3, 4} {
+		if _, err := service.UpdateStatus(context.Background(), id, 1, "", domain.OrderInRoute, 9); !errors.Is(err, usecase.ErrConflict) {
+			t.Errorf("Expected ErrConflict leaving a terminal status on order %d, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/repository"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+)
+
+type mockOrganizationRepo struct {
+	users       *mockUserRepo
+	orgs        []domain.Organization
+	invitations []domain.OrganizationInvitation
+}
+
+func (m *mockOrganizationRepo) join(userID, orgID uint, role domain.OrgRole) error {
+	u, err := m.users.FindByID(context.Background(), userID)
+	if err != nil {
+		return err
+	}
+	if u.OrganizationID != nil {
+		return repository.ErrAlreadyMember
+	}
+	u.OrganizationID = &orgID
+	u.OrgRole = role
+	return nil
+}
+
+func (m *mockOrganizationRepo) Create(ctx context.Context, o *domain.Organization) error {
+	o.ID = uint(len(m.orgs) + 1)
+	m.orgs = append(m.orgs, *o)
+	return m.join(o.CreatedBy, o.ID, domain.OrgOwner)
+}
+
+func (m *mockOrganizationRepo) FindByID(ctx context.Context, id uint) (*domain.Organization, error) {
+	if id == 0 || int(id) > len(m.orgs) {
+		return nil, errors.New("record not found")
+	}
+	o := m.orgs[id-1]
+	o.Members = nil
+	for _, u := range m.users.users {
+		if u.OrganizationID != nil && *u.OrganizationID == id {
+			o.Members = append(o.Members, u)
+		}
+	}
+	return &o, nil
+}
+
+func (m *mockOrganizationRepo) CreateInvitation(ctx context.Context, inv *domain.OrganizationInvitation) error {
+	inv.ID = uint(len(m.invitations) + 1)
+	m.invitations = append(m.invitations, *inv)
+	return nil
+}
+
+func (m *mockOrganizationRepo) FindInvitationByHash(ctx context.Context, hash string) (*domain.OrganizationInvitation, error) {
+	for i := range m.invitations {
+		if m.invitations[i].TokenHash == hash {
+			inv := m.invitations[i]
+			return &inv, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockOrganizationRepo) AcceptInvitation(ctx context.Context, inv *domain.OrganizationInvitation, userID uint) error {
+	now := time.Now()
+	m.invitations[inv.ID-1].AcceptedAt = &now
+	return m.join(userID, inv.OrganizationID, inv.Role)
+}
+
+func (m *mockOrganizationRepo) UpdateMemberRole(ctx context.Context, orgID, userID uint, role domain.OrgRole) error {
+	u, _ := m.users.FindByID(ctx, userID)
+	u.OrgRole = role
+	return nil
+}
+
+func (m *mockOrganizationRepo) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	u, _ := m.users.FindByID(ctx, userID)
+	u.OrganizationID = nil
+	u.OrgRole = ""
+	return nil
+}
+
+func scopeOf(u domain.User) domain.Scope {
+	return domain.Scope{UserID: u.ID, OrganizationID: u.OrganizationID, OrgRole: u.OrgRole}
+}
+
+func TestOrganizationService_InviteAndAccept(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "sofia@example.com", Role: domain.RoleClient, IsActive: true},
+	}}
+	mailer := &mockMailer{}
+	service := usecase.NewOrganizationService(&mockOrganizationRepo{users: users}, users, mailer, usecase.OrganizationConfig{BaseURL: "http://app.test/"})
+	o, err := service.Create(context.Background(), 1, "Acme")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.Invite(context.Background(), scopeOf(users.users[0]), "luis@example.com", domain.OrgShipper); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	token := lastMailToken(t, mailer)
+
+	// Act
+	joined, err := service.Accept(context.Background(), 2, token)
+	reuseErr := func() error { _, err := service.Accept(context.Background(), 2, token); return err }()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if joined.ID != o.ID || len(joined.Members) != 2 {
+		t.Errorf("Expected 2 members in organization %d, got %+v", o.ID, joined)
+	}
+
+	luis := users.users[1]
+	if luis.OrganizationID == nil || *luis.OrganizationID != o.ID || luis.OrgRole != domain.OrgShipper {
+		t.Errorf("Expected luis to be a shipper of the organization, got %+v", luis)
+	}
+
+	if !errors.Is(reuseErr, usecase.ErrUnprocessable) {
+		t.Errorf("Expected ErrUnprocessable on reuse, got %v", reuseErr)
+	}
+}
+
+func TestOrganizationService_Accept_OtherEmail(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "sofia@example.com", Role: domain.RoleClient, IsActive: true},
+	}}
+	mailer := &mockMailer{}
+	service := usecase.NewOrganizationService(&mockOrganizationRepo{users: users}, users, mailer, usecase.OrganizationConfig{BaseURL: "http://app.test/"})
+	if _, err := service.Create(context.Background(), 1, "Acme"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, _ = service.Invite(context.Background(), scopeOf(users.users[0]), "luis@example.com", domain.OrgViewer)
+
+	// Act
+	_, err := service.Accept(context.Background(), 3, lastMailToken(t, mailer))
+
+	// Assert
+	if !errors.Is(err, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+
+	if users.users[2].OrganizationID != nil {
+		t.Error("Expected sofia to stay outside the organization")
+	}
+}
+
+func TestOrganizationService_Invite_OwnerOnly(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "sofia@example.com", Role: domain.RoleClient, IsActive: true},
+	}}
+	mailer := &mockMailer{}
+	service := usecase.NewOrganizationService(&mockOrganizationRepo{users: users}, users, mailer, usecase.OrganizationConfig{BaseURL: "http://app.test/"})
+	if _, err := service.Create(context.Background(), 1, "Acme"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, _ = service.Invite(context.Background(), scopeOf(users.users[0]), "luis@example.com", domain.OrgShipper)
+	_, _ = service.Accept(context.Background(), 2, lastMailToken(t, mailer))
+
+	// Act
+	_, err := service.Invite(context.Background(), scopeOf(users.users[1]), "sofia@example.com", domain.OrgShipper)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestOrganizationService_KeepsAnOwner(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "sofia@example.com", Role: domain.RoleClient, IsActive: true},
+	}}
+	mailer := &mockMailer{}
+	service := usecase.NewOrganizationService(&mockOrganizationRepo{users: users}, users, mailer, usecase.OrganizationConfig{BaseURL: "http://app.test/"})
+	if _, err := service.Create(context.Background(), 1, "Acme"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, _ = service.Invite(context.Background(), scopeOf(users.users[0]), "luis@example.com", domain.OrgViewer)
+	_, _ = service.Accept(context.Background(), 2, lastMailToken(t, mailer))
+	owner := scopeOf(users.users[0])
+
+	// Act
+	demoteErr := service.UpdateMemberRole(context.Background(), owner, 1, domain.OrgShipper)
+	leaveErr := service.RemoveMember(context.Background(), owner, 1)
+	viewerLeaves := service.RemoveMember(context.Background(), scopeOf(users.users[1]), 2)
+
+	// Assert
+	if !errors.Is(demoteErr, usecase.ErrConflict) || !errors.Is(leaveErr, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict for the last owner, got %v and %v", demoteErr, leaveErr)
+	}
+
+	if viewerLeaves != nil {
+		t.Errorf("Expected a member to be able to leave, got %v", viewerLeaves)
+	}
+
+	if users.users[1].OrganizationID != nil {
+		t.Error("Expected luis to be out of the organization")
+	}
+}
+
+func TestScope_SeesAndWritable(t *testing.T) {
+	org, other := uint(5), uint(6)
+	viewer := domain.Scope{UserID: 1, OrganizationID: &org, OrgRole: domain.OrgViewer}
+	shipper := domain.Scope{UserID: 1, OrganizationID: &org, OrgRole: domain.OrgShipper}
+
+	if !viewer.Sees(2, &org) || !viewer.Sees(1, nil) {
+		t.Error("Expected members to see the organization's rows and their own")
+	}
+
+	if viewer.Sees(1, &other) || viewer.Sees(2, nil) {
+		t.Error("Expected members not to see other organizations or other customers")
+	}
+
+	if viewer.Writable().Sees(2, &org) || !shipper.Writable().Sees(2, &org) {
+		t.Error("Expected only shippers and owners to change the organization's rows")
+	}
+
+	u := domain.User{Role: domain.RoleClient, OrganizationID: &org, OrgRole: domain.OrgViewer}
+	for _, p := range u.Permissions() {
+		if p == domain.PermOrdersCreate || p == domain.PermAddressesCreate {
+			t.Errorf("Expected viewers not to be granted %s", p)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"testing"
+
+	"logistics-app/backend/internal/domain"
+)
+
+func TestAdminHasEveryPermission(t *testing.T) {
+	for _, p := range domain.AllPermissions {
+		if !domain.RoleAdmin.Can(p) {
+			t.Errorf("admin should have %s", p)
+		}
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	cases := []struct {
+		role    domain.Role
+		allowed []domain.Permission
+		denied  []domain.Permission
+	}{
+		{domain.RoleClient,
+			[]domain.Permission{domain.PermOrdersCreate, domain.PermAddressesCreate},
+			[]domain.Permission{domain.PermOrdersReadAll, domain.PermOrdersStatusUpdate, domain.PermUsersDelete}},
+		{domain.RoleDispatcher,
+			[]domain.Permission{domain.PermOrdersStatusUpdate, domain.PermDeliveriesRoute, domain.PermManifestsManage},
+			[]domain.Permission{domain.PermPackageTypesManage, domain.PermUsersDelete, domain.PermOrdersCreate}},
+		{domain.RoleStationClerk,
+			[]domain.Permission{domain.PermScansRecord, domain.PermOrdersReadAll},
+			[]domain.Permission{domain.PermOrdersStatusUpdate, domain.PermStationsManage}},
+		{domain.RoleSupport,
+			[]domain.Permission{domain.PermOrdersManage, domain.PermAddressesManageAll, domain.PermUsersReadAll},
+			[]domain.Permission{domain.PermUsersDelete, domain.PermOrdersExportAll}},
+		{domain.RoleFinance,
+			[]domain.Permission{domain.PermOrdersExportAll, domain.PermOrdersReadAll},
+			[]domain.Permission{domain.PermOrdersManage, domain.PermScansRecord}},
+	}
+	for _, c := range cases {
+		for _, p := range c.allowed {
+			if !c.role.Can(p) {
+				t.Errorf("%s should have %s", c.role, p)
+			}
+		}
+		for _, p := range c.denied {
+			if c.role.Can(p) {
+				t.Errorf("%s should not have %s", c.role, p)
+			}
+		}
+	}
+}
+
+func TestUnknownRoleHasNoPermissions(t *testing.T) {
+	r := domain.Role("superuser")
+	if r.Valid() || len(r.Permissions()) != 0 || r.Can(domain.PermOrdersCreate) {
+		t.Fatal("unknown roles must not be granted anything")
+	}
+	for _, known := range domain.Roles {
+		if !known.Valid() {
+			t.Errorf("%s should be valid", known)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+)
+
+type mockPickupRepo struct {
+	slots   []domain.PickupSlot
+	pickups []domain.Pickup
+}
+
+func (m *mockPickupRepo) FindSlot(ctx context.Context, zone, start, end string) (*domain.PickupSlot, error) {
+	var fallback *domain.PickupSlot
+	for i, s := range m.slots {
+		if s.WindowStart != start || s.WindowEnd != end || !s.IsActive {
+			continue
+		}
+		if s.Zone == zone {
+			return &m.slots[i], nil
+		}
+		if s.Zone == "" {
+			fallback = &m.slots[i]
+		}
+	}
+	if fallback == nil {
+		return nil, errors.New("record not found")
+	}
+	return fallback, nil
+}
+
+func (m *mockPickupRepo) ListSlots(ctx context.Context) ([]domain.PickupSlot, error) {
+	return m.slots, nil
+}
+
+func (m *mockPickupRepo) SaveSlot(ctx context.Context, s *domain.PickupSlot) error {
+	m.slots = append(m.slots, *s)
+	return nil
+}
+
+func (m *mockPickupRepo) Save(ctx context.Context, p *domain.Pickup, slot *domain.PickupSlot, attach []uint) error {
+	var booked uint
+	for _, other := range m.pickups {
+		if other.ID != p.ID && other.Zone == p.Zone && other.PickupDate.Equal(p.PickupDate) &&
+			other.WindowStart == p.WindowStart && other.WindowEnd == p.WindowEnd && other.Status == domain.PickupScheduled {
+			booked++
+		}
+	}
+	if booked >= slot.Capacity {
+		return errors.New("pickup slot is full")
+	}
+	if p.ID == 0 {
+		p.ID = uint(len(m.pickups) + 1)
+		m.pickups = append(m.pickups, *p)
+		return nil
+	}
+	m.pickups[p.ID-1] = *p
+	return nil
+}
+
+func (m *mockPickupRepo) FindByID(ctx context.Context, id uint) (*domain.Pickup, error) {
+	if id == 0 || int(id) > len(m.pickups) {
+		return nil, errors.New("record not found")
+	}
+	p := m.pickups[id-1]
+	return &p, nil
+}
+
+func (m *mockPickupRepo) List(ctx context.Context, scope *domain.Scope, date *time.Time) ([]domain.Pickup, error) {
+	return m.pickups, nil
+}
+
+func (m *mockPickupRepo) Cancel(ctx context.Context, id uint, changedBy uint) error {
+	m.pickups[id-1].Status = domain.PickupCancelled
+	return nil
+}
+
+func tomorrow() string {
+	return time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+}
+
+func TestPickupService_Book_Success(t *testing.T) {
+	// Arrange
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, Street: "Calle 60", City: "Mérida", State: "Yucatán", PostalCode: "97000", IsActive: true},
+	}}
+	repo := &mockPickupRepo{slots: []domain.PickupSlot{
+		{ID: 1, Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 5, IsActive: true},
+	}}
+	service := usecase.NewPickupService(repo, addresses)
+
+	// Act
+	p, err := service.Book(context.Background(), domain.Scope{UserID: 10}, false, usecase.PickupRequest{
+		OriginAddressID: 1,
+		Date:            tomorrow(),
+		WindowStart:     "09:00",
+		WindowEnd:       "13:00",
+		OrderIDs:        []uint{3, 3, 4},
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if p.Zone != "970" {
+		t.Errorf("Expected zone 970, got %s", p.Zone)
+	}
+
+	if len(p.OrderIDs) != 2 {
+		t.Errorf("Expected 2 grouped orders, got %d", len(p.OrderIDs))
+	}
+
+	if len(repo.pickups) != 1 {
+		t.Errorf("Expected 1 pickup in repository, got %d", len(repo.pickups))
+	}
+}
+
+func TestPickupService_Book_SlotFull(t *testing.T) {
+	// Arrange
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, Street: "Calle 60", City: "Mérida", State: "Yucatán", PostalCode: "97000", IsActive: true},
+	}}
+	repo := &mockPickupRepo{slots: []domain.PickupSlot{
+		{ID: 1, Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 1, IsActive: true},
+	}}
+	service := usecase.NewPickupService(repo, addresses)
+	req := usecase.PickupRequest{OriginAddressID: 1, Date: tomorrow(), WindowStart: "09:00", WindowEnd: "13:00"}
+	if _, err := service.Book(context.Background(), domain.Scope{UserID: 10}, false, req); err != nil {
+		t.Fatalf("Expected first booking to succeed, got %v", err)
+	}
+
+	// Act
+	_, err := service.Book(context.Background(), domain.Scope{UserID: 10}, false, req)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for full slot, got nil")
+	}
+}
+
+func TestPickupService_Book_UnknownWindow(t *testing.T) {
+	// Arrange
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, Street: "Calle 60", City: "Mérida", State: "Yucatán", PostalCode: "97000", IsActive: true},
+	}}
+	repo := &mockPickupRepo{slots: []domain.PickupSlot{
+		{ID: 1, Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 5, IsActive: true},
+	}}
+	service := usecase.NewPickupService(repo, addresses)
+
+	// Act
+	_, err := service.Book(context.Background(), domain.Scope{UserID: 10}, false, usecase.PickupRequest{OriginAddressID: 1, Date: tomorrow(), WindowStart: "18:00", WindowEnd: "20:00"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for window without slot, got nil")
+	}
+}
+
+func TestPickupService_Book_PastDate(t *testing.T) {
+	// Arrange
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, Street: "Calle 60", City: "Mérida", State: "Yucatán", PostalCode: "97000", IsActive: true},
+	}}
+	repo := &mockPickupRepo{slots: []domain.PickupSlot{
+		{ID: 1, Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 5, IsActive: true},
+	}}
+	service := usecase.NewPickupService(repo, addresses)
+
+	// Act
+	_, err := service.Book(context.Background(), domain.Scope{UserID: 10}, false, usecase.PickupRequest{
+		OriginAddressID: 1,
+		Date:            time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
+		WindowStart:     "09:00",
+		WindowEnd:       "13:00",
+	})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for past date, got nil")
+	}
+}
+
+func TestPickupService_Book_WindowAlreadyEnded(t *testing.T) {
+	// Arrange
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, Street: "Calle 60", City: "Mérida", State: "Yucatán", PostalCode: "97000", IsActive: true},
+	}}
+	repo := &mockPickupRepo{slots: []domain.PickupSlot{
+		{ID: 1, Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 5, IsActive: true},
+	}}
+	service := usecase.NewPickupService(repo, addresses)
+
+	// Act
+	_, err := service.Book(context.Background(), domain.Scope{UserID: 10}, false, usecase.PickupRequest{
+		OriginAddressID: 1,
+		Date:            time.Now().Format("2006-01-02"),
+		WindowStart:     "00:00",
+		WindowEnd:       "00:01",
+	})
+
+	// Assert
+	if err == nil || err.Error() != "la ventana de recolección ya terminó" {
+		t.Fatalf("Expected error for a window that already ended today, got %v", err)
+	}
+}
+
+func TestPickupService_Book_ForeignAddress(t *testing.T) {
+	// Arrange
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, Street: "Calle 60", City: "Mérida", State: "Yucatán", PostalCode: "97000", IsActive: true},
+	}}
+	repo := &mockPickupRepo{slots: []domain.PickupSlot{
+		{ID: 1, Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 5, IsActive: true},
+	}}
+	service := usecase.NewPickupService(repo, addresses)
+
+	// Act
+	_, err := service.Book(context.Background(), domain.Scope{UserID: 11}, false, usecase.PickupRequest{OriginAddressID: 1, Date: tomorrow(), WindowStart: "09:00", WindowEnd: "13:00"})
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for address of another customer, got nil")
+	}
+}
+
+func TestPickupService_Cancel_NotOwner(t *testing.T) {
+	// Arrange
+	addresses := &mockAddressRepo{addresses: []domain.Address{
+		{ID: 1, CustomerID: 10, Street: "Calle 60", City: "Mérida", State: "Yucatán", PostalCode: "97000", IsActive: true},
+	}}
+	repo := &mockPickupRepo{slots: []domain.PickupSlot{
+		{ID: 1, Zone: "", WindowStart: "09:00", WindowEnd: "13:00", Capacity: 5, IsActive: true},
+	}}
+	service := usecase.NewPickupService(repo, addresses)
+	p, err := service.Book(context.Background(), domain.Scope{UserID: 10}, false, usecase.PickupRequest{OriginAddressID: 1, Date: tomorrow(), WindowStart: "09:00", WindowEnd: "13:00"})
+	if err != nil {
+		t.Fatalf("Expected booking to succeed, got %v", err)
+	}
+
+	// Act
+	err = service.Cancel(context.Background(), domain.Scope{UserID: 11}, false, p.ID)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
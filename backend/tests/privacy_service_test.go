@@ -0,0 +1,321 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpdelivery "logistics-app/backend/internal/delivery/http"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/export"
+	"logistics-app/backend/internal/repository"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/gorilla/mux"
+)
+
+type mockPrivacyRepo struct {
+	users     *mockUserRepo
+	addresses []domain.Address
+	orders    []domain.Order
+	requests  []domain.ErasureRequest
+}
+
+func (m *mockPrivacyRepo) PersonalData(ctx context.Context, userID uint) (*domain.PersonalData, error) {
+	u, err := m.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	d := &domain.PersonalData{Profile: *u}
+	for _, a := range m.addresses {
+		if a.CustomerID == userID {
+			d.Addresses = append(d.Addresses, a)
+		}
+	}
+	for _, o := range m.orders {
+		if o.CustomerID == userID {
+			d.Orders = append(d.Orders, o)
+		}
+	}
+	return d, nil
+}
+
+func (m *mockPrivacyRepo) CreateErasureRequest(ctx context.Context, e *domain.ErasureRequest) error {
+	e.ID = uint(len(m.requests) + 1)
+	m.requests = append(m.requests, *e)
+	return nil
+}
+
+func (m *mockPrivacyRepo) FindErasureRequest(ctx context.Context, id uint) (*domain.ErasureRequest, error) {
+	if id == 0 || int(id) > len(m.requests) {
+		return nil, errors.New("record not found")
+	}
+	e := m.requests[id-1]
+	return &e, nil
+}
+
+func (m *mockPrivacyRepo) HasPendingErasure(ctx context.Context, userID uint) (bool, error) {
+	for _, e := range m.requests {
+		if e.UserID == userID && e.Status == domain.ErasurePending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockPrivacyRepo) ListErasureRequests(ctx context.Context, status domain.ErasureStatus) ([]domain.ErasureRequest, error) {
+	var list []domain.ErasureRequest
+	for _, e := range m.requests {
+		if status == "" || e.Status == status {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockPrivacyRepo) review(id uint, status domain.ErasureStatus, by uint, note string) error {
+	e := &m.requests[id-1]
+	if e.Status != domain.ErasurePending {
+		return repository.ErrTokenUsed
+	}
+	now := time.Now()
+	e.Status, e.ReviewedBy, e.ReviewedAt, e.ReviewNote = status, &by, &now, note
+	return nil
+}
+
+func (m *mockPrivacyRepo) RejectErasure(ctx context.Context, id, by uint, note string) error {
+	return m.review(id, domain.ErasureRejected, by, note)
+}
+
+func (m *mockPrivacyRepo) Erase(ctx context.Context, e *domain.ErasureRequest, by uint, note string) error {
+	if err := m.review(e.ID, domain.ErasureCompleted, by, note); err != nil {
+		return err
+	}
+	for i := range m.addresses {
+		if m.addresses[i].CustomerID == e.UserID && m.addresses[i].OrganizationID == nil {
+			m.addresses[i].Street, m.addresses[i].ExteriorNumber = "", ""
+			m.addresses[i].IsActive = false
+		}
+	}
+	return m.users.SoftDelete(ctx, e.UserID, by)
+}
+
+func TestPrivacyService_Export(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin, IsActive: true},
+		{ID: 2, Email: "ana@example.com", FullName: "Ana Ruiz", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "eva@example.com", Role: domain.RoleAdmin, IsActive: true},
+	}}
+	org := uint(4)
+	repo := &mockPrivacyRepo{
+		users: users,
+		addresses: []domain.Address{
+			{ID: 1, CustomerID: 2, Street: "Reforma", ExteriorNumber: "10", City: "CDMX", IsActive: true},
+			{ID: 2, CustomerID: 2, OrganizationID: &org, Street: "Insurgentes", City: "CDMX", IsActive: true},
+		},
+		orders: []domain.Order{{ID: 1, OrderNumber: "ORD-1", CustomerID: 2, OriginAddressID: 1, DestinationAddressID: 2}},
+	}
+	audit := &mockAuditRepo{}
+	service := usecase.NewPrivacyService(repo, usecase.NewUserService(users, audit), audit)
+
+	// Act
+	d, err := service.Export(context.Background(), 2, "10.0.0.2")
+	var buf bytes.Buffer
+	zipErr := export.WritePersonalData(&buf, d)
+
+	// Assert
+	if err != nil || zipErr != nil {
+		t.Fatalf("Expected no error, got %v / %v", err, zipErr)
+	}
+
+	if d.Profile.Email != "ana@example.com" || len(d.Addresses) != 2 || len(d.Orders) != 1 {
+		t.Errorf("Unexpected personal data %+v", d)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected a ZIP archive, got %v", err)
+	}
+	names := map[string]*zip.File{}
+	for _, f := range zr.File {
+		names[f.Name] = f
+	}
+	for _, n := range []string{"profile.json", "addresses.json", "orders.json", "status_history.json"} {
+		if names[n] == nil {
+			t.Errorf("Expected %s in the archive", n)
+		}
+	}
+	rc, _ := names["profile.json"].Open()
+	var profile map[string]interface{}
+	_ = json.NewDecoder(rc).Decode(&profile)
+	rc.Close()
+	if profile["email"] != "ana@example.com" || profile["password"] != nil {
+		t.Errorf("Unexpected profile %v", profile)
+	}
+
+	if len(audit.logs) != 1 || audit.logs[0].Action != domain.AuditPrivacyExport {
+		t.Errorf("Expected the export to be audited, got %+v", audit.logs)
+	}
+}
+
+func TestPrivacyService_ApproveErasure(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin, IsActive: true},
+		{ID: 2, Email: "ana@example.com", FullName: "Ana Ruiz", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "eva@example.com", Role: domain.RoleAdmin, IsActive: true},
+	}}
+	org := uint(4)
+	repo := &mockPrivacyRepo{
+		users: users,
+		addresses: []domain.Address{
+			{ID: 1, CustomerID: 2, Street: "Reforma", ExteriorNumber: "10", City: "CDMX", IsActive: true},
+			{ID: 2, CustomerID: 2, OrganizationID: &org, Street: "Insurgentes", City: "CDMX", IsActive: true},
+		},
+		orders: []domain.Order{{ID: 1, OrderNumber: "ORD-1", CustomerID: 2, OriginAddressID: 1, DestinationAddressID: 2}},
+	}
+	audit := &mockAuditRepo{}
+	service := usecase.NewPrivacyService(repo, usecase.NewUserService(users, audit), audit)
+	client := domain.Principal{UserID: 2, Role: domain.RoleClient, Permissions: domain.RoleClient.Permissions()}
+	e, err := service.RequestErasure(context.Background(), 2, "10.0.0.2", "ya no uso el servicio")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	_, duplicate := service.RequestErasure(context.Background(), 2, "10.0.0.2", "")
+	_, notAdmin := service.ApproveErasure(context.Background(), client, "", e.ID, "")
+	done, err := service.ApproveErasure(context.Background(), adminActor(1), "10.0.0.1", e.ID, "verificado")
+	_, again := service.ApproveErasure(context.Background(), adminActor(3), "10.0.0.3", e.ID, "")
+
+	// Assert
+	if !errors.Is(duplicate, usecase.ErrConflict) || !errors.Is(notAdmin, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrConflict and ErrForbidden, got %v / %v", duplicate, notAdmin)
+	}
+
+	if err != nil || done.Status != domain.ErasureCompleted || done.ReviewedBy == nil || *done.ReviewedBy != 1 {
+		t.Fatalf("Expected the request to be completed, got %+v / %v", done, err)
+	}
+
+	u := repo.users.users[1]
+	if !u.DeletedAt.Valid || u.FullName != domain.DeletedUserName || u.Email != domain.DeletedUserEmail(2) {
+		t.Errorf("Expected the user to be anonymized, got %+v", u)
+	}
+
+	if repo.addresses[0].Street != "" || repo.addresses[0].City != "CDMX" || repo.addresses[1].Street != "Insurgentes" {
+		t.Errorf("Expected only the personal address to be anonymized, got %+v", repo.addresses)
+	}
+
+	if len(repo.orders) != 1 || repo.orders[0].CustomerID != 2 {
+		t.Error("Expected the orders to be kept")
+	}
+
+	if !errors.Is(again, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict reviewing twice, got %v", again)
+	}
+
+	actions := []string{}
+	for _, l := range audit.logs {
+		actions = append(actions, l.Action)
+	}
+	if len(actions) != 2 || actions[0] != domain.AuditPrivacyErasureRequest || actions[1] != domain.AuditPrivacyErasureApprove {
+		t.Errorf("Unexpected audit trail %v", actions)
+	}
+}
+
+func TestPrivacyService_RejectErasure(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin, IsActive: true},
+		{ID: 2, Email: "ana@example.com", FullName: "Ana Ruiz", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "eva@example.com", Role: domain.RoleAdmin, IsActive: true},
+	}}
+	org := uint(4)
+	repo := &mockPrivacyRepo{
+		users: users,
+		addresses: []domain.Address{
+			{ID: 1, CustomerID: 2, Street: "Reforma", ExteriorNumber: "10", City: "CDMX", IsActive: true},
+			{ID: 2, CustomerID: 2, OrganizationID: &org, Street: "Insurgentes", City: "CDMX", IsActive: true},
+		},
+		orders: []domain.Order{{ID: 1, OrderNumber: "ORD-1", CustomerID: 2, OriginAddressID: 1, DestinationAddressID: 2}},
+	}
+	audit := &mockAuditRepo{}
+	service := usecase.NewPrivacyService(repo, usecase.NewUserService(users, audit), audit)
+	own, _ := service.RequestErasure(context.Background(), 1, "", "")
+	e, _ := service.RequestErasure(context.Background(), 2, "", "")
+
+	// Act
+	_, self := service.ApproveErasure(context.Background(), adminActor(1), "", own.ID, "")
+	_, noNote := service.RejectErasure(context.Background(), adminActor(1), "", e.ID, " ")
+	rejected, err := service.RejectErasure(context.Background(), adminActor(1), "", e.ID, "hay órdenes en curso")
+	pending, _ := service.ListErasureRequests(context.Background(), domain.ErasurePending)
+
+	// Assert
+	if !errors.Is(self, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden reviewing the own request, got %v", self)
+	}
+
+	if noNote == nil {
+		t.Error("Expected a rejection without note to fail")
+	}
+
+	if err != nil || rejected.Status != domain.ErasureRejected || repo.users.users[1].DeletedAt.Valid {
+		t.Errorf("Expected the request to be rejected and the user kept, got %+v / %v", rejected, err)
+	}
+
+	if len(pending) != 1 || pending[0].ID != own.ID {
+		t.Errorf("Expected only the admin's request pending, got %+v", pending)
+	}
+}
+
+func TestDeleteUser_OwnAccountFilesErasureRequest(t *testing.T) {
+	// Arrange
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin, IsActive: true},
+		{ID: 2, Email: "ana@example.com", FullName: "Ana Ruiz", Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "eva@example.com", Role: domain.RoleAdmin, IsActive: true},
+	}}
+	org := uint(4)
+	repo := &mockPrivacyRepo{
+		users: users,
+		addresses: []domain.Address{
+			{ID: 1, CustomerID: 2, Street: "Reforma", ExteriorNumber: "10", City: "CDMX", IsActive: true},
+			{ID: 2, CustomerID: 2, OrganizationID: &org, Street: "Insurgentes", City: "CDMX", IsActive: true},
+		},
+		orders: []domain.Order{{ID: 1, OrderNumber: "ORD-1", CustomerID: 2, OriginAddressID: 1, DestinationAddressID: 2}},
+	}
+	audit := &mockAuditRepo{}
+	service := usecase.NewPrivacyService(repo, usecase.NewUserService(users, audit), audit)
+	h := &httpdelivery.Handler{Privacy: service}
+	client := &domain.Principal{UserID: 2, Role: domain.RoleClient, Permissions: domain.RoleClient.Permissions()}
+	req := httptest.NewRequest(http.MethodDelete, "/api/users/2", nil)
+	req = mux.SetURLVars(req.WithContext(domain.WithPrincipal(req.Context(), client)), map[string]string{"id": "2"})
+	rec := httptest.NewRecorder()
+
+	// Act
+	h.DeleteUser(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if len(repo.requests) != 1 || repo.requests[0].UserID != 2 || repo.requests[0].Status != domain.ErasurePending {
+		t.Errorf("Expected a pending erasure request, got %+v", repo.requests)
+	}
+
+	if repo.users.users[1].DeletedAt.Valid || repo.addresses[0].Street == "" {
+		t.Error("Expected nothing deleted before an administrator approves")
+	}
+
+	if len(audit.logs) != 1 || audit.logs[0].Action != domain.AuditPrivacyErasureRequest {
+		t.Errorf("Expected the request to be audited, got %+v", audit.logs)
+	}
+}
@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+)
+
+type mockStationRepo struct {
+	stations []domain.Station
+}
+
+func (m *mockStationRepo) Create(ctx context.Context, s *domain.Station) error {
+	s.ID = uint(len(m.stations) + 1)
+	m.stations = append(m.stations, *s)
+	return nil
+}
+
+func (m *mockStationRepo) FindByID(ctx context.Context, id uint) (*domain.Station, error) {
+	for i := range m.stations {
+		if m.stations[i].ID == id {
+			return &m.stations[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockStationRepo) FindAll(ctx context.Context, includeInactive bool) ([]domain.Station, error) {
+	return m.stations, nil
+}
+
+func (m *mockStationRepo) SetActive(ctx context.Context, id uint, active bool) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+type mockScanRepo struct {
+	orders []domain.Order
+	scans  []domain.Scan
+}
+
+func (m *mockScanRepo) FindOrderByNumber(ctx context.Context, orderNumber string) (*domain.Order, error) {
+	for i := range m.orders {
+		if m.orders[i].OrderNumber == orderNumber {
+			return &m.orders[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockScanRepo) LastApplied(ctx context.Context, orderID uint) (*domain.Scan, error) {
+	for i := len(m.scans) - 1; i >= 0; i-- {
+		s := m.scans[i]
+		if s.OrderID != nil && *s.OrderID == orderID && s.Result == domain.ScanApplied {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockScanRepo) Apply(ctx context.Context, s *domain.Scan, stationCode string) error {
+	for i := range m.orders {
+		if m.orders[i].ID == *s.OrderID {
+			m.orders[i].Status = *s.NewStatus
+		}
+	}
+	s.ID = uint(len(m.scans) + 1)
+	m.scans = append(m.scans, *s)
+	return nil
+}
+
+func (m *mockScanRepo) Log(ctx context.Context, s *domain.Scan) error {
+	s.ID = uint(len(m.scans) + 1)
+	m.scans = append(m.scans, *s)
+	return nil
+}
+
+func (m *mockScanRepo) List(ctx context.Context, orderID, stationID uint, date *time.Time) ([]domain.Scan, error) {
+	return m.scans, nil
+}
+
+func TestScanService_Record_InboundMovesToStation(t *testing.T) {
+	// Arrange
+	repo := &mockScanRepo{orders: []domain.Order{{ID: 1, OrderNumber: "ORD-20261019-1", Status: domain.OrderCollected}}}
+	stations := &mockStationRepo{stations: []domain.Station{
+		{ID: 1, Code: "MID01", Name: "Mérida Centro", IsActive: true},
+		{ID: 2, Code: "CDMX1", Name: "CDMX Norte", IsActive: false},
+	}}
+	service := usecase.NewScanService(repo, stations)
+
+	// Act
+	scan, duplicate, err := service.Record(context.Background(), usecase.ScanRequest{Barcode: " ORD-20261019-1 ", ScanType: domain.ScanInbound, StationID: 1, DeviceID: "HH-01"}, 99)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if duplicate {
+		t.Error("Expected first scan not to be a duplicate")
+	}
+
+	if scan.NewStatus == nil || *scan.NewStatus != domain.OrderInStation {
+		t.Errorf("Expected new status %v, got %v", domain.OrderInStation, scan.NewStatus)
+	}
+
+	if repo.orders[0].Status != domain.OrderInStation {
+		t.Errorf("Expected order status %v, got %v", domain.OrderInStation, repo.orders[0].Status)
+	}
+}
+
+func TestScanService_Record_DuplicateIsIdempotent(t *testing.T) {
+	// Arrange
+	repo := &mockScanRepo{orders: []domain.Order{{ID: 1, OrderNumber: "ORD-20261019-1", Status: domain.OrderCollected}}}
+	stations := &mockStationRepo{stations: []domain.Station{
+		{ID: 1, Code: "MID01", Name: "Mérida Centro", IsActive: true},
+		{ID: 2, Code: "CDMX1", Name: "CDMX Norte", IsActive: false},
+	}}
+	service := usecase.NewScanService(repo, stations)
+	req := usecase.ScanRequest{Barcode: "ORD-20261019-1", ScanType: domain.ScanInbound, StationID: 1, DeviceID: "HH-01"}
+	first, _, err := service.Record(context.Background(), req, 99)
+	if err != nil {
+		t.Fatalf("Expected first scan to succeed, got %v", err)
+	}
+
+	// Act
+	second, duplicate, err := service.Record(context.Background(), req, 99)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error on duplicate, got %v", err)
+	}
+
+	if !duplicate || second.ID != first.ID {
+		t.Errorf("Expected duplicate to return scan %d, got %d (duplicate=%v)", first.ID, second.ID, duplicate)
+	}
+
+	if len(repo.scans) != 1 {
+		t.Errorf("Expected 1 scan logged, got %d", len(repo.scans))
+	}
+}
+
+func TestScanService_Record_RejectsScanNotFittingStatus(t *testing.T) {
+	// Arrange
+	repo := &mockScanRepo{orders: []domain.Order{{ID: 1, OrderNumber: "ORD-20261019-1", Status: domain.OrderDelivered}}}
+	stations := &mockStationRepo{stations: []domain.Station{
+		{ID: 1, Code: "MID01", Name: "Mérida Centro", IsActive: true},
+		{ID: 2, Code: "CDMX1", Name: "CDMX Norte", IsActive: false},
+	}}
+	service := usecase.NewScanService(repo, stations)
+
+	// Act
+	_, _, err := service.Record(context.Background(), usecase.ScanRequest{Barcode: "ORD-20261019-1", ScanType: domain.ScanOutbound, StationID: 1, DeviceID: "HH-01"}, 99)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrConflict) {
+		t.Fatalf("Expected ErrConflict, got %v", err)
+	}
+
+	if repo.orders[0].Status != domain.OrderDelivered {
+		t.Errorf("Expected order status unchanged, got %v", repo.orders[0].Status)
+	}
+
+	if len(repo.scans) != 1 || repo.scans[0].Result != domain.ScanRejected {
+		t.Error("Expected rejected scan to be logged")
+	}
+}
+
+func TestScanService_Record_UnknownOrder(t *testing.T) {
+	// Arrange
+	repo := &mockScanRepo{orders: []domain.Order{{ID: 1, OrderNumber: "ORD-20261019-1", Status: domain.OrderCollected}}}
+	stations := &mockStationRepo{stations: []domain.Station{
+		{ID: 1, Code: "MID01", Name: "Mérida Centro", IsActive: true},
+		{ID: 2, Code: "CDMX1", Name: "CDMX Norte", IsActive: false},
+	}}
+	service := usecase.NewScanService(repo, stations)
+
+	// Act
+	_, _, err := service.Record(context.Background(), usecase.ScanRequest{Barcode: "ORD-NOPE", ScanType: domain.ScanInbound, StationID: 1, DeviceID: "HH-01"}, 99)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestScanService_Record_InactiveStation(t *testing.T) {
+	// Arrange
+	repo := &mockScanRepo{orders: []domain.Order{{ID: 1, OrderNumber: "ORD-20261019-1", Status: domain.OrderCollected}}}
+	stations := &mockStationRepo{stations: []domain.Station{
+		{ID: 1, Code: "MID01", Name: "Mérida Centro", IsActive: true},
+		{ID: 2, Code: "CDMX1", Name: "CDMX Norte", IsActive: false},
+	}}
+	service := usecase.NewScanService(repo, stations)
+
+	// Act
+	_, _, err := service.Record(context.Background(), usecase.ScanRequest{Barcode: "ORD-20261019-1", ScanType: domain.ScanInbound, StationID: 2, DeviceID: "HH-01"}, 99)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for inactive station, got nil")
+	}
+}
+
+func TestScanService_Record_InvalidType(t *testing.T) {
+	// Arrange
+	repo := &mockScanRepo{orders: []domain.Order{{ID: 1, OrderNumber: "ORD-20261019-1", Status: domain.OrderCollected}}}
+	stations := &mockStationRepo{stations: []domain.Station{
+		{ID: 1, Code: "MID01", Name: "Mérida Centro", IsActive: true},
+		{ID: 2, Code: "CDMX1", Name: "CDMX Norte", IsActive: false},
+	}}
+	service := usecase.NewScanService(repo, stations)
+
+	// Act
+	_, _, err := service.Record(context.Background(), usecase.ScanRequest{Barcode: "ORD-20261019-1", ScanType: "weigh", StationID: 1, DeviceID: "HH-01"}, 99)
+
+	// Assert
+	if err == nil {
+		t.Fatal("Expected error for invalid scan type, got nil")
+	}
+}
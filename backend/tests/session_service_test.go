@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/usecase"
+	"testing"
+	"time"
+)
+
+type mockSessionRepo struct {
+	tokens []domain.RefreshToken
+	denied map[string]time.Time
+}
+
+func (m *mockSessionRepo) CreateRefresh(ctx context.Context, t *domain.RefreshToken) error {
+	t.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, *t)
+	return nil
+}
+
+func (m *mockSessionRepo) FindRefreshByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	for i := range m.tokens {
+		if m.tokens[i].TokenHash == hash {
+			t := m.tokens[i]
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockSessionRepo) RotateRefresh(ctx context.Context, id uint, next *domain.RefreshToken) (bool, error) {
+	t := &m.tokens[id-1]
+	if t.UsedAt != nil || t.RevokedAt != nil {
+		return false, nil
+	}
+	_ = m.CreateRefresh(ctx, next)
+	now := time.Now()
+	m.tokens[id-1].UsedAt = &now
+	m.tokens[id-1].ReplacedByID = &next.ID
+	return true, nil
+}
+
+func (m *mockSessionRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	now := time.Now()
+	for i := range m.tokens {
+		if m.tokens[i].FamilyID == familyID && m.tokens[i].RevokedAt == nil {
+			m.tokens[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockSessionRepo) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	for _, t := range m.tokens {
+		if t.FamilyID == familyID && t.RevokedAt != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockSessionRepo) DenyAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if m.denied == nil {
+		m.denied = map[string]time.Time{}
+	}
+	m.denied[jti] = expiresAt
+	return nil
+}
+
+func (m *mockSessionRepo) IsAccessTokenDenied(ctx context.Context, jti string) (bool, error) {
+	_, ok := m.denied[jti]
+	return ok, nil
+}
+
+func TestSessionService_Rotate_IssuesNewToken(t *testing.T) {
+	// Arrange
+	repo := &mockSessionRepo{}
+	service := usecase.NewSessionService(repo, time.Hour)
+	family, first, err := service.Start(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Expected no error starting session, got %v", err)
+	}
+
+	// Act
+	userID, gotFamily, second, err := service.Rotate(context.Background(), first)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if userID != 7 || gotFamily != family {
+		t.Errorf("Expected user 7 in family %s, got user %d in %s", family, userID, gotFamily)
+	}
+
+	if second == "" || second == first {
+		t.Error("Expected a new refresh token")
+	}
+
+	if repo.tokens[0].TokenHash == first {
+		t.Error("Expected refresh token to be stored hashed")
+	}
+}
+
+func TestSessionService_Rotate_ReuseRevokesSession(t *testing.T) {
+	// Arrange
+	repo := &mockSessionRepo{}
+	service := usecase.NewSessionService(repo, time.Hour)
+	_, first, _ := service.Start(context.Background(), 7)
+	_, _, second, _ := service.Rotate(context.Background(), first)
+
+	// Act
+	_, _, _, reuseErr := service.Rotate(context.Background(), first)
+	_, _, _, afterErr := service.Rotate(context.Background(), second)
+
+	// Assert
+	if !errors.Is(reuseErr, usecase.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized on reuse, got %v", reuseErr)
+	}
+
+	if !errors.Is(afterErr, usecase.ErrUnauthorized) {
+		t.Errorf("Expected the rotated token to be revoked with the session, got %v", afterErr)
+	}
+}
+
+func TestSessionService_Rotate_Expired(t *testing.T) {
+	// Arrange
+	repo := &mockSessionRepo{}
+	service := usecase.NewSessionService(repo, time.Hour)
+	_, raw, _ := service.Start(context.Background(), 7)
+	repo.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	// Act
+	_, _, _, err := service.Rotate(context.Background(), raw)
+
+	// Assert
+	if !errors.Is(err, usecase.ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestSessionService_Logout(t *testing.T) {
+	// Arrange
+	repo := &mockSessionRepo{}
+	service := usecase.NewSessionService(repo, time.Hour)
+	family, raw, _ := service.Start(context.Background(), 7)
+
+	// Act
+	err := service.Logout(context.Background(), family, "jti-1", time.Now().Add(10*time.Minute))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !service.IsRevoked(context.Background(), "jti-1") {
+		t.Error("Expected access token to be deny-listed")
+	}
+
+	if _, _, _, err := service.Rotate(context.Background(), raw); !errors.Is(err, usecase.ErrUnauthorized) {
+		t.Errorf("Expected refresh token to be revoked, got %v", err)
+	}
+}
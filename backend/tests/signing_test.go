@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"logistics-app/backend/internal/infra/signing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func testClaims() *jwt.RegisteredClaims {
+	return &jwt.RegisteredClaims{Subject: "1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+func writeKey(t *testing.T, dir, kid string, key interface{}) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writePublicKey(t *testing.T, dir, kid string, key interface{}) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHMACRotationKeepsOldTokensValid(t *testing.T) {
+	oldKeys := signing.NewHMAC([]byte("an-old-secret-that-is-long-enough-0001"))
+	token, err := oldKeys.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := signing.NewHMAC([]byte("a-new-secret-that-is-long-enough-00002"), []byte("an-old-secret-that-is-long-enough-0001"))
+	if rotated.ActiveID() == oldKeys.ActiveID() {
+		t.Fatal("expected a different kid for the new secret")
+	}
+	if _, err := rotated.Parse(token, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("token of the previous secret should verify: %v", err)
+	}
+
+	// once the old secret is dropped its tokens stop verifying
+	fresh := signing.NewHMAC([]byte("a-new-secret-that-is-long-enough-00002"))
+	if _, err := fresh.Parse(token, &jwt.RegisteredClaims{}); !errors.Is(err, signing.ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestHMACAcceptsTokensWithoutKid(t *testing.T) {
+	secret := []byte("a-secret-that-is-long-enough-for-tests")
+	legacy, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims()).SignedString(secret)
+
+	if _, err := signing.NewHMAC(secret).Parse(legacy, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("tokens issued before kid support should verify: %v", err)
+	}
+}
+
+func TestLoadDirSignsWithEdDSAAndRotates(t *testing.T) {
+	dir := t.TempDir()
+	_, oldPriv, _ := ed25519.GenerateKey(rand.Reader)
+	_, newPriv, _ := ed25519.GenerateKey(rand.Reader)
+	writeKey(t, dir, "2025-01", oldPriv)
+	writeKey(t, dir, "2026-01", newPriv)
+
+	if _, err := signing.LoadDir(dir, ""); err == nil {
+		t.Fatal("expected an error when several private keys exist and none is active")
+	}
+
+	old, err := signing.LoadDir(dir, "2025-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := old.Sign(testClaims())
+
+	keys, err := signing.LoadDir(dir, "2026-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Parse(token, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatalf("token signed by the previous key should verify: %v", err)
+	}
+
+	fresh, _ := keys.Sign(testClaims())
+	parsed, err := keys.Parse(fresh, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != "2026-01" || parsed.Method.Alg() != "EdDSA" {
+		t.Fatalf("unexpected header %v", parsed.Header)
+	}
+
+	set := keys.JWKS()
+	if len(set.Keys) != 2 || set.Keys[0].Kid != "2026-01" {
+		t.Fatalf("expected both keys with the active one first, got %+v", set.Keys)
+	}
+	if set.Keys[0].Kty != "OKP" || set.Keys[0].Crv != "Ed25519" || set.Keys[0].X == "" {
+		t.Fatalf("unexpected jwk %+v", set.Keys[0])
+	}
+}
+
+func TestLoadDirRSAPublicKeyOnlyVerifies(t *testing.T) {
+	dir := t.TempDir()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "rsa-1", priv)
+
+	keys, err := signing.LoadDir(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := keys.Sign(testClaims())
+
+	// a second instance that only has the public key can verify but not sign
+	verifyDir := t.TempDir()
+	writePublicKey(t, verifyDir, "rsa-1", &priv.PublicKey)
+	if _, err := signing.LoadDir(verifyDir, ""); err == nil {
+		t.Fatal("expected an error without a private key")
+	}
+
+	set := keys.JWKS()
+	if len(set.Keys) != 1 || set.Keys[0].Kty != "RSA" || set.Keys[0].Alg != "RS256" || set.Keys[0].E != "AQAB" {
+		t.Fatalf("unexpected jwks %+v", set.Keys)
+	}
+
+	// an HS256 token carrying the RSA kid must not be accepted
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = "rsa-1"
+	forgedStr, _ := forged.SignedString([]byte("whatever"))
+	if _, err := keys.Parse(forgedStr, &jwt.RegisteredClaims{}); err == nil {
+		t.Fatal("expected algorithm confusion to be rejected")
+	}
+	if _, err := keys.Parse(token, &jwt.RegisteredClaims{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHMACKeysAreNotPublished(t *testing.T) {
+	if keys := signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")).JWKS(); len(keys.Keys) != 0 {
+		t.Fatalf("HMAC secrets must not be published, got %+v", keys.Keys)
+	}
+}
+
+func TestCheckSecret(t *testing.T) {
+	for _, weak := range []string{"", "dev_secret", "SECRET", "short-secret"} {
+		if signing.CheckSecret(weak) == nil {
+			t.Errorf("expected %q to be rejected", weak)
+		}
+	}
+	if err := signing.CheckSecret("k3Jx9vQ2mZp7Lw4Rt8Yb1Nc6Hd5Fg0Se"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFromEnvRefusesWeakSecretOutsideDev(t *testing.T) {
+	t.Setenv("JWT_KEYS_DIR", "")
+	t.Setenv("JWT_SECRET", "dev_secret")
+
+	if _, err := signing.FromEnv(false); err == nil {
+		t.Fatal("expected weak secret to be refused outside dev mode")
+	}
+	if _, err := signing.FromEnv(true); err != nil {
+		t.Fatalf("dev mode should tolerate it: %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpdelivery "logistics-app/backend/internal/delivery/http"
+	"logistics-app/backend/internal/domain"
+	"logistics-app/backend/internal/infra/signing"
+	"logistics-app/backend/internal/usecase"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func adminActor(id uint) domain.Principal {
+	return domain.Principal{UserID: id, Role: domain.RoleAdmin, Permissions: domain.RoleAdmin.Permissions()}
+}
+
+func TestUserService_List(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{}
+	for _, u := range []domain.User{
+		{Email: "ana@example.com", FullName: "Ana Ruiz", Role: domain.RoleClient, IsActive: true},
+		{Email: "luis@example.com", FullName: "Luis Ruiz", Role: domain.RoleClient, IsActive: false},
+		{Email: "eva@example.com", FullName: "Eva Gil", Role: domain.RoleSupport, IsActive: true},
+	} {
+		u := u
+		_ = repo.Create(context.Background(), &u)
+	}
+	service := usecase.NewUserService(repo, &mockAuditRepo{})
+	active := true
+
+	// Act
+	page, err := service.List(context.Background(), usecase.UserQuery{Query: "ruiz", PageSize: 1, Page: 2})
+	filtered, _ := service.List(context.Background(), usecase.UserQuery{Role: domain.RoleClient, Active: &active})
+	capped, _ := service.List(context.Background(), usecase.UserQuery{PageSize: 1000})
+	_, badRole := service.List(context.Background(), usecase.UserQuery{Role: "root"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].Email != "luis@example.com" {
+		t.Errorf("Unexpected second page %+v", page)
+	}
+
+	if filtered.Total != 1 || filtered.Items[0].Email != "ana@example.com" {
+		t.Errorf("Expected only the active client, got %+v", filtered.Items)
+	}
+
+	if capped.PageSize != usecase.MaxUserPageSize || capped.Page != 1 {
+		t.Errorf("Expected the page size to be capped, got %d", capped.PageSize)
+	}
+
+	if badRole == nil {
+		t.Error("Expected an unknown role to be rejected")
+	}
+}
+
+func TestUserService_Update(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "admin@example.com", FullName: "Admin", Role: domain.RoleAdmin, IsActive: true},
+		{ID: 2, Email: "ana@example.com", FullName: "Ana", Role: domain.RoleClient, IsActive: true},
+	}}
+	audit := &mockAuditRepo{}
+	service := usecase.NewUserService(repo, audit)
+	client := domain.Principal{UserID: 2, Role: domain.RoleClient, Permissions: domain.RoleClient.Permissions()}
+	name, phone := "Ana Ruiz", "555-0101"
+	support, inactive := domain.RoleSupport, false
+
+	// Act
+	own, err := service.Update(context.Background(), client, "", 2, usecase.UserUpdate{FullName: &name, Phone: &phone})
+	_, selfRole := service.Update(context.Background(), client, "", 2, usecase.UserUpdate{Role: &support})
+	_, other := service.Update(context.Background(), client, "", 1, usecase.UserUpdate{FullName: &name})
+	_, lastAdmin := service.Update(context.Background(), adminActor(3), "", 1, usecase.UserUpdate{Role: &support})
+	changed, adminErr := service.Update(context.Background(), adminActor(1), "", 2, usecase.UserUpdate{Role: &support, IsActive: &inactive})
+
+	// Assert
+	if err != nil || own.FullName != name || own.Phone != phone {
+		t.Fatalf("Expected the user to edit their profile, got %+v / %v", own, err)
+	}
+
+	if !errors.Is(selfRole, usecase.ErrForbidden) || !errors.Is(other, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v / %v", selfRole, other)
+	}
+
+	if !errors.Is(lastAdmin, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict demoting the last admin, got %v", lastAdmin)
+	}
+
+	if adminErr != nil || changed.Role != domain.RoleSupport || changed.IsActive || repo.users[1].SessionsRevokedAt == nil {
+		t.Errorf("Expected the admin to change role and deactivate, got %+v / %v", changed, adminErr)
+	}
+
+	if len(audit.logs) != 1 || audit.logs[0].Action != domain.AuditUserRoleChange {
+		t.Errorf("Expected the role change to be audited, got %+v", audit.logs)
+	}
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	// Arrange
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	repo := &mockUserRepo{users: []domain.User{{ID: 1, Email: "ana@example.com", Password: string(hash), IsActive: true}}}
+	service := usecase.NewUserService(repo, &mockAuditRepo{})
+
+	// Act
+	wrong := service.ChangePassword(context.Background(), 1, "s1", "nope", "new-password")
+	err := service.ChangePassword(context.Background(), 1, "s1", "old-password", "new-password")
+	_, loginErr := service.Authenticate(context.Background(), "ana@example.com", "new-password")
+
+	// Assert
+	if !errors.Is(wrong, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a wrong current password, got %v", wrong)
+	}
+
+	if err != nil || loginErr != nil {
+		t.Errorf("Expected the new password to work, got %v / %v", err, loginErr)
+	}
+}
+
+func TestUserService_ChangePassword_PrivilegedFloor(t *testing.T) {
+	// Arrange
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-password-123"), bcrypt.MinCost)
+	repo := &mockUserRepo{users: []domain.User{{ID: 1, Email: "admin@example.com", Password: string(hash), Role: domain.RoleAdmin, IsActive: true}}}
+	service := usecase.NewUserService(repo, &mockAuditRepo{})
+
+	// Act
+	err := service.ChangePassword(context.Background(), 1, "s1", "old-password-123", "short")
+	_, loginErr := service.Authenticate(context.Background(), "admin@example.com", "old-password-123")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "12 caracteres") {
+		t.Errorf("Expected the privileged floor to apply, got %v", err)
+	}
+
+	if loginErr != nil {
+		t.Errorf("Expected the old password to be kept, got %v", loginErr)
+	}
+}
+
+func TestUserService_Delete(t *testing.T) {
+	// Arrange
+	org := uint(7)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secreto"), bcrypt.MinCost)
+	repo := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "admin@example.com", Role: domain.RoleAdmin, IsActive: true},
+		{ID: 2, Email: "ana@example.com", FullName: "Ana", Phone: "555-0101", Password: string(hash), Role: domain.RoleClient, IsActive: true},
+		{ID: 3, Email: "luis@example.com", Role: domain.RoleClient, IsActive: true, OrganizationID: &org, OrgRole: domain.OrgOwner},
+		{ID: 4, Email: "eva@example.com", Role: domain.RoleClient, IsActive: true, OrganizationID: &org, OrgRole: domain.OrgViewer},
+	}}
+	audit := &mockAuditRepo{}
+	service := usecase.NewUserService(repo, audit)
+	client := domain.Principal{UserID: 2, Role: domain.RoleClient, Permissions: domain.RoleClient.Permissions()}
+
+	// Act
+	other := service.Delete(context.Background(), client, "10.0.0.1", 3)
+	self := service.Delete(context.Background(), client, "10.0.0.1", 2)
+	err := service.Delete(context.Background(), adminActor(1), "10.0.0.1", 2)
+	_, loginErr := service.Authenticate(context.Background(), "ana@example.com", "secreto")
+	again := service.Delete(context.Background(), adminActor(1), "10.0.0.1", 2)
+	lastAdmin := service.Delete(context.Background(), adminActor(1), "10.0.0.1", 1)
+	lastOwner := service.Delete(context.Background(), adminActor(1), "10.0.0.1", 3)
+
+	// Assert
+	if !errors.Is(other, usecase.ErrForbidden) || !errors.Is(self, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without users.delete, also on the own account, got %v / %v", other, self)
+	}
+
+	deleted := repo.users[1]
+	if err != nil || !deleted.DeletedAt.Valid || deleted.IsActive || deleted.UpdatedBy == nil {
+		t.Fatalf("Expected the account to be soft deleted and kept, got %v / %+v", err, deleted)
+	}
+
+	if deleted.Email != domain.DeletedUserEmail(2) || deleted.FullName != domain.DeletedUserName || deleted.Phone != "" || deleted.Password != "" {
+		t.Errorf("Expected the personal data to be anonymized, got %+v", deleted)
+	}
+
+	if len(audit.logs) != 1 || audit.logs[0].Action != domain.AuditUserDelete || audit.logs[0].Subject != "user:2" || *audit.logs[0].ActorID != 1 {
+		t.Errorf("Expected the deletion to be audited, got %+v", audit.logs)
+	}
+
+	if loginErr == nil || !errors.Is(again, usecase.ErrNotFound) {
+		t.Errorf("Expected a deleted user to be gone, got %v / %v", loginErr, again)
+	}
+
+	if !errors.Is(lastAdmin, usecase.ErrConflict) || !errors.Is(lastOwner, usecase.ErrConflict) {
+		t.Errorf("Expected ErrConflict for the last admin and owner, got %v / %v", lastAdmin, lastOwner)
+	}
+}
+
+func TestRequireAuth_RejectsTokenAfterForceLogout(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+	old := bearer(t, h.Keys, jwt.MapClaims{"uid": 1, "jti": "old", "iat": time.Now().Add(-time.Minute).Unix()})
+
+	if err := h.Users.ForceLogout(context.Background(), adminActor(9), 1); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := serve(h, old); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after a force logout, got %d", code)
+	}
+
+	// tokens issued after it are accepted
+	later := time.Now().Add(-time.Hour)
+	users.users[0].SessionsRevokedAt = &later
+	if code, _ := serve(h, old); code != http.StatusOK {
+		t.Fatalf("expected a newer token to pass, got %d", code)
+	}
+}
+
+func TestUserService_RegisterCreatesClients(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{}
+	service := usecase.NewUserService(repo, &mockAuditRepo{})
+
+	// Act
+	u, err := service.Register(context.Background(), "ana@example.com", "secreto", "Ana", "")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if u.Role != domain.RoleClient || repo.users[0].Role != domain.RoleClient {
+		t.Errorf("Expected a client, got %q", u.Role)
+	}
+}
+
+func TestRegisterUser_RejectsPrivilegedRole(t *testing.T) {
+	users := &mockUserRepo{users: []domain.User{
+		{ID: 1, Email: "ana@example.com", Role: domain.RoleDispatcher, IsActive: true},
+		{ID: 2, Email: "luis@example.com", Role: domain.RoleClient, IsActive: false},
+	}}
+	h := &httpdelivery.Handler{
+		Users:    usecase.NewUserService(users, &mockAuditRepo{}),
+		Sessions: usecase.NewSessionService(&mockSessionRepo{denied: map[string]time.Time{}}, time.Hour),
+		Keys:     signing.NewHMAC([]byte("a-secret-that-is-long-enough-for-tests")),
+	}
+	before := len(users.users)
+
+	for _, role := range []string{"admin", "dispatcher"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(`{"email":"eve@example.com","password":"secreto","full_name":"Eve","role":"`+role+`"}`))
+		rec := httptest.NewRecorder()
+		h.RegisterUser(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", role, rec.Code)
+		}
+	}
+	if len(users.users) != before {
+		t.Fatal("expected no user to be created")
+	}
+}
+
+func TestUserService_CreatePrivileged(t *testing.T) {
+	// Arrange
+	repo := &mockUserRepo{}
+	audit := &mockAuditRepo{}
+	service := usecase.NewUserService(repo, audit)
+	client := domain.Principal{UserID: 2, Role: domain.RoleClient, Permissions: domain.RoleClient.Permissions()}
+	req := usecase.NewUserRequest{Email: "eva@example.com", Password: "a-long-password", FullName: "Eva", Role: domain.RoleDispatcher}
+
+	// Act
+	_, forbidden := service.CreatePrivileged(context.Background(), client, "10.0.0.2", req)
+	_, short := service.CreatePrivileged(context.Background(), adminActor(1), "10.0.0.1", usecase.NewUserRequest{Email: "x@example.com", Password: "short", FullName: "X", Role: domain.RoleAdmin})
+	_, unknown := service.CreatePrivileged(context.Background(), adminActor(1), "10.0.0.1", usecase.NewUserRequest{Email: "x@example.com", Password: "a-long-password", FullName: "X", Role: "root"})
+	u, err := service.CreatePrivileged(context.Background(), adminActor(1), "10.0.0.1", req)
+
+	// Assert
+	if !errors.Is(forbidden, usecase.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without users.manage, got %v", forbidden)
+	}
+
+	if short == nil || unknown == nil {
+		t.Errorf("Expected a short password and an unknown role to be rejected, got %v / %v", short, unknown)
+	}
+
+	if err != nil || u.Role != domain.RoleDispatcher || len(repo.users) != 1 {
+		t.Fatalf("Expected a dispatcher to be created, got %+v / %v", u, err)
+	}
+
+	if len(audit.logs) != 1 {
+		t.Fatalf("Expected one audit entry, got %d", len(audit.logs))
+	}
+	entry := audit.logs[0]
+	if entry.Action != domain.AuditUserCreate || entry.ActorID == nil || *entry.ActorID != 1 || entry.Subject != "user:1" || entry.IP != "10.0.0.1" {
+		t.Errorf("Unexpected audit entry %+v", entry)
+	}
+}
@@ -1,5 +1,5 @@
 "use client";
-import React, { createContext, useCallback, useContext, useEffect, useMemo, useState } from "react";
+import React, { createContext, useCallback, useContext, useEffect, useMemo, useRef, useState } from "react";
 import { API_BASE } from "../../../lib/constants";
 
 export type AuthState = {
@@ -12,10 +12,27 @@ export type AuthState = {
 
 export type Toast = { id: number; type: "success" | "danger" | "info" | "warning"; message: string; timeout?: number };
 
+// Returned by login when the account needs a second factor; enrollmentRequired means it has to set up MFA first
+export type MfaChallenge = { email: string; mfaToken: string; enrollmentRequired: boolean; expiresIn: number };
+
+export type MfaEnrollment = { secret: string; otpauthUrl: string; qrCode: string };
+
+type TokenResponse = { token: string; refresh_token?: string; token_type?: string; expires_in?: number };
+
+// The access token is refreshed this long before it expires
+const REFRESH_MARGIN_MS = 60_000;
+
 type AuthContextType = AuthState & {
-  login: (email: string, password: string) => Promise<void>;
+  // Resolves with a challenge when the login must continue with verifyMfa, or enrollMfa and confirmMfa
+  login: (email: string, password: string) => Promise<MfaChallenge | null>;
+  verifyMfa: (challenge: MfaChallenge, code: string) => Promise<void>;
+  enrollMfa: (challenge: MfaChallenge) => Promise<MfaEnrollment>;
+  // The session starts when finish is called, so the recovery codes can be shown first
+  confirmMfa: (challenge: MfaChallenge, code: string) => Promise<{ recoveryCodes: string[]; finish: () => void }>;
   register: (email: string, password: string, fullName: string, phone: string) => Promise<void>;
   logout: () => void;
+  // fetch with the current access token; on 401 the session is refreshed once and the request retried
+  authFetch: (url: string, init?: RequestInit) => Promise<Response>;
   notify: (toast: Omit<Toast, "id">) => void;
   toasts: Toast[];
   removeToast: (id: number) => void;
@@ -29,6 +46,10 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children
   const [userId, setUserId] = useState<number | null>(null);
   const [role, setRole] = useState<"admin" | "client" | "courier" | null>(null);
   const [toasts, setToasts] = useState<Toast[]>([]);
+  const [expiresAt, setExpiresAt] = useState<number | null>(null);
+  // Read by authFetch and the refresh, which outlive the render that created them
+  const tokenRef = useRef<string | null>(null);
+  const refreshing = useRef<Promise<string | null> | null>(null);
 
   const decodeJwt = (tkn: string): { uid?: number; role?: "admin"|"client"|"courier" } => {
     try {
@@ -41,17 +62,46 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children
     }
   };
 
+  const applyToken = (t: string) => {
+    tokenRef.current = t;
+    setToken(t);
+    const info = decodeJwt(t);
+    setUserId(typeof info.uid === "number" ? info.uid : null);
+    setRole((info.role as any) ?? null);
+  };
+
+  const clearSession = useCallback(() => {
+    tokenRef.current = null;
+    setToken(null);
+    setEmail(null);
+    setUserId(null);
+    setRole(null);
+    setExpiresAt(null);
+    localStorage.removeItem("auth_token");
+    localStorage.removeItem("auth_email");
+    localStorage.removeItem("auth_refresh_token");
+    localStorage.removeItem("auth_expires_at");
+  }, []);
+
   useEffect(() => {
     const t = localStorage.getItem("auth_token");
     const e = localStorage.getItem("auth_email");
-    if (t) {
-      setToken(t);
-      const info = decodeJwt(t);
-      setUserId(typeof info.uid === "number" ? info.uid : null);
-      setRole((info.role as any) ?? null);
-    }
+    const exp = Number(localStorage.getItem("auth_expires_at"));
+    if (t) applyToken(t);
     if (e) setEmail(e);
-  }, []);
+    if (t && exp) setExpiresAt(exp);
+
+    // Another tab refreshed or closed the session
+    const onStorage = (ev: StorageEvent) => {
+      if (ev.key === "auth_token") {
+        if (ev.newValue) applyToken(ev.newValue);
+        else clearSession();
+      }
+      if (ev.key === "auth_expires_at" && ev.newValue) setExpiresAt(Number(ev.newValue));
+    };
+    window.addEventListener("storage", onStorage);
+    return () => window.removeEventListener("storage", onStorage);
+  }, [clearSession]);
 
   const isAuthenticated = !!token;
 
@@ -70,26 +120,122 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children
     setToasts((prev) => prev.filter((t) => t.id !== id));
   }, []);
 
+  const startSession = useCallback((data: TokenResponse, email?: string) => {
+    applyToken(data.token);
+    localStorage.setItem("auth_token", data.token);
+    if (email) {
+      setEmail(email);
+      localStorage.setItem("auth_email", email);
+    }
+    if (data.refresh_token) localStorage.setItem("auth_refresh_token", data.refresh_token);
+    if (data.expires_in) {
+      const at = Date.now() + data.expires_in * 1000;
+      setExpiresAt(at);
+      localStorage.setItem("auth_expires_at", String(at));
+    }
+  }, []);
+
+  // Refresh tokens work once, so concurrent callers share the same request. The token is read from
+  // storage because another tab may have rotated it.
+  const refreshSession = useCallback((): Promise<string | null> => {
+    if (refreshing.current) return refreshing.current;
+    const refreshToken = localStorage.getItem("auth_refresh_token");
+    if (!refreshToken) return Promise.resolve(null);
+
+    refreshing.current = (async () => {
+      try {
+        const res = await fetch(`${API_BASE}/api/token/refresh`, {
+          method: "POST",
+          headers: { "Content-Type": "application/json" },
+          body: JSON.stringify({ refresh_token: refreshToken }),
+        });
+        if (!res.ok) {
+          clearSession();
+          notify({ type: "warning", message: "Tu sesión expiró, inicia sesión de nuevo" });
+          return null;
+        }
+        const data = (await res.json()) as TokenResponse;
+        startSession(data);
+        return data.token;
+      } catch {
+        // Network error: keep the session and try again on the next request
+        return null;
+      } finally {
+        refreshing.current = null;
+      }
+    })();
+    return refreshing.current;
+  }, [clearSession, notify, startSession]);
+
+  useEffect(() => {
+    if (!expiresAt) return;
+    const timer = setTimeout(refreshSession, Math.max(0, expiresAt - Date.now() - REFRESH_MARGIN_MS));
+    return () => clearTimeout(timer);
+  }, [expiresAt, refreshSession]);
+
+  const authFetch = useCallback(async (url: string, init: RequestInit = {}) => {
+    const send = (t: string | null) => {
+      const headers = new Headers(init.headers);
+      if (t) headers.set("Authorization", `Bearer ${t}`);
+      return fetch(url, { ...init, headers });
+    };
+    const res = await send(tokenRef.current);
+    if (res.status !== 401) return res;
+    const fresh = await refreshSession();
+    return fresh ? send(fresh) : res;
+  }, [refreshSession]);
+
+  const fail = useCallback(async (res: Response, fallback: string) => {
+    const msg = await res.text();
+    notify({ type: "danger", message: msg || fallback });
+    throw new Error(msg || fallback);
+  }, [notify]);
+
   const login = useCallback(async (email: string, password: string) => {
     const res = await fetch(`${API_BASE}/api/login`, {
       method: "POST",
       headers: { "Content-Type": "application/json" },
       body: JSON.stringify({ email, password }),
     });
-    if (!res.ok) {
-      const msg = await res.text();
-      notify({ type: "danger", message: msg || "Error de autenticación" });
-      throw new Error(msg || "login failed");
+    if (!res.ok) return fail(res, "Error de autenticación");
+    if (res.status === 202) {
+      const c = (await res.json()) as { mfa_token: string; enrollment_required: boolean; expires_in: number };
+      return { email, mfaToken: c.mfa_token, enrollmentRequired: c.enrollment_required, expiresIn: c.expires_in };
     }
-    const data = (await res.json()) as { token: string };
-    setToken(data.token);
-    setEmail(email);
-    localStorage.setItem("auth_token", data.token);
-    localStorage.setItem("auth_email", email);
-    const info = decodeJwt(data.token);
-    setUserId(typeof info.uid === "number" ? info.uid : null);
-    setRole((info.role as any) ?? null);
-  }, [notify]);
+    startSession((await res.json()) as TokenResponse, email);
+    return null;
+  }, [fail, startSession]);
+
+  const verifyMfa = useCallback(async (challenge: MfaChallenge, code: string) => {
+    const res = await fetch(`${API_BASE}/api/login/mfa`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ mfa_token: challenge.mfaToken, code }),
+    });
+    if (!res.ok) return fail(res, "Código inválido");
+    startSession((await res.json()) as TokenResponse, challenge.email);
+  }, [fail, startSession]);
+
+  const enrollMfa = useCallback(async (challenge: MfaChallenge) => {
+    const res = await fetch(`${API_BASE}/api/mfa/enroll`, {
+      method: "POST",
+      headers: { Authorization: `Bearer ${challenge.mfaToken}` },
+    });
+    if (!res.ok) return fail(res, "No se pudo iniciar la configuración de MFA");
+    const data = (await res.json()) as { secret: string; otpauth_url: string; qr_code: string };
+    return { secret: data.secret, otpauthUrl: data.otpauth_url, qrCode: data.qr_code };
+  }, [fail]);
+
+  const confirmMfa = useCallback(async (challenge: MfaChallenge, code: string) => {
+    const res = await fetch(`${API_BASE}/api/mfa/confirm`, {
+      method: "POST",
+      headers: { "Content-Type": "application/json", Authorization: `Bearer ${challenge.mfaToken}` },
+      body: JSON.stringify({ code }),
+    });
+    if (!res.ok) return fail(res, "Código inválido");
+    const data = (await res.json()) as TokenResponse & { recovery_codes: string[] };
+    return { recoveryCodes: data.recovery_codes, finish: () => startSession(data, challenge.email) };
+  }, [fail, startSession]);
 
   const register = useCallback(async (email: string, password: string, fullName: string, phone: string) => {
     const res = await fetch(`${API_BASE}/api/users`, {
@@ -106,17 +252,17 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children
   }, [notify]);
 
   const logout = useCallback(() => {
-    setToken(null);
-    setEmail(null);
-    setUserId(null);
-    setRole(null);
-    localStorage.removeItem("auth_token");
-    localStorage.removeItem("auth_email");
-  }, []);
+    // Revokes the session on the server; the local session ends either way
+    const t = tokenRef.current;
+    if (t) {
+      fetch(`${API_BASE}/api/logout`, { method: "POST", headers: { Authorization: `Bearer ${t}` } }).catch(() => {});
+    }
+    clearSession();
+  }, [clearSession]);
 
   const value = useMemo<AuthContextType>(
-    () => ({ token, email, isAuthenticated, userId, role, login, register, logout, notify, toasts, removeToast }),
-    [token, email, isAuthenticated, userId, role, login, register, logout, notify, toasts, removeToast]
+    () => ({ token, email, isAuthenticated, userId, role, login, verifyMfa, enrollMfa, confirmMfa, register, logout, authFetch, notify, toasts, removeToast }),
+    [token, email, isAuthenticated, userId, role, login, verifyMfa, enrollMfa, confirmMfa, register, logout, authFetch, notify, toasts, removeToast]
   );
 
   return <AuthContext.Provider value={value}>{children}</AuthContext.Provider>;
@@ -1,22 +1,34 @@
 "use client";
 import React, {useState} from "react";
-import {useAuth} from "./AuthContext";
+import {MfaChallenge, MfaEnrollment, useAuth} from "./AuthContext";
 
 const LoginForm: React.FC<{ onSwitchToRegister?: () => void; onSuccess?: () => void }> = ({
                                                                                               onSwitchToRegister,
                                                                                               onSuccess
                                                                                           }) => {
-    const {login} = useAuth();
+    const {login, verifyMfa, enrollMfa, confirmMfa} = useAuth();
     const [email, setEmail] = useState("");
     const [password, setPassword] = useState("");
     const [loading, setLoading] = useState(false);
+    // Second factor: challenge from the login, enrollment data on first setup and the codes shown once
+    const [challenge, setChallenge] = useState<MfaChallenge | null>(null);
+    const [enrollment, setEnrollment] = useState<MfaEnrollment | null>(null);
+    const [code, setCode] = useState("");
+    const [recovery, setRecovery] = useState<{ recoveryCodes: string[]; finish: () => void } | null>(null);
 
     const handleSubmit = async (e: React.FormEvent) => {
         e.preventDefault();
         setLoading(true);
         try {
-            await login(email, password);
-            onSuccess?.();
+            const c = await login(email, password);
+            if (!c) {
+                onSuccess?.();
+                return;
+            }
+            if (c.enrollmentRequired) {
+                setEnrollment(await enrollMfa(c));
+            }
+            setChallenge(c);
         } catch {
             // notification handled by context
         } finally {
@@ -24,6 +36,35 @@ const LoginForm: React.FC<{ onSwitchToRegister?: () => void; onSuccess?: () => v
         }
     };
 
+    const handleCode = async (e: React.FormEvent) => {
+        e.preventDefault();
+        if (!challenge) return;
+        setLoading(true);
+        try {
+            if (challenge.enrollmentRequired) {
+                setRecovery(await confirmMfa(challenge, code.trim()));
+            } else {
+                await verifyMfa(challenge, code.trim());
+                onSuccess?.();
+            }
+        } catch {
+            // notification handled by context
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const handleBack = () => {
+        setChallenge(null);
+        setEnrollment(null);
+        setCode("");
+    };
+
+    const handleFinish = () => {
+        recovery?.finish();
+        onSuccess?.();
+    };
+
     return (
         <div className="login-container">
             <div className="login-card row g-0">
@@ -32,22 +73,67 @@ const LoginForm: React.FC<{ onSwitchToRegister?: () => void; onSuccess?: () => v
                 </div>
 
                 <div className="col-md-6 right-panel">
-                    <form onSubmit={handleSubmit}>
-                        <h2 className="form-title mb-4">Bienvenido</h2>
-                        <div className="mb-3">
-                            <label className="form-label">Email</label>
-                            <input type="email" className="form-control" value={email}
-                                   onChange={(e) => setEmail(e.target.value)} required/>
+                    {recovery ? (
+                        <div>
+                            <h2 className="form-title mb-3">Códigos de recuperación</h2>
+                            <p className="text-muted">
+                                Guarda estos códigos en un lugar seguro. Cada uno sirve una sola vez si pierdes
+                                acceso a tu aplicación de autenticación y no se volverán a mostrar.
+                            </p>
+                            <ul className="list-unstyled font-monospace mb-4">
+                                {recovery.recoveryCodes.map((c) => <li key={c}>{c}</li>)}
+                            </ul>
+                            <button className="btn btn-primary w-100" type="button" onClick={handleFinish}>
+                                Continuar
+                            </button>
                         </div>
-                        <div className="mb-5">
-                            <label className="form-label">Clave de usuario</label>
-                            <input type="password" className="form-control" value={password}
-                                   onChange={(e) => setPassword(e.target.value)} required/>
-                        </div>
-                        <button className="btn btn-primary w-100" type="submit" disabled={loading}>
-                            {loading ? "Ingresando..." : "Ingresar"}
-                        </button>
-                    </form>
+                    ) : challenge ? (
+                        <form onSubmit={handleCode}>
+                            <h2 className="form-title mb-3">Verificación en dos pasos</h2>
+                            {enrollment ? (
+                                <div className="mb-3">
+                                    <p className="text-muted">
+                                        Tu cuenta requiere MFA. Escanea el código con tu aplicación de autenticación
+                                        o ingresa la clave manualmente.
+                                    </p>
+                                    <img src={enrollment.qrCode} alt="Código QR de MFA" width={200} height={200}/>
+                                    <div className="small font-monospace text-break">{enrollment.secret}</div>
+                                </div>
+                            ) : (
+                                <p className="text-muted">
+                                    Ingresa el código de tu aplicación de autenticación o un código de recuperación.
+                                </p>
+                            )}
+                            <div className="mb-4">
+                                <label className="form-label">Código</label>
+                                <input type="text" className="form-control" value={code} autoComplete="one-time-code"
+                                       onChange={(e) => setCode(e.target.value)} required/>
+                            </div>
+                            <button className="btn btn-primary w-100" type="submit" disabled={loading}>
+                                {loading ? "Verificando..." : "Verificar"}
+                            </button>
+                            <button type="button" className="btn btn-link w-100 mt-2" onClick={handleBack}>
+                                Volver
+                            </button>
+                        </form>
+                    ) : (
+                        <form onSubmit={handleSubmit}>
+                            <h2 className="form-title mb-4">Bienvenido</h2>
+                            <div className="mb-3">
+                                <label className="form-label">Email</label>
+                                <input type="email" className="form-control" value={email}
+                                       onChange={(e) => setEmail(e.target.value)} required/>
+                            </div>
+                            <div className="mb-5">
+                                <label className="form-label">Clave de usuario</label>
+                                <input type="password" className="form-control" value={password}
+                                       onChange={(e) => setPassword(e.target.value)} required/>
+                            </div>
+                            <button className="btn btn-primary w-100" type="submit" disabled={loading}>
+                                {loading ? "Ingresando..." : "Ingresar"}
+                            </button>
+                        </form>
+                    )}
                     <div className="mt-3 text-center">
                         <span className="text-muted">¿No tienes cuenta? </span>
                         <button type="button" className="btn btn-link p-0 align-baseline"
@@ -21,7 +21,7 @@ export type OrderRow = {
 };
 
 const MainContent: React.FC = () => {
-  const { token } = useAuth();
+  const { isAuthenticated, authFetch } = useAuth();
   const [rows, setRows] = React.useState<OrderRow[]>([]);
   const [loading, setLoading] = React.useState(true);
   const [error, setError] = React.useState<string | null>(null);
@@ -37,7 +37,7 @@ const MainContent: React.FC = () => {
   const [selectedOrderId, setSelectedOrderId] = React.useState<number | undefined>(undefined);
 
   const fetchOrders = React.useCallback(async () => {
-    if (!token) {
+    if (!isAuthenticated) {
       setError("No autenticado. Inicia sesión para ver tus órdenes.");
       setRows([]);
       setLoading(false);
@@ -46,11 +46,8 @@ const MainContent: React.FC = () => {
     setLoading(true);
     setError(null);
     try {
-      const res = await fetch(`${API_BASE}/api/orders?all=1`, {
+      const res = await authFetch(`${API_BASE}/api/orders?all=1`, {
         credentials: "include",
-        headers: {
-          Authorization: `Bearer ${token}`,
-        }
       });
       if (!res.ok) {
         const msg = await res.text();
@@ -63,7 +60,7 @@ const MainContent: React.FC = () => {
     } finally {
       setLoading(false);
     }
-  }, [token]);
+  }, [isAuthenticated, authFetch]);
 
   React.useEffect(() => {
     fetchOrders();
@@ -81,7 +81,7 @@ const dialogStyle: React.CSSProperties = {
 };
 
 const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, onSaved}) => {
-    const {token, userId, role, notify} = useAuth();
+    const {isAuthenticated, userId, role, notify, authFetch} = useAuth();
 
     const [addresses, setAddresses] = React.useState<Address[]>([]);
     const [pkgTypes, setPkgTypes] = React.useState<PackageType[]>([]);
@@ -89,6 +89,8 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
     const [loading, setLoading] = React.useState(false);
     const [saving, setSaving] = React.useState(false);
     const [detail, setDetail] = React.useState<OrderDetail | null>(null);
+    // Version of the order as returned in the ETag, sent back as If-Match on updates
+    const [etag, setEtag] = React.useState<string | null>(null);
 
     const isAdmin = role === "admin";
     const isView = mode === "view";
@@ -120,7 +122,7 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
 
     const fetchBaseData = React.useCallback(async (customerIdFromOrder?: number
     ) => {
-        if (!token) return;
+        if (!isAuthenticated) return;
 
         setLoading(true);
         try {
@@ -130,9 +132,9 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
             }
 
             const [addrRes, pkgRes, statusRes] = await Promise.all([
-                fetch(addressesUrl, {headers: {Authorization: `Bearer ${token}`}}),
-                fetch(`${API_BASE}/api/package-types`, {headers: {Authorization: `Bearer ${token}`}}),
-                fetch(`${API_BASE}/api/orders/status`, {headers: {Authorization: `Bearer ${token}`}}),
+                authFetch(addressesUrl),
+                authFetch(`${API_BASE}/api/package-types`),
+                authFetch(`${API_BASE}/api/orders/status`),
             ]);
 
             if (!addrRes.ok) throw new Error(await addrRes.text());
@@ -162,18 +164,19 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
         } finally {
             setLoading(false);
         }
-    }, [token, notify]);
+    }, [isAuthenticated, notify, authFetch]);
 
     const fetchDetail = React.useCallback(async () => {
-        if (!token || !orderId) return;
+        if (!isAuthenticated || !orderId) return;
 
         setLoading(true);
         try {
-            const res = await fetch(`${API_BASE}/api/orders/${orderId}`, {headers: {Authorization: `Bearer ${token}`}});
+            const res = await authFetch(`${API_BASE}/api/orders/${orderId}`);
             if (!res.ok) throw new Error(await res.text());
 
             const d = (await res.json()) as OrderDetail;
             setDetail(d);
+            setEtag(res.headers.get("ETag"));
             if (d.user_id !== userId) {
                 await fetchBaseData(d.user_id);
             }
@@ -193,7 +196,7 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
         } finally {
             setLoading(false);
         }
-    }, [token, orderId, notify]);
+    }, [isAuthenticated, orderId, notify, authFetch]);
 
     React.useEffect(() => {
         if (!open) return;
@@ -201,6 +204,7 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
 
         if (!isView) {
             setDetail(null);
+            setEtag(null);
             setForm({
                 quantity: 1,
                 actual_weight_kg: 0,
@@ -232,7 +236,7 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
     }, [form.actual_weight_kg, computePackageTypeId]);
 
     const handleCreate = async () => {
-        if (!token || !userId) {
+        if (!isAuthenticated || !userId) {
             notify({type: "danger", message: "No autenticado"});
             return;
         }
@@ -264,9 +268,9 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
                 updated_at: nowIso,
                 updated_by: 0
             };
-            const res = await fetch(`${API_BASE}/api/orders`, {
+            const res = await authFetch(`${API_BASE}/api/orders`, {
                 method: "POST",
-                headers: {"Content-Type": "application/json", Authorization: `Bearer ${token}`},
+                headers: {"Content-Type": "application/json"},
                 body: JSON.stringify(body),
             });
 
@@ -282,15 +286,24 @@ const OrderModal: React.FC<OrderModalProps> = ({open, mode, orderId, onClose, on
     };
 
     const handlePatchStatus = async () => {
-        if (!token || !isAdmin || !orderId) return;
+        if (!isAuthenticated || !isAdmin || !orderId) return;
 
         setSaving(true);
         try {
-            const res = await fetch(`${API_BASE}/api/orders/${orderId}/status`, {
+            const res = await authFetch(`${API_BASE}/api/orders/${orderId}/status`, {
                 method: "PATCH",
-                headers: {"Content-Type": "application/json", Authorization: `Bearer ${token}`},
+                headers: {
+                    "Content-Type": "application/json",
+                    ...(etag ? {"If-Match": etag} : {}),
+                },
                 body: JSON.stringify({internal_notes: isAdmin ? form.internal_notes : "", status: form.status}),
             });
+            if (res.status === 412) {
+                // Someone else changed the order since it was loaded
+                notify({type: "warning", message: "La orden fue modificada por otro usuario, se recargaron los datos"});
+                await fetchDetail();
+                return;
+            }
             if (!res.ok) throw new Error(await res.text());
             notify({type: "success", message: "Orden actualizada"});
             onClose();
@@ -66,7 +66,7 @@ const mapBoxStyle: React.CSSProperties = {
 };
 
 const UserProfileModal: React.FC<UserProfileModalProps> = ({ open, onClose }) => {
-  const { token, userId, notify } = useAuth();
+  const { isAuthenticated, userId, notify, authFetch } = useAuth();
 
   const [loading, setLoading] = React.useState(false);
   const [saving, setSaving] = React.useState(false);
@@ -107,12 +107,12 @@ const UserProfileModal: React.FC<UserProfileModalProps> = ({ open, onClose }) =>
   };
 
   const fetchUser = React.useCallback(async () => {
-    if (!token || !userId) return;
+    if (!isAuthenticated || !userId) return;
     setLoading(true);
     try {
       const [userRes, addrRes] = await Promise.all([
-        fetch(`${API_BASE}/api/users/${userId}`, { headers: { Authorization: `Bearer ${token}` } }),
-        fetch(`${API_BASE}/api/addresses`, { headers: { Authorization: `Bearer ${token}` } })
+        authFetch(`${API_BASE}/api/users/${userId}`),
+        authFetch(`${API_BASE}/api/addresses`)
       ]);
 
       if (!userRes.ok) throw new Error(await userRes.text());
@@ -129,7 +129,7 @@ const UserProfileModal: React.FC<UserProfileModalProps> = ({ open, onClose }) =>
     } finally {
       setLoading(false);
     }
-  }, [token, userId, notify]);
+  }, [isAuthenticated, userId, notify, authFetch]);
 
   React.useEffect(() => {
     if (open) {
@@ -228,7 +228,7 @@ const UserProfileModal: React.FC<UserProfileModalProps> = ({ open, onClose }) =>
   };
 
   const saveAddress = async () => {
-    if (!token || !userId) {
+    if (!isAuthenticated || !userId) {
       notify({ type: "danger", message: "No autenticado" });
       return;
     }
@@ -253,9 +253,9 @@ const UserProfileModal: React.FC<UserProfileModalProps> = ({ open, onClose }) =>
         },
         customer_id: userId,
       } as any;
-      const res = await fetch(`${API_BASE}/api/addresses`, {
+      const res = await authFetch(`${API_BASE}/api/addresses`, {
         method: "POST",
-        headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+        headers: { "Content-Type": "application/json" },
         body: JSON.stringify(body),
       });
       if (!res.ok) throw new Error(await res.text());
@@ -264,7 +264,7 @@ const UserProfileModal: React.FC<UserProfileModalProps> = ({ open, onClose }) =>
       goToCurrentLocation();
 
       // refresh
-      const listRes = await fetch(`${API_BASE}/api/addresses`, { headers: { Authorization: `Bearer ${token}` } });
+      const listRes = await authFetch(`${API_BASE}/api/addresses`);
       if (listRes.ok) {
         const addrs = (await listRes.json()) as Address[];
         const filtered = addrs.filter(a => (a as any).customer_id ? (a as any).customer_id === userId : true);